@@ -2,6 +2,7 @@ package task_engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ndizazzo/task-engine/internal/stopsignal"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -19,6 +21,70 @@ type contextKey string
 // GlobalContextKey is the key used to store the global context in the context
 const GlobalContextKey contextKey = "globalContext"
 
+// TaskPathKey is the key used to store the chain of enclosing task IDs in the context
+const TaskPathKey contextKey = "taskPath"
+
+// TaskPathFromContext returns the IDs of the tasks enclosing the current execution,
+// outermost first. It returns nil when the context is not inside a task.
+func TaskPathFromContext(ctx context.Context) []string {
+	path, _ := ctx.Value(TaskPathKey).([]string)
+	if len(path) == 0 {
+		return nil
+	}
+	return append([]string(nil), path...)
+}
+
+// StopSignals let code running inside a task started by a TaskManager tell a
+// requested stop apart from other cancellation. Command runners use them to
+// give processes the stop grace period: signaled when Stopping is closed and
+// killed when Kill is.
+type StopSignals = stopsignal.Signals
+
+// StopSignalsFromContext returns the StopSignals of the task run ctx belongs
+// to, or false outside a run started by a TaskManager
+func StopSignalsFromContext(ctx context.Context) (StopSignals, bool) {
+	return stopsignal.FromContext(ctx)
+}
+
+// ExecutionKey is the key used to store the current ExecutionInfo in the context
+const ExecutionKey contextKey = "execution"
+
+// ExecutionInfo identifies the task run, and the action within it, that a
+// context belongs to
+type ExecutionInfo struct {
+	TaskID   string `json:"taskId"`
+	RunID    string `json:"runId"`
+	ActionID string `json:"actionId,omitempty"`
+}
+
+// ExecutionFromContext returns the innermost task run and action executing with ctx
+func ExecutionFromContext(ctx context.Context) (ExecutionInfo, bool) {
+	info, ok := ctx.Value(ExecutionKey).(ExecutionInfo)
+	return info, ok
+}
+
+// withActionExecution records actionID as the action executing with ctx
+func withActionExecution(ctx context.Context, actionID string) context.Context {
+	info, _ := ExecutionFromContext(ctx)
+	info.ActionID = actionID
+	return context.WithValue(ctx, ExecutionKey, info)
+}
+
+// WithChildAction returns the context for running a child action, i.e. one
+// executed by the action running with ctx, such as a ParallelGroupAction
+// child. The child is recorded as the executing action, so audit entries and
+// policy checks name it, and its progress reports carry its ID.
+func WithChildAction(ctx context.Context, actionID string) context.Context {
+	ctx = withActionExecution(ctx, actionID)
+	if parent, ok := ProgressReporterFromContext(ctx); ok {
+		ctx = context.WithValue(ctx, ProgressKey, ProgressReporter(func(progress ActionProgress) {
+			progress.ActionID = actionID
+			parent(progress)
+		}))
+	}
+	return ctx
+}
+
 // ActionInterface defines the contract for actions
 type ActionInterface interface {
 	BeforeExecute(ctx context.Context) error
@@ -29,6 +95,13 @@ type ActionInterface interface {
 	GetOutput() interface{}
 }
 
+// StateChecker is implemented by actions that can tell, before executing,
+// whether their target is already in the desired state. When Check reports
+// true, Execute is skipped and the action's output reports "changed": false.
+type StateChecker interface {
+	Check(ctx context.Context) (inDesiredState bool, err error)
+}
+
 // ActionWithResults interface for actions that can optionally provide rich results
 type ActionWithResults interface {
 	ActionInterface
@@ -113,16 +186,20 @@ type GlobalContext struct {
 	ActionResults map[string]ResultProvider // Actions implementing ResultProvider
 	TaskOutputs   map[string]interface{}    // Outputs from completed tasks
 	TaskResults   map[string]ResultProvider // Tasks implementing ResultProvider
-	mu            sync.RWMutex              // Protects concurrent access
+	// TypedActionOutputs holds the TypedOutput values of actions implementing
+	// ActionWithTypedOutput
+	TypedActionOutputs map[string]interface{}
+	mu                 sync.RWMutex // Protects concurrent access
 }
 
 // NewGlobalContext creates a new GlobalContext instance
 func NewGlobalContext() *GlobalContext {
 	return &GlobalContext{
-		ActionOutputs: make(map[string]interface{}),
-		ActionResults: make(map[string]ResultProvider),
-		TaskOutputs:   make(map[string]interface{}),
-		TaskResults:   make(map[string]ResultProvider),
+		ActionOutputs:      make(map[string]interface{}),
+		ActionResults:      make(map[string]ResultProvider),
+		TaskOutputs:        make(map[string]interface{}),
+		TaskResults:        make(map[string]ResultProvider),
+		TypedActionOutputs: make(map[string]interface{}),
 	}
 }
 
@@ -147,6 +224,22 @@ func (gc *GlobalContext) StoreTaskOutput(taskID string, output interface{}) {
 	gc.TaskOutputs[taskID] = output
 }
 
+// GetActionOutput returns the stored output of an action
+func (gc *GlobalContext) GetActionOutput(actionID string) (interface{}, bool) {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+	output, ok := gc.ActionOutputs[actionID]
+	return output, ok
+}
+
+// GetTaskOutput returns the stored output of a task
+func (gc *GlobalContext) GetTaskOutput(taskID string) (interface{}, bool) {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+	output, ok := gc.TaskOutputs[taskID]
+	return output, ok
+}
+
 // StoreTaskResult stores the result provider from a task
 func (gc *GlobalContext) StoreTaskResult(taskID string, resultProvider ResultProvider) {
 	gc.mu.Lock()
@@ -342,7 +435,17 @@ type Action[T ActionInterface] struct {
 	EndTime   time.Time     // When execution completed
 	Duration  time.Duration // Total execution time
 	Logger    *slog.Logger  // Logger for the action
-	mu        sync.RWMutex  // Protects concurrent access to time fields
+	// ErrorPolicy controls whether the task aborts or continues when this action
+	// fails. The zero value behaves like ErrorPolicyFail.
+	ErrorPolicy ErrorPolicy
+	// Locks names resources this action needs exclusively, in addition to any
+	// declared by the wrapped action's ResourceLocks method
+	Locks []string
+	// skipped is set when Check found the target already in the desired state
+	skipped bool
+	// failedPhase is the lifecycle step that failed in the last run
+	failedPhase ActionPhase
+	mu          sync.RWMutex // Protects concurrent access to time fields
 }
 
 func (a *Action[T]) Execute(ctx context.Context) error {
@@ -374,12 +477,45 @@ func (a *Action[T]) GetName() string {
 	return a.ID
 }
 
-// GetOutput delegates to the wrapped action's GetOutput method
+// GetErrorPolicy returns the action's error policy, defaulting to ErrorPolicyFail
+func (a *Action[T]) GetErrorPolicy() ErrorPolicy {
+	if a.ErrorPolicy == "" {
+		return ErrorPolicyFail
+	}
+	return a.ErrorPolicy
+}
+
+// ResourceLocks returns the action's Locks together with those declared by the wrapped action
+func (a *Action[T]) ResourceLocks() []string {
+	return uniqueSorted(append(append([]string(nil), a.Locks...), collectResourceLocks(a.Wrapped)...))
+}
+
+// Skipped reports whether the last run skipped Execute because Check found
+// the target already in the desired state
+func (a *Action[T]) Skipped() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.skipped
+}
+
+// GetOutput delegates to the wrapped action's GetOutput method. When the last
+// run was skipped by Check, map outputs report "changed": false and "skipped": true.
 func (a *Action[T]) GetOutput() interface{} {
-	if actionWithOutput, ok := any(a.Wrapped).(interface{ GetOutput() interface{} }); ok {
-		return actionWithOutput.GetOutput()
+	actionWithOutput, ok := any(a.Wrapped).(interface{ GetOutput() interface{} })
+	if !ok {
+		return nil
 	}
-	return nil
+	output := actionWithOutput.GetOutput()
+	if m, isMap := output.(map[string]interface{}); isMap && a.Skipped() {
+		skippedOutput := make(map[string]interface{}, len(m)+2)
+		for k, v := range m {
+			skippedOutput[k] = v
+		}
+		skippedOutput["changed"] = false
+		skippedOutput["skipped"] = true
+		return skippedOutput
+	}
+	return output
 }
 
 func (a *Action[T]) InternalExecute(ctx context.Context) error {
@@ -390,6 +526,7 @@ func (a *Action[T]) InternalExecute(ctx context.Context) error {
 	a.mu.Lock()
 	a.RunID = uuid.New().String()
 	runID := a.RunID // Store locally to avoid race conditions in logging
+	a.failedPhase = ""
 	a.mu.Unlock()
 
 	a.log("Starting action", "actionID", a.ID, "runID", runID)
@@ -409,11 +546,42 @@ func (a *Action[T]) InternalExecute(ctx context.Context) error {
 
 	if err := a.Wrapped.BeforeExecute(execCtx); err != nil {
 		a.log("BeforeExecute failed", "actionID", a.ID, "runID", runID, "error", err)
+		a.setFailedPhase(ActionPhaseBeforeExecute)
 		return err
 	}
 
-	if err := a.Wrapped.Execute(execCtx); err != nil {
+	inDesiredState := false
+	if checker, ok := any(a.Wrapped).(StateChecker); ok {
+		var err error
+		if inDesiredState, err = checker.Check(execCtx); err != nil {
+			a.log("Check failed", "actionID", a.ID, "runID", runID, "error", err)
+			a.setFailedPhase(ActionPhaseCheck)
+			return fmt.Errorf("desired state check failed: %w", err)
+		}
+	}
+	a.mu.Lock()
+	a.skipped = inDesiredState
+	a.mu.Unlock()
+
+	if inDesiredState {
+		a.log("Already in desired state, skipping execution", "actionID", a.ID, "runID", runID)
+	} else if err := a.Wrapped.Execute(execCtx); err != nil {
 		a.log("Execute failed", "actionID", a.ID, "runID", runID, "error", err)
+		a.setFailedPhase(ActionPhaseExecute)
+		if execCtx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		// A canceled action still gets to clean up after itself
+		afterCtx := execCtx
+		if execCtx.Err() != nil {
+			var cancel context.CancelFunc
+			afterCtx, cancel = cleanupContext(execCtx, DefaultFinallyTimeout)
+			defer cancel()
+		}
+		if afterErr := a.Wrapped.AfterExecute(afterCtx); afterErr != nil {
+			a.log("AfterExecute failed", "actionID", a.ID, "runID", runID, "error", afterErr)
+			return errors.Join(err, afterErr)
+		}
 		return err
 	}
 
@@ -427,6 +595,7 @@ func (a *Action[T]) InternalExecute(ctx context.Context) error {
 
 	if err := a.Wrapped.AfterExecute(execCtx); err != nil {
 		a.log("AfterExecute failed", "actionID", a.ID, "runID", runID, "error", err)
+		a.setFailedPhase(ActionPhaseAfterExecute)
 		return err
 	}
 
@@ -434,6 +603,20 @@ func (a *Action[T]) InternalExecute(ctx context.Context) error {
 	return nil
 }
 
+func (a *Action[T]) setFailedPhase(phase ActionPhase) {
+	a.mu.Lock()
+	a.failedPhase = phase
+	a.mu.Unlock()
+}
+
+// FailedPhase returns the lifecycle step that failed in the last run, or ""
+// when it succeeded
+func (a *Action[T]) FailedPhase() ActionPhase {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.failedPhase
+}
+
 func (a *Action[T]) log(message string, keyvals ...interface{}) {
 	if a.Logger != nil {
 		a.Logger.Info(message, keyvals...)
@@ -77,6 +77,45 @@ func (a *AfterExecuteFailingAction) AfterExecute(ctx context.Context) error {
 	return nil
 }
 
+// cleanupAction blocks until canceled and records its AfterExecute cleanup
+type cleanupAction struct {
+	BaseAction
+	CleanedUp  bool
+	CleanupErr error
+}
+
+func (a *cleanupAction) Execute(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (a *cleanupAction) AfterExecute(ctx context.Context) error {
+	a.CleanedUp = true
+	a.CleanupErr = ctx.Err()
+	return nil
+}
+
+// desiredStateAction implements StateChecker for testing skipped executions
+type desiredStateAction struct {
+	BaseAction
+	InState  bool
+	CheckErr error
+	Executed bool
+}
+
+func (a *desiredStateAction) Check(ctx context.Context) (bool, error) {
+	return a.InState, a.CheckErr
+}
+
+func (a *desiredStateAction) Execute(ctx context.Context) error {
+	a.Executed = true
+	return nil
+}
+
+func (a *desiredStateAction) GetOutput() interface{} {
+	return map[string]interface{}{"success": true, "changed": a.Executed}
+}
+
 // ActionTestSuite contains all the action tests
 type ActionTestSuite struct {
 	suite.Suite
@@ -187,6 +226,19 @@ func (suite *ActionTestSuite) TestAction_AfterExecuteFailure() {
 	suite.Contains(err.Error(), "simulated AfterExecute failure", "Error should contain AfterExecute failure message")
 }
 
+// TestAction_AfterExecuteRunsOnCancellation tests that a canceled action still cleans up
+func (suite *ActionTestSuite) TestAction_AfterExecuteRunsOnCancellation() {
+	action := &Action[*cleanupAction]{ID: "cleanup-action", Wrapped: &cleanupAction{}}
+	ctx, cancel := context.WithTimeout(testContext(), 10*time.Millisecond)
+	defer cancel()
+
+	err := action.Execute(ctx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal(ActionPhaseExecute, action.FailedPhase())
+	suite.True(action.Wrapped.CleanedUp, "AfterExecute runs for a canceled action")
+	suite.NoError(action.Wrapped.CleanupErr, "cleanup gets a context that is not canceled")
+}
+
 // TestAction_GetLogger tests logger access
 func (suite *ActionTestSuite) TestAction_GetLogger() {
 	action := &Action[*TestAction]{
@@ -780,3 +832,28 @@ func testContext() context.Context {
 func NewDiscardLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
+
+func (suite *ActionTestSuite) TestAction_CheckSkipsExecute() {
+	wrapped := &desiredStateAction{InState: true}
+	action := &Action[*desiredStateAction]{ID: "checked", Wrapped: wrapped}
+
+	suite.NoError(action.Execute(testContext()))
+	suite.False(wrapped.Executed)
+	suite.True(action.Skipped())
+	suite.Equal(map[string]interface{}{"success": true, "changed": false, "skipped": true}, action.GetOutput())
+
+	wrapped.InState = false
+	suite.NoError(action.Execute(testContext()))
+	suite.True(wrapped.Executed)
+	suite.False(action.Skipped())
+	suite.Equal(map[string]interface{}{"success": true, "changed": true}, action.GetOutput())
+}
+
+func (suite *ActionTestSuite) TestAction_CheckErrorFailsAction() {
+	wrapped := &desiredStateAction{CheckErr: fmt.Errorf("stat failed")}
+	action := &Action[*desiredStateAction]{ID: "checked", Wrapped: wrapped}
+
+	err := action.Execute(testContext())
+	suite.ErrorContains(err, "desired state check failed: stat failed")
+	suite.False(wrapped.Executed)
+}
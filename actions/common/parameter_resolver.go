@@ -177,6 +177,25 @@ func (pr *ParameterResolver) ResolveMapParameter(
 	return nil, fmt.Errorf("%s parameter resolved to non-map value: %T", paramName, value)
 }
 
+// StaticString returns the value of a StaticParameter holding a string, for
+// use before execution when other parameters cannot be resolved yet
+func StaticString(param task_engine.ActionParameter) (string, bool) {
+	var value interface{}
+	switch p := param.(type) {
+	case task_engine.StaticParameter:
+		value = p.Value
+	case *task_engine.StaticParameter:
+		if p == nil {
+			return "", false
+		}
+		value = p.Value
+	default:
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
 // ResolveSliceParameter resolves a parameter and converts it to a slice
 func (pr *ParameterResolver) ResolveSliceParameter(
 	ctx context.Context,
@@ -180,5 +180,6 @@ func (a *CheckContainerHealthAction) GetOutput() interface{} {
 		"maxRetries": a.ResolvedMaxRetries,
 		"retryDelay": a.ResolvedRetryDelay.String(),
 		"workingDir": a.ResolvedWorkingDir,
+		"changed":    false,
 	})
 }
@@ -25,6 +25,8 @@ type DockerComposeDownAction struct {
 	// Resolved/output fields
 	ResolvedWorkingDir string
 	ResolvedServices   []string
+	// Changed is true when the last run stopped the services
+	Changed bool
 }
 
 // SetCommandRunner allows injecting a mock or alternative CommandRunner for testing.
@@ -56,6 +58,7 @@ func (a *DockerComposeDownAction) WithParameters(workingDirParam, servicesParam
 }
 
 func (a *DockerComposeDownAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve working directory parameter
 	var workingDir string
 	if a.WorkingDirParam != nil {
@@ -108,10 +111,22 @@ func (a *DockerComposeDownAction) Execute(execCtx context.Context) error {
 	}
 
 	a.Logger.Info("Docker compose down finished successfully", "output", output)
+	a.Changed = true
+	return nil
+}
+
+// ResourceLocks serializes compose operations on the same project directory.
+// Only a static working directory can be known before the action runs.
+func (a *DockerComposeDownAction) ResourceLocks() []string {
+	if dir, ok := common.StaticString(a.WorkingDirParam); ok && dir != "" {
+		return []string{"compose:" + dir}
+	}
 	return nil
 }
 
 // GetOutput returns details about the compose down execution
 func (a *DockerComposeDownAction) GetOutput() interface{} {
-	return a.BuildStandardOutput(nil, true, nil)
+	return a.BuildStandardOutput(nil, true, map[string]interface{}{
+		"changed": a.Changed,
+	})
 }
@@ -37,6 +37,9 @@ type DockerComposeExecAction struct {
 	ResolvedWorkingDir  string
 	ResolvedService     string
 	ResolvedCommandArgs []string
+	Result              command.CommandResult
+	// Changed is true when the last run ran the command
+	Changed bool
 }
 
 // SetCommandRunner allows injecting a mock or alternative CommandRunner for testing.
@@ -60,6 +63,7 @@ func (a *DockerComposeExecAction) WithParameters(
 }
 
 func (a *DockerComposeExecAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve working directory parameter
 	workingDirValue, err := a.ResolveStringParameter(execCtx, a.WorkingDirParam, "working directory")
 	if err != nil {
@@ -92,26 +96,30 @@ func (a *DockerComposeExecAction) Execute(execCtx context.Context) error {
 
 	a.Logger.Info("Executing docker compose exec", "service", a.ResolvedService, "command", a.ResolvedCommandArgs, "workingDir", a.ResolvedWorkingDir)
 
-	var output string
-	if a.ResolvedWorkingDir != "" {
-		output, err = a.commandRunner.RunCommandInDirWithContext(execCtx, a.ResolvedWorkingDir, "docker", args...)
-	} else {
-		output, err = a.commandRunner.RunCommandWithContext(execCtx, "docker", args...)
-	}
+	a.Result, err = command.RunWithOptions(execCtx, a.commandRunner, command.Cmd{
+		Name: "docker",
+		Args: args,
+		Dir:  a.ResolvedWorkingDir,
+	})
+	output := a.Result.Stdout + a.Result.Stderr
 
 	if err != nil {
-		a.Logger.Error("Failed to run docker compose exec", "error", err, "output", output)
+		a.Logger.Error("Failed to run docker compose exec", "error", err, "exitCode", a.Result.ExitCode, "output", output)
 		return fmt.Errorf("failed to run docker compose exec on service %s with command %v in dir %s: %w. Output: %s", a.ResolvedService, a.ResolvedCommandArgs, a.ResolvedWorkingDir, err, output)
 	}
 	a.Logger.Info("Docker compose exec finished successfully", "output", output)
+	a.Changed = true
 	return nil
 }
 
 // GetOutput returns details about the compose exec execution
 func (a *DockerComposeExecAction) GetOutput() interface{} {
-	return a.BuildStandardOutput(nil, true, map[string]interface{}{
+	return a.BuildStandardOutput(strings.TrimSpace(a.Result.Stdout), a.Result.Success(), map[string]interface{}{
 		"service":    a.ResolvedService,
 		"workingDir": a.ResolvedWorkingDir,
 		"command":    a.ResolvedCommandArgs,
+		"stderr":     a.Result.Stderr,
+		"exitCode":   a.Result.ExitCode,
+		"changed":    a.Changed,
 	})
 }
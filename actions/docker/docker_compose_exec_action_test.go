@@ -8,6 +8,7 @@ import (
 
 	task_engine "github.com/ndizazzo/task-engine"
 	"github.com/ndizazzo/task-engine/actions/docker"
+	"github.com/ndizazzo/task-engine/command"
 	command_mock "github.com/ndizazzo/task-engine/testing/mocks"
 	"github.com/stretchr/testify/suite"
 )
@@ -538,3 +539,29 @@ func (suite *DockerComposeExecTestSuite) TestDockerComposeExecAction_GetOutput()
 func TestDockerComposeExecTestSuite(t *testing.T) {
 	suite.Run(t, new(DockerComposeExecTestSuite))
 }
+
+func (suite *DockerComposeExecTestSuite) TestExecuteSurfacesExitCodeAndStderr() {
+	action, err := docker.NewDockerComposeExecAction(suite.logger).WithParameters(
+		task_engine.StaticParameter{Value: "/tmp/exec-test"},
+		task_engine.StaticParameter{Value: "db"},
+		task_engine.StaticParameter{Value: []string{"psql", "-c", "select 1"}},
+	)
+	suite.Require().NoError(err)
+	action.Wrapped.SetCommandRunner(suite.mockRunner)
+
+	suite.mockRunner.On("RunCommandWithOptions", context.Background(), command.Cmd{
+		Name: "docker",
+		Args: []string{"compose", "exec", "db", "psql", "-c", "select 1"},
+		Dir:  "/tmp/exec-test",
+	}).Return(command.CommandResult{Stderr: "psql: connection refused\n", ExitCode: 2}, fmt.Errorf("exit status 2")).Once()
+
+	execErr := action.Wrapped.Execute(context.Background())
+	suite.Error(execErr)
+	suite.ErrorContains(execErr, "connection refused")
+
+	output := action.Wrapped.GetOutput().(map[string]interface{})
+	suite.Equal(2, output["exitCode"])
+	suite.Equal("psql: connection refused\n", output["stderr"])
+	suite.Equal(false, output["success"])
+	suite.mockRunner.AssertExpectations(suite.T())
+}
@@ -18,6 +18,12 @@ type ComposeStack struct {
 	ConfigFiles string
 }
 
+// DockerComposeLsOutput holds the stacks listed by DockerComposeLsAction
+type DockerComposeLsOutput struct {
+	Stacks []ComposeStack
+	Output string
+}
+
 // DockerComposeLsConfig holds configuration for Docker Compose ls action
 type DockerComposeLsConfig struct {
 	All        bool
@@ -200,7 +206,7 @@ func (a *DockerComposeLsAction) Execute(execCtx context.Context) error {
 		"workingDir", a.WorkingDir,
 	)
 
-	output, err := a.CommandProcessor.RunCommand("docker", args...)
+	output, err := a.CommandProcessor.RunCommandWithContext(execCtx, "docker", args...)
 	if err != nil {
 		a.Logger.Error("Failed to list Docker Compose stacks", "error", err.Error(), "output", output)
 		return fmt.Errorf("failed to list Docker Compose stacks: %w", err)
@@ -222,11 +228,22 @@ func (a *DockerComposeLsAction) Execute(execCtx context.Context) error {
 // using ActionOutputParameter references.
 func (a *DockerComposeLsAction) GetOutput() interface{} {
 	return a.BuildOutputWithCount(a.Stacks, true, map[string]interface{}{
-		"stacks": a.Stacks,
-		"output": a.Output,
+		"stacks":  a.Stacks,
+		"output":  a.Output,
+		"changed": false,
 	})
 }
 
+var _ task_engine.ActionWithTypedOutput[DockerComposeLsOutput] = (*DockerComposeLsAction)(nil)
+
+// TypedOutput returns the same results as GetOutput as a DockerComposeLsOutput
+func (a *DockerComposeLsAction) TypedOutput() DockerComposeLsOutput {
+	return DockerComposeLsOutput{
+		Stacks: a.Stacks,
+		Output: a.Output,
+	}
+}
+
 // parseStacks parses the docker compose ls output and populates the Stacks slice
 func (a *DockerComposeLsAction) parseStacks(output string) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
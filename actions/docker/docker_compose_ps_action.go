@@ -20,6 +20,12 @@ type ComposeService struct {
 	Ports       string
 }
 
+// DockerComposePsOutput holds the services listed by DockerComposePsAction
+type DockerComposePsOutput struct {
+	Services []ComposeService
+	Output   string
+}
+
 // DockerComposePsActionWrapper provides a consistent interface for DockerComposePsAction
 type DockerComposePsActionWrapper struct {
 	ID      string
@@ -231,7 +237,7 @@ func (a *DockerComposePsAction) Execute(execCtx context.Context) error {
 		"workingDir", a.WorkingDir,
 	)
 
-	output, err := a.CommandProcessor.RunCommand("docker", args...)
+	output, err := a.CommandProcessor.RunCommandWithContext(execCtx, "docker", args...)
 	if err != nil {
 		a.Logger.Error("Failed to list Docker Compose services", "error", err.Error(), "output", output)
 		return fmt.Errorf("failed to list Docker Compose services: %w", err)
@@ -253,9 +259,20 @@ func (a *DockerComposePsAction) GetOutput() interface{} {
 	return a.BuildOutputWithCount(a.ServicesList, true, map[string]interface{}{
 		"services": a.ServicesList,
 		"output":   a.Output,
+		"changed":  false,
 	})
 }
 
+var _ task_engine.ActionWithTypedOutput[DockerComposePsOutput] = (*DockerComposePsAction)(nil)
+
+// TypedOutput returns the same results as GetOutput as a DockerComposePsOutput
+func (a *DockerComposePsAction) TypedOutput() DockerComposePsOutput {
+	return DockerComposePsOutput{
+		Services: a.ServicesList,
+		Output:   a.Output,
+	}
+}
+
 // parseServices parses the docker compose ps output and populates the ServicesList slice
 func (a *DockerComposePsAction) parseServices(output string) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
@@ -25,6 +25,8 @@ type DockerComposeUpAction struct {
 	// Resolved/output fields
 	ResolvedWorkingDir string
 	ResolvedServices   []string
+	// Changed is false when compose reported every container already running
+	Changed bool
 }
 
 // SetCommandRunner allows injecting a mock or alternative CommandRunner for testing.
@@ -56,6 +58,7 @@ func (a *DockerComposeUpAction) WithParameters(workingDirParam, servicesParam ta
 }
 
 func (a *DockerComposeUpAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve working directory parameter
 	workingDirValue, err := a.ResolveStringParameter(execCtx, a.WorkingDirParam, "working directory")
 	if err != nil {
@@ -97,6 +100,33 @@ func (a *DockerComposeUpAction) Execute(execCtx context.Context) error {
 		return fmt.Errorf("failed to run docker compose up for services %v in dir %s: %w. Output: %s", a.ResolvedServices, a.ResolvedWorkingDir, err, output)
 	}
 	a.Logger.Info("Docker compose up finished successfully", "output", output)
+	a.Changed = !composeUpToDate(output)
+	return nil
+}
+
+// composeUpToDate reports whether compose up output only lists resources that
+// were already running. Empty output is treated as a change.
+func composeUpToDate(output string) bool {
+	seen := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasSuffix(line, "Running") {
+			return false
+		}
+		seen = true
+	}
+	return seen
+}
+
+// ResourceLocks serializes compose operations on the same project directory.
+// Only a static working directory can be known before the action runs.
+func (a *DockerComposeUpAction) ResourceLocks() []string {
+	if dir, ok := common.StaticString(a.WorkingDirParam); ok && dir != "" {
+		return []string{"compose:" + dir}
+	}
 	return nil
 }
 
@@ -105,5 +135,6 @@ func (a *DockerComposeUpAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, true, map[string]interface{}{
 		"services":   a.ResolvedServices,
 		"workingDir": a.ResolvedWorkingDir,
+		"changed":    a.Changed,
 	})
 }
@@ -58,9 +58,28 @@ func (suite *DockerComposeUpTestSuite) TestExecuteSuccessWithServices() {
 	execErr := action.Wrapped.Execute(context.Background())
 
 	suite.NoError(execErr)
+	suite.True(action.Wrapped.Changed)
 	suite.mockProcessor.AssertExpectations(suite.T())
 }
 
+func (suite *DockerComposeUpTestSuite) TestExecuteAlreadyRunning() {
+	logger := command_mock.NewDiscardLogger()
+
+	action, err := docker.NewDockerComposeUpAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: testUpWorkingDir},
+		task_engine.StaticParameter{Value: []string{"web", "db"}},
+	)
+	suite.Require().NoError(err)
+	action.Wrapped.SetCommandRunner(suite.mockProcessor)
+
+	suite.mockProcessor.On("RunCommandInDirWithContext", context.Background(), testUpWorkingDir, "docker", "compose", "up", "-d", "web", "db").
+		Return(" Container up-test-db-1  Running\n Container up-test-web-1  Running\n", nil)
+
+	suite.Require().NoError(action.Wrapped.Execute(context.Background()))
+	suite.False(action.Wrapped.Changed)
+	suite.Equal(false, action.Wrapped.GetOutput().(map[string]interface{})["changed"])
+}
+
 func (suite *DockerComposeUpTestSuite) TestExecuteCommandFailure() {
 	logger := command_mock.NewDiscardLogger()
 	services := []string{"web"}
@@ -122,6 +141,19 @@ func (suite *DockerComposeUpTestSuite) TestNewDockerComposeUpActionWithParams()
 	// Resolved values computed at execution time
 }
 
+func (suite *DockerComposeUpTestSuite) TestResourceLocks() {
+	logger := command_mock.NewDiscardLogger()
+
+	action, err := docker.NewDockerComposeUpAction(logger).WithParameters(task_engine.StaticParameter{Value: "/srv/app"}, task_engine.StaticParameter{Value: []string{}})
+	suite.Require().NoError(err)
+	suite.Equal([]string{"compose:/srv/app"}, action.ResourceLocks())
+
+	// A directory that is only known at execution time cannot be locked up front
+	action, err = docker.NewDockerComposeUpAction(logger).WithParameters(task_engine.ActionOutputField("setup", "dir"), task_engine.StaticParameter{Value: []string{}})
+	suite.Require().NoError(err)
+	suite.Empty(action.ResourceLocks())
+}
+
 // ===== PARAMETER RESOLUTION TESTS =====
 
 func (suite *DockerComposeUpTestSuite) TestExecute_WithStaticParameters() {
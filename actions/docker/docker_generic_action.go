@@ -47,12 +47,15 @@ type DockerGenericAction struct {
 	DockerCmd        []string
 	CommandProcessor command.CommandRunner
 	Output           string
+	// Changed is true when the last run ran the command
+	Changed bool
 
 	// Parameter-aware fields
 	DockerCmdParam task_engine.ActionParameter
 }
 
 func (a *DockerGenericAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve docker command parameter if it exists
 	if a.DockerCmdParam != nil {
 		dockerCmdValue, err := a.ResolveParameter(execCtx, a.DockerCmdParam, "docker command")
@@ -70,7 +73,7 @@ func (a *DockerGenericAction) Execute(execCtx context.Context) error {
 	}
 
 	a.Logger.Info("Executing docker command", "command", a.DockerCmd)
-	output, err := a.CommandProcessor.RunCommand("docker", a.DockerCmd...)
+	output, err := a.CommandProcessor.RunCommandWithContext(execCtx, "docker", a.DockerCmd...)
 	a.Output = strings.TrimSpace(output)
 
 	if err != nil {
@@ -78,6 +81,7 @@ func (a *DockerGenericAction) Execute(execCtx context.Context) error {
 		return fmt.Errorf("failed to run docker command %v: %w. Output: %s", a.DockerCmd, err, output)
 	}
 	a.Logger.Info("Docker command finished successfully", "output", a.Output)
+	a.Changed = true
 	return nil
 }
 
@@ -86,5 +90,6 @@ func (a *DockerGenericAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, a.Output != "", map[string]interface{}{
 		"command": a.DockerCmd,
 		"output":  a.Output,
+		"changed": a.Changed,
 	})
 }
@@ -1,14 +1,18 @@
 package docker_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"strings"
 	"testing"
 
 	task_engine "github.com/ndizazzo/task-engine"
 	"github.com/ndizazzo/task-engine/actions/docker"
+	"github.com/ndizazzo/task-engine/command"
 	command_mock "github.com/ndizazzo/task-engine/testing/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -52,7 +56,7 @@ func (suite *DockerGenericActionTestSuite) TestNewDockerGenericActionConstructor
 	suite.Require().NoError(err)
 	action.Wrapped.CommandProcessor = suite.mockProcessor
 
-	suite.mockProcessor.On("RunCommand", "docker", "network", "ls", "-q", "--filter", "name=test_net").Return(expectedOutput+"\n", nil)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "network", "ls", "-q", "--filter", "name=test_net").Return(expectedOutput+"\n", nil)
 
 	err = action.Wrapped.Execute(context.Background())
 
@@ -75,7 +79,7 @@ func (suite *DockerGenericActionTestSuite) TestNewDockerGenericActionConstructor
 	suite.Require().NoError(err)
 	action.Wrapped.CommandProcessor = suite.mockProcessor
 
-	suite.mockProcessor.On("RunCommand", "docker", "network", "ls", "-q", "--filter", "name=test_net").Return(expectedOutput+"\n", nil)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "network", "ls", "-q", "--filter", "name=test_net").Return(expectedOutput+"\n", nil)
 
 	err = action.Wrapped.Execute(context.Background())
 
@@ -117,7 +121,7 @@ func (suite *DockerGenericActionTestSuite) TestNewDockerGenericActionConstructor
 	suite.Require().NoError(err)
 	action.Wrapped.CommandProcessor = suite.mockProcessor
 
-	suite.mockProcessor.On("RunCommand", "docker", "info", "--invalid-flag").Return(expectedOutput, assert.AnError)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "info", "--invalid-flag").Return(expectedOutput, assert.AnError)
 
 	err = action.Wrapped.Execute(context.Background())
 
@@ -141,6 +145,65 @@ func (suite *DockerGenericActionTestSuite) TestDockerGenericAction_GetOutput() {
 	suite.Equal(true, m["success"])
 }
 
+func (suite *DockerGenericActionTestSuite) TestDockerGenericAction_AuditEntryCarriesExecutionIDs() {
+	action, err := docker.NewDockerGenericAction(command_mock.NewDiscardLogger()).WithParameters(
+		task_engine.StaticParameter{Value: []string{"network", "ls"}},
+	)
+	suite.Require().NoError(err)
+	var log bytes.Buffer
+	action.Wrapped.CommandProcessor = command.NewAuditCommandRunner(suite.mockProcessor, &log)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "network", "ls").Return("bridge\n", nil)
+
+	task := &task_engine.Task{
+		ID:      "networks",
+		Name:    "List networks",
+		Logger:  command_mock.NewDiscardLogger(),
+		Actions: []task_engine.ActionWrapper{action},
+	}
+	suite.Require().NoError(task.Run(context.Background()))
+
+	var entry command.AuditEntry
+	suite.Require().NoError(json.Unmarshal(log.Bytes(), &entry))
+	suite.Equal("networks", entry.TaskID)
+	suite.Equal(task.RunID, entry.RunID)
+	suite.Equal("docker-generic-action", entry.ActionID)
+	suite.Equal([]string{"network", "ls"}, entry.Args)
+}
+
+func (suite *DockerGenericActionTestSuite) TestDockerGenericAction_PolicyMatchesTaskRules() {
+	policy, err := command.ParseCommandPolicy([]byte(`
+default: deny
+rules:
+  - name: networks-may-list
+    effect: allow
+    binary: docker
+    tasks: ["networks"]
+`))
+	suite.Require().NoError(err)
+	runner, err := command.NewPolicyCommandRunner(suite.mockProcessor, policy)
+	suite.Require().NoError(err)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "network", "ls").Return("bridge\n", nil)
+
+	newTask := func(id string) *task_engine.Task {
+		action, err := docker.NewDockerGenericAction(command_mock.NewDiscardLogger()).WithParameters(
+			task_engine.StaticParameter{Value: []string{"network", "ls"}},
+		)
+		suite.Require().NoError(err)
+		action.Wrapped.CommandProcessor = runner
+		return &task_engine.Task{
+			ID:      id,
+			Name:    id,
+			Logger:  command_mock.NewDiscardLogger(),
+			Actions: []task_engine.ActionWrapper{action},
+		}
+	}
+
+	suite.NoError(newTask("networks").Run(context.Background()))
+	err = newTask("cleanup").Run(context.Background())
+	suite.ErrorIs(err, command.ErrCommandDenied)
+	suite.mockProcessor.AssertNumberOfCalls(suite.T(), "RunCommandWithContext", 1)
+}
+
 func TestDockerGenericTestSuite(t *testing.T) {
 	suite.Run(t, new(DockerGenericActionTestSuite))
 }
@@ -20,6 +20,12 @@ type DockerImage struct {
 	Created    string
 }
 
+// DockerImageListOutput holds the images listed by DockerImageListAction
+type DockerImageListOutput struct {
+	Images []DockerImage
+	Output string
+}
+
 // DockerImageListActionConstructor provides the new constructor pattern
 type DockerImageListActionConstructor struct {
 	common.BaseConstructor[*DockerImageListAction]
@@ -233,7 +239,7 @@ func (a *DockerImageListAction) Execute(execCtx context.Context) error {
 		"quiet", a.Quiet,
 	)
 
-	output, err := a.CommandProcessor.RunCommand("docker", args...)
+	output, err := a.CommandProcessor.RunCommandWithContext(execCtx, "docker", args...)
 	if err != nil {
 		a.Logger.Error("Failed to list Docker images", "error", err.Error(), "output", output)
 		return fmt.Errorf("failed to list Docker images: %w", err)
@@ -253,11 +259,22 @@ func (a *DockerImageListAction) Execute(execCtx context.Context) error {
 // GetOutput returns parsed image information and raw output metadata
 func (a *DockerImageListAction) GetOutput() interface{} {
 	return a.BuildOutputWithCount(a.Images, true, map[string]interface{}{
-		"images": a.Images,
-		"output": a.Output,
+		"images":  a.Images,
+		"output":  a.Output,
+		"changed": false,
 	})
 }
 
+var _ task_engine.ActionWithTypedOutput[DockerImageListOutput] = (*DockerImageListAction)(nil)
+
+// TypedOutput returns the same results as GetOutput as a DockerImageListOutput
+func (a *DockerImageListAction) TypedOutput() DockerImageListOutput {
+	return DockerImageListOutput{
+		Images: a.Images,
+		Output: a.Output,
+	}
+}
+
 // parseImages parses the docker image ls output and populates the Images slice
 func (a *DockerImageListAction) parseImages(output string) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
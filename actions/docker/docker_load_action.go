@@ -100,6 +100,8 @@ type DockerLoadAction struct {
 	CommandProcessor command.CommandRunner
 	Output           string
 	LoadedImages     []string // Stores the names of loaded images
+	// Changed is true when the last run loaded the archive
+	Changed bool
 
 	// Parameter-aware fields
 	TarFilePathParam task_engine.ActionParameter
@@ -111,6 +113,7 @@ func (a *DockerLoadAction) SetCommandRunner(runner command.CommandRunner) {
 }
 
 func (a *DockerLoadAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve tar file path parameter if it exists
 	if a.TarFilePathParam != nil {
 		tarFilePathValue, err := a.ResolveStringParameter(execCtx, a.TarFilePathParam, "tar file path")
@@ -134,7 +137,7 @@ func (a *DockerLoadAction) Execute(execCtx context.Context) error {
 	}
 
 	a.Logger.Info("Executing docker load", "tarFile", a.TarFilePath, "platform", a.Platform, "quiet", a.Quiet)
-	output, err := a.CommandProcessor.RunCommand("docker", args...)
+	output, err := a.CommandProcessor.RunCommandWithContext(execCtx, "docker", args...)
 	a.Output = output
 
 	if err != nil {
@@ -146,6 +149,7 @@ func (a *DockerLoadAction) Execute(execCtx context.Context) error {
 	a.parseLoadedImages(output)
 
 	a.Logger.Info("Docker load finished successfully", "loadedImages", a.LoadedImages, "output", a.Output)
+	a.Changed = true
 	return nil
 }
 
@@ -155,6 +159,7 @@ func (a *DockerLoadAction) GetOutput() interface{} {
 		"loadedImages": a.LoadedImages,
 		"output":       a.Output,
 		"tarFile":      a.TarFilePath,
+		"changed":      a.Changed,
 	})
 }
 
@@ -22,6 +22,12 @@ type Container struct {
 	Names       string
 }
 
+// DockerPsOutput holds the containers listed by DockerPsAction
+type DockerPsOutput struct {
+	Containers []Container
+	RawOutput  string
+}
+
 // DockerPsOption is a function type for configuring DockerPsAction
 type DockerPsOption func(*DockerPsAction)
 
@@ -263,7 +269,7 @@ func (a *DockerPsAction) Execute(execCtx context.Context) error {
 		"size", a.Size,
 	)
 
-	output, err := a.CommandProcessor.RunCommand("docker", args...)
+	output, err := a.CommandProcessor.RunCommandWithContext(execCtx, "docker", args...)
 	if err != nil {
 		a.Logger.Error("Failed to list Docker containers", "error", err.Error(), "output", output)
 		return fmt.Errorf("failed to list Docker containers: %w", err)
@@ -285,9 +291,20 @@ func (a *DockerPsAction) GetOutput() interface{} {
 	return a.BuildOutputWithCount(a.Containers, true, map[string]interface{}{
 		"containers": a.Containers,
 		"rawOutput":  a.Output,
+		"changed":    false,
 	})
 }
 
+var _ task_engine.ActionWithTypedOutput[DockerPsOutput] = (*DockerPsAction)(nil)
+
+// TypedOutput returns the same results as GetOutput as a DockerPsOutput
+func (a *DockerPsAction) TypedOutput() DockerPsOutput {
+	return DockerPsOutput{
+		Containers: a.Containers,
+		RawOutput:  a.Output,
+	}
+}
+
 // parseContainers parses the docker ps output and populates the Containers slice
 func (a *DockerPsAction) parseContainers(output string) {
 	lines := strings.Split(strings.TrimSpace(output), "\n")
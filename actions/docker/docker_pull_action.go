@@ -129,6 +129,13 @@ func WithPullPlatform(platform string) DockerPullOption {
 	}
 }
 
+// WithPullOutputHandler forwards each line of docker pull output to handler
+func WithPullOutputHandler(handler command.LineHandler) DockerPullOption {
+	return func(a *DockerPullAction) {
+		a.OutputHandler = handler
+	}
+}
+
 type DockerPullAction struct {
 	task_engine.BaseAction
 	common.ParameterResolver
@@ -142,6 +149,11 @@ type DockerPullAction struct {
 	Output           string
 	PulledImages     []string
 	FailedImages     []string
+	// Changed is true when any pull downloaded a newer image
+	Changed bool
+	// OutputHandler optionally receives every line of docker pull output
+	OutputHandler command.LineHandler
+	progress      task_engine.ActionProgress
 
 	// Parameter-aware fields
 	ImagesParam          task_engine.ActionParameter
@@ -156,6 +168,7 @@ func (a *DockerPullAction) SetCommandRunner(runner command.CommandRunner) {
 }
 
 func (a *DockerPullAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve images via parameter if provided
 	if a.ImagesParam != nil {
 		v, err := a.ResolveParameter(execCtx, a.ImagesParam, "images")
@@ -281,6 +294,9 @@ func (a *DockerPullAction) Execute(execCtx context.Context) error {
 	a.PulledImages = []string{}
 	a.FailedImages = []string{}
 
+	a.progress = task_engine.ActionProgress{Total: int64(totalImages), Unit: "images"}
+	task_engine.ReportActionProgress(execCtx, a.progress)
+
 	for name, spec := range a.Images {
 		if err := a.pullImage(execCtx, name, spec); err != nil {
 			a.FailedImages = append(a.FailedImages, name)
@@ -288,6 +304,8 @@ func (a *DockerPullAction) Execute(execCtx context.Context) error {
 		} else {
 			a.PulledImages = append(a.PulledImages, name)
 		}
+		a.progress.Current = int64(len(a.PulledImages) + len(a.FailedImages))
+		task_engine.ReportActionProgress(execCtx, a.progress)
 	}
 
 	for name, spec := range a.MultiArchImages {
@@ -297,6 +315,8 @@ func (a *DockerPullAction) Execute(execCtx context.Context) error {
 		} else {
 			a.PulledImages = append(a.PulledImages, name)
 		}
+		a.progress.Current = int64(len(a.PulledImages) + len(a.FailedImages))
+		task_engine.ReportActionProgress(execCtx, a.progress)
 	}
 
 	a.Output = fmt.Sprintf("Pulled %d images, failed %d images", len(a.PulledImages), len(a.FailedImages))
@@ -330,10 +350,11 @@ func (a *DockerPullAction) pullImage(ctx context.Context, name string, spec Imag
 
 	a.Logger.Info("Pulling Docker image", "name", name, "image", imageRef, "architecture", spec.Architecture)
 
-	output, err := a.CommandProcessor.RunCommandWithContext(ctx, "docker", args...)
+	output, err := command.RunStreaming(ctx, a.CommandProcessor, a.streamOptions(ctx, name), "docker", args...)
 	if err != nil {
 		return fmt.Errorf("failed to pull image %s: %w. Output: %s", imageRef, err, output)
 	}
+	a.notePull(output)
 
 	a.Logger.Info("Successfully pulled image", "name", name, "image", imageRef)
 	return nil
@@ -370,13 +391,14 @@ func (a *DockerPullAction) pullMultiArchImage(ctx context.Context, name string,
 
 		a.Logger.Info("Pulling multi-arch Docker image", "name", name, "image", imageRef, "architecture", arch)
 
-		output, err := a.CommandProcessor.RunCommandWithContext(ctx, "docker", args...)
+		output, err := command.RunStreaming(ctx, a.CommandProcessor, a.streamOptions(ctx, name), "docker", args...)
 		if err != nil {
 			lastError = fmt.Errorf("failed to pull image %s for architecture %s: %w. Output: %s", imageRef, arch, err, output)
 			a.Logger.Error("Failed to pull multi-arch image", "name", name, "architecture", arch, "error", err)
 			continue
 		}
 
+		a.notePull(output)
 		successCount++
 		a.Logger.Info("Successfully pulled multi-arch image", "name", name, "image", imageRef, "architecture", arch)
 	}
@@ -392,6 +414,31 @@ func (a *DockerPullAction) pullMultiArchImage(ctx context.Context, name string,
 	return nil
 }
 
+// notePull records a change unless docker reported the image as already
+// current; quiet pulls cannot tell and always count as a change
+func (a *DockerPullAction) notePull(output string) {
+	if !strings.Contains(output, "Image is up to date") {
+		a.Changed = true
+	}
+}
+
+// streamOptions forwards docker pull output to the logger, the OutputHandler
+// and the task's progress as it arrives
+func (a *DockerPullAction) streamOptions(ctx context.Context, name string) command.StreamOptions {
+	logLine := command.LogLines(a.Logger, "docker pull output", "name", name)
+	return command.StreamOptions{
+		OnLine: func(stream command.OutputStream, line string) {
+			logLine(stream, line)
+			if a.OutputHandler != nil {
+				a.OutputHandler(stream, line)
+			}
+			progress := a.progress
+			progress.Message = line
+			task_engine.ReportActionProgress(ctx, progress)
+		},
+	}
+}
+
 func (a *DockerPullAction) buildImageReference(spec ImageSpec) string {
 	if spec.Tag == "" {
 		return spec.Image
@@ -414,5 +461,6 @@ func (a *DockerPullAction) GetOutput() interface{} {
 		"failedImages": a.FailedImages,
 		"totalImages":  len(a.Images) + len(a.MultiArchImages),
 		"success":      len(a.FailedImages) == 0,
+		"changed":      a.Changed,
 	}
 }
@@ -2,12 +2,15 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"testing"
 
 	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/command"
 	"github.com/ndizazzo/task-engine/testing/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -95,10 +98,27 @@ func (suite *DockerPullActionTestSuite) TestDockerPullAction_Execute_Success() {
 	assert.Equal(suite.T(), "nginx", action.Wrapped.PulledImages[0])
 	assert.Len(suite.T(), action.Wrapped.FailedImages, 0)
 	assert.Contains(suite.T(), action.Wrapped.Output, "Pulled 1 images, failed 0 images")
+	assert.True(suite.T(), action.Wrapped.Changed)
 
 	mockRunner.AssertExpectations(suite.T())
 }
 
+func (suite *DockerPullActionTestSuite) TestDockerPullAction_Execute_UpToDate() {
+	mockRunner := &mocks.MockCommandRunner{}
+	mockRunner.On("RunCommandWithContext", context.Background(), "docker", "pull", "--platform", "amd64", "nginx:latest").
+		Return("latest: Pulling from library/nginx\nDigest: sha256:...\nStatus: Image is up to date for nginx:latest", nil)
+
+	action := NewDockerPullActionLegacy(slog.Default(), map[string]ImageSpec{
+		"nginx": {Image: "nginx", Tag: "latest", Architecture: "amd64"},
+	})
+	action.Wrapped.SetCommandRunner(mockRunner)
+
+	assert.NoError(suite.T(), action.Wrapped.Execute(context.Background()))
+	assert.Len(suite.T(), action.Wrapped.PulledImages, 1)
+	assert.False(suite.T(), action.Wrapped.Changed)
+	mockRunner.AssertExpectations(suite.T())
+}
+
 func (suite *DockerPullActionTestSuite) TestDockerPullAction_Execute_SuccessMultipleImages() {
 	logger := slog.Default()
 	expectedOutput := "Image pulled successfully"
@@ -508,6 +528,7 @@ func (suite *DockerPullActionTestSuite) TestDockerPullAction_GetOutput() {
 		"failedImages": []string(nil),
 		"totalImages":  1,
 		"success":      true,
+		"changed":      false,
 	}
 
 	assert.Equal(suite.T(), expectedOutput, result)
@@ -720,3 +741,57 @@ func (suite *DockerPullActionTestSuite) TestNewDockerPullActionConstructor_Execu
 
 	mockRunner.AssertExpectations(suite.T())
 }
+
+func (suite *DockerPullActionTestSuite) TestDockerPullAction_Execute_ReportsImageProgress() {
+	mockRunner := &mocks.MockCommandRunner{}
+	mockRunner.On("RunCommandWithContext", mock.Anything, "docker", "pull", "nginx:latest").Return("ok", nil)
+	mockRunner.On("RunCommandWithContext", mock.Anything, "docker", "pull", "redis:7").Return("", errors.New("pull failed"))
+
+	images := map[string]ImageSpec{
+		"nginx": {Image: "nginx", Tag: "latest"},
+		"redis": {Image: "redis", Tag: "7"},
+	}
+	action := NewDockerPullActionLegacy(slog.Default(), images)
+	action.Wrapped.SetCommandRunner(mockRunner)
+
+	var reports []task_engine.ActionProgress
+	ctx := context.WithValue(context.Background(), task_engine.ProgressKey, task_engine.ProgressReporter(func(p task_engine.ActionProgress) {
+		reports = append(reports, p)
+	}))
+
+	err := action.Wrapped.Execute(ctx)
+	assert.Error(suite.T(), err)
+
+	var counts []task_engine.ActionProgress
+	for _, p := range reports {
+		if p.Message == "" {
+			counts = append(counts, p)
+		}
+	}
+	suite.Require().Len(counts, 3)
+	assert.Equal(suite.T(), task_engine.ActionProgress{Current: 0, Total: 2, Unit: "images"}, counts[0])
+	assert.Equal(suite.T(), task_engine.ActionProgress{Current: 2, Total: 2, Unit: "images"}, counts[2])
+}
+
+func (suite *DockerPullActionTestSuite) TestDockerPullAction_Execute_ForwardsOutputLines() {
+	mockRunner := &mocks.MockCommandRunner{}
+	mockRunner.On("RunCommandWithContext", mock.Anything, "docker", "pull", "nginx:latest").Return("latest: Pulling from library/nginx\nStatus: Downloaded newer image", nil)
+
+	var lines []string
+	action := NewDockerPullActionLegacy(slog.Default(), map[string]ImageSpec{"nginx": {Image: "nginx", Tag: "latest"}},
+		WithPullOutputHandler(func(stream command.OutputStream, line string) {
+			lines = append(lines, line)
+		}))
+	action.Wrapped.SetCommandRunner(mockRunner)
+
+	var messages []string
+	ctx := context.WithValue(context.Background(), task_engine.ProgressKey, task_engine.ProgressReporter(func(p task_engine.ActionProgress) {
+		if p.Message != "" {
+			messages = append(messages, p.Message)
+		}
+	}))
+
+	suite.Require().NoError(action.Wrapped.Execute(ctx))
+	assert.Equal(suite.T(), []string{"latest: Pulling from library/nginx", "Status: Downloaded newer image"}, lines)
+	assert.Equal(suite.T(), lines, messages)
+}
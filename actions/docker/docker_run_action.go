@@ -54,6 +54,8 @@ type DockerRunAction struct {
 	Output        string                      // Stores trimmed output regardless of buffer
 	OutputBuffer  *bytes.Buffer               // Optional buffer to write output to
 	ImageParam    task_engine.ActionParameter // optional parameter for image
+	// Changed is true when the last run ran the container
+	Changed bool
 }
 
 // SetCommandRunner allows injecting a mock or alternative CommandRunner for testing.
@@ -62,6 +64,7 @@ func (a *DockerRunAction) SetCommandRunner(runner command.CommandRunner) {
 }
 
 func (a *DockerRunAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve image via parameter if provided
 	effectiveImage := a.Image
 	if a.ImageParam != nil {
@@ -76,7 +79,7 @@ func (a *DockerRunAction) Execute(execCtx context.Context) error {
 	args = append(args, a.RunArgs...)
 
 	a.Logger.Info("Executing docker run", "image", effectiveImage, "args", a.RunArgs)
-	output, err := a.commandRunner.RunCommand("docker", args...)
+	output, err := a.commandRunner.RunCommandWithContext(execCtx, "docker", args...)
 	a.Output = strings.TrimSpace(output) // Store trimmed output internally
 
 	// Write to buffer if provided
@@ -94,14 +97,16 @@ func (a *DockerRunAction) Execute(execCtx context.Context) error {
 	}
 	a.Logger.Info("Docker run finished successfully", "output", a.Output)
 
+	a.Changed = true
 	return nil
 }
 
 // GetOutput returns information about the docker run execution
 func (a *DockerRunAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, a.Output != "", map[string]interface{}{
-		"image":  a.Image,
-		"args":   a.RunArgs,
-		"output": a.Output,
+		"image":   a.Image,
+		"args":    a.RunArgs,
+		"output":  a.Output,
+		"changed": a.Changed,
 	})
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/ndizazzo/task-engine/actions/docker"
 	command_mock "github.com/ndizazzo/task-engine/testing/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -31,7 +32,7 @@ func (suite *DockerRunTestSuite) TestExecuteSuccess() {
 	action.Wrapped.SetCommandRunner(suite.mockProcessor)
 
 	expectedOutput := "Hello from Docker! ...some more output..."
-	suite.mockProcessor.On("RunCommand", "docker", "run", "--rm", image).Return(expectedOutput+"\n  ", nil) // Simulate untrimmed output
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "run", "--rm", image).Return(expectedOutput+"\n  ", nil) // Simulate untrimmed output
 
 	err = action.Wrapped.Execute(context.Background())
 
@@ -49,7 +50,7 @@ func (suite *DockerRunTestSuite) TestExecuteSuccessWithCommand() {
 	action.Wrapped.SetCommandRunner(suite.mockProcessor)
 
 	expectedOutput := "hello from busybox"
-	suite.mockProcessor.On("RunCommand", "docker", "run", "--rm", image, "echo", "hello from busybox").Return(expectedOutput+"\n", nil)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "run", "--rm", image, "echo", "hello from busybox").Return(expectedOutput+"\n", nil)
 
 	err = action.Wrapped.Execute(context.Background())
 
@@ -67,7 +68,7 @@ func (suite *DockerRunTestSuite) TestExecuteCommandFailure() {
 	action.Wrapped.SetCommandRunner(suite.mockProcessor)
 
 	expectedOutput := "Error: image not found..."
-	suite.mockProcessor.On("RunCommand", "docker", "run", "--rm", image).Return(expectedOutput+" ", assert.AnError)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "run", "--rm", image).Return(expectedOutput+" ", assert.AnError)
 
 	err = action.Wrapped.Execute(context.Background())
 
@@ -88,7 +89,7 @@ func (suite *DockerRunTestSuite) TestExecuteSuccessWithBuffer() {
 	action.Wrapped.SetCommandRunner(suite.mockProcessor)
 
 	expectedOutput := "buffer test"
-	suite.mockProcessor.On("RunCommand", "docker", "run", "--rm", image, "echo", "-n", "buffer test").Return(expectedOutput, nil)
+	suite.mockProcessor.On("RunCommandWithContext", mock.Anything, "docker", "run", "--rm", image, "echo", "-n", "buffer test").Return(expectedOutput, nil)
 
 	err = action.Wrapped.Execute(context.Background())
 	suite.NoError(err)
@@ -20,6 +20,11 @@ type ContainerState struct {
 	Status string   `json:"status"`
 }
 
+// ContainerStateOutput holds the container states retrieved by GetContainerStateAction
+type ContainerStateOutput struct {
+	Containers []ContainerState
+}
+
 // GetContainerStateActionBuilder provides the new constructor pattern
 type GetContainerStateActionBuilder struct {
 	common.BaseConstructor[*GetContainerStateAction]
@@ -211,5 +216,15 @@ func (a *GetContainerStateAction) parseContainerOutput(output string) ([]Contain
 func (a *GetContainerStateAction) GetOutput() interface{} {
 	return a.BuildOutputWithCount(a.ContainerStates, true, map[string]interface{}{
 		"containers": a.ContainerStates,
+		"changed":    false,
 	})
 }
+
+var _ task_engine.ActionWithTypedOutput[ContainerStateOutput] = (*GetContainerStateAction)(nil)
+
+// TypedOutput returns the same results as GetOutput as a ContainerStateOutput
+func (a *GetContainerStateAction) TypedOutput() ContainerStateOutput {
+	return ContainerStateOutput{
+		Containers: a.ContainerStates,
+	}
+}
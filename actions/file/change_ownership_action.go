@@ -36,6 +36,8 @@ type ChangeOwnershipAction struct {
 	Path  string
 	Owner string
 	Group string
+	// Changed is true when the last run changed ownership
+	Changed bool
 
 	commandRunner command.CommandRunner
 }
@@ -61,8 +63,60 @@ func (a *ChangeOwnershipAction) SetCommandRunner(runner command.CommandRunner) {
 	a.commandRunner = runner
 }
 
+// Check reports whether the path, and everything beneath it when recursive,
+// already has the requested owner and group
+func (a *ChangeOwnershipAction) Check(execCtx context.Context) (bool, error) {
+	if err := a.resolve(execCtx); err != nil {
+		return false, err
+	}
+	uid, gid, err := lookupOwnership(a.Owner, a.Group)
+	if err != nil {
+		// Leave unknown users and groups for chown to report
+		return false, nil
+	}
+	return allPaths(a.Path, a.Recursive, func(_ string, info os.FileInfo) bool {
+		fileUID, fileGID, ok := fileOwnership(info)
+		return ok && (uid < 0 || fileUID == uid) && (gid < 0 || fileGID == gid)
+	})
+}
+
 func (a *ChangeOwnershipAction) Execute(execCtx context.Context) error {
-	// Resolve parameters using the ParameterResolver
+	a.Changed = false
+	if err := a.resolve(execCtx); err != nil {
+		return err
+	}
+
+	// Build chown arguments
+	var ownerSpec string
+	switch {
+	case a.Owner != "" && a.Group != "":
+		ownerSpec = a.Owner + ":" + a.Group
+	case a.Owner != "":
+		ownerSpec = a.Owner
+	default:
+		ownerSpec = ":" + a.Group
+	}
+
+	args := []string{ownerSpec, a.Path}
+	if a.Recursive {
+		args = append([]string{"-R"}, args...)
+	}
+
+	a.Logger.Info("Changing ownership", "path", a.Path, "owner", a.Owner, "group", a.Group, "recursive", a.Recursive)
+
+	output, err := a.commandRunner.RunCommandWithContext(execCtx, "chown", args...)
+	if err != nil {
+		a.Logger.Error("Failed to change ownership", "error", err, "output", output)
+		return fmt.Errorf("failed to change ownership of %s to %s: %w. Output: %s", a.Path, ownerSpec, err, output)
+	}
+
+	a.Changed = true
+	a.Logger.Info("Successfully changed ownership", "path", a.Path, "owner", a.Owner, "group", a.Group)
+	return nil
+}
+
+// resolve resolves and validates the parameters
+func (a *ChangeOwnershipAction) resolve(execCtx context.Context) error {
 	if a.PathParam != nil {
 		pathValue, err := a.ResolveStringParameter(execCtx, a.PathParam, "path")
 		if err != nil {
@@ -97,32 +151,6 @@ func (a *ChangeOwnershipAction) Execute(execCtx context.Context) error {
 	if _, err := os.Stat(a.Path); os.IsNotExist(err) {
 		return fmt.Errorf("path does not exist: %s", a.Path)
 	}
-
-	// Build chown arguments
-	var ownerSpec string
-	switch {
-	case a.Owner != "" && a.Group != "":
-		ownerSpec = a.Owner + ":" + a.Group
-	case a.Owner != "":
-		ownerSpec = a.Owner
-	default:
-		ownerSpec = ":" + a.Group
-	}
-
-	args := []string{ownerSpec, a.Path}
-	if a.Recursive {
-		args = append([]string{"-R"}, args...)
-	}
-
-	a.Logger.Info("Changing ownership", "path", a.Path, "owner", a.Owner, "group", a.Group, "recursive", a.Recursive)
-
-	output, err := a.commandRunner.RunCommandWithContext(execCtx, "chown", args...)
-	if err != nil {
-		a.Logger.Error("Failed to change ownership", "error", err, "output", output)
-		return fmt.Errorf("failed to change ownership of %s to %s: %w. Output: %s", a.Path, ownerSpec, err, output)
-	}
-
-	a.Logger.Info("Successfully changed ownership", "path", a.Path, "owner", a.Owner, "group", a.Group)
 	return nil
 }
 
@@ -133,5 +161,6 @@ func (a *ChangeOwnershipAction) GetOutput() interface{} {
 		"owner":     a.Owner,
 		"group":     a.Group,
 		"recursive": a.Recursive,
+		"changed":   a.Changed,
 	})
 }
@@ -3,6 +3,7 @@ package file_test
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
 
 	task_engine "github.com/ndizazzo/task-engine"
@@ -219,6 +220,35 @@ func (suite *ChangeOwnershipTestSuite) TestChangeOwnershipAction_GetOutput() {
 	suite.Equal(true, m["success"])
 }
 
+func (suite *ChangeOwnershipTestSuite) TestCheck() {
+	logger := command_mock.NewDiscardLogger()
+	owner := strconv.Itoa(os.Getuid())
+	group := strconv.Itoa(os.Getgid())
+
+	action, err := file.NewChangeOwnershipAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: suite.tempFile},
+		task_engine.StaticParameter{Value: owner},
+		task_engine.StaticParameter{Value: group},
+		false,
+	)
+	suite.Require().NoError(err)
+	action.Wrapped.SetCommandRunner(suite.mockRunner)
+
+	inState, err := action.Wrapped.Check(context.Background())
+	suite.NoError(err)
+	suite.True(inState, "file is already owned by the current user")
+
+	// The wrapper skips chown entirely
+	suite.NoError(action.Execute(context.Background()))
+	suite.True(action.Skipped())
+	suite.mockRunner.AssertNotCalled(suite.T(), "RunCommandWithContext")
+
+	action.Wrapped.OwnerParam = task_engine.StaticParameter{Value: strconv.Itoa(os.Getuid() + 1)}
+	inState, err = action.Wrapped.Check(context.Background())
+	suite.NoError(err)
+	suite.False(inState)
+}
+
 func TestChangeOwnershipTestSuite(t *testing.T) {
 	suite.Run(t, new(ChangeOwnershipTestSuite))
 }
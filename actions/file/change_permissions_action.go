@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
 
 	task_engine "github.com/ndizazzo/task-engine"
 	"github.com/ndizazzo/task-engine/actions/common"
@@ -34,6 +35,8 @@ type ChangePermissionsAction struct {
 	// Runtime resolved values
 	Path        string
 	Permissions string
+	// Changed is true when the last run changed permissions
+	Changed bool
 
 	commandRunner command.CommandRunner
 }
@@ -57,8 +60,52 @@ func (a *ChangePermissionsAction) SetCommandRunner(runner command.CommandRunner)
 	a.commandRunner = runner
 }
 
+// Check reports whether the path, and everything beneath it when recursive,
+// already has the requested mode. Only octal modes such as "0644" can be
+// checked; symbolic modes always run chmod.
+func (a *ChangePermissionsAction) Check(execCtx context.Context) (bool, error) {
+	if err := a.resolve(execCtx); err != nil {
+		return false, err
+	}
+	mode, err := strconv.ParseUint(a.Permissions, 8, 32)
+	if err != nil || mode > uint64(os.ModePerm) {
+		return false, nil
+	}
+	return allPaths(a.Path, a.Recursive, func(_ string, info os.FileInfo) bool {
+		// chmod leaves symlinks themselves untouched
+		if info.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+		return info.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky) == os.FileMode(mode)
+	})
+}
+
 func (a *ChangePermissionsAction) Execute(execCtx context.Context) error {
-	// Resolve parameters using the ParameterResolver
+	a.Changed = false
+	if err := a.resolve(execCtx); err != nil {
+		return err
+	}
+
+	args := []string{a.Permissions, a.Path}
+	if a.Recursive {
+		args = append([]string{"-R"}, args...)
+	}
+
+	a.Logger.Info("Changing permissions", "path", a.Path, "permissions", a.Permissions, "recursive", a.Recursive)
+
+	output, err := a.commandRunner.RunCommandWithContext(execCtx, "chmod", args...)
+	if err != nil {
+		a.Logger.Error("Failed to change permissions", "error", err, "output", output)
+		return fmt.Errorf("failed to change permissions of %s to %s: %w. Output: %s", a.Path, a.Permissions, err, output)
+	}
+
+	a.Changed = true
+	a.Logger.Info("Successfully changed permissions", "path", a.Path, "permissions", a.Permissions)
+	return nil
+}
+
+// resolve resolves and validates the parameters
+func (a *ChangePermissionsAction) resolve(execCtx context.Context) error {
 	if a.PathParam != nil {
 		pathValue, err := a.ResolveStringParameter(execCtx, a.PathParam, "path")
 		if err != nil {
@@ -78,21 +125,6 @@ func (a *ChangePermissionsAction) Execute(execCtx context.Context) error {
 	if _, err := os.Stat(a.Path); os.IsNotExist(err) {
 		return fmt.Errorf("path does not exist: %s", a.Path)
 	}
-
-	args := []string{a.Permissions, a.Path}
-	if a.Recursive {
-		args = append([]string{"-R"}, args...)
-	}
-
-	a.Logger.Info("Changing permissions", "path", a.Path, "permissions", a.Permissions, "recursive", a.Recursive)
-
-	output, err := a.commandRunner.RunCommandWithContext(execCtx, "chmod", args...)
-	if err != nil {
-		a.Logger.Error("Failed to change permissions", "error", err, "output", output)
-		return fmt.Errorf("failed to change permissions of %s to %s: %w. Output: %s", a.Path, a.Permissions, err, output)
-	}
-
-	a.Logger.Info("Successfully changed permissions", "path", a.Path, "permissions", a.Permissions)
 	return nil
 }
 
@@ -102,5 +134,6 @@ func (a *ChangePermissionsAction) GetOutput() interface{} {
 		"path":        a.Path,
 		"permissions": a.Permissions,
 		"recursive":   a.Recursive,
+		"changed":     a.Changed,
 	})
 }
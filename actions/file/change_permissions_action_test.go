@@ -180,6 +180,33 @@ func (suite *ChangePermissionsTestSuite) TestChangePermissionsAction_GetOutput()
 	suite.Equal(true, m["success"])
 }
 
+func (suite *ChangePermissionsTestSuite) TestCheck() {
+	logger := command_mock.NewDiscardLogger()
+	suite.Require().NoError(os.Chmod(suite.tempFile, 0o640))
+
+	tests := []struct {
+		mode    string
+		inState bool
+	}{
+		{"640", true},
+		{"0640", true},
+		{"644", false},
+		{"u+r", false},
+	}
+	for _, tt := range tests {
+		action, err := file.NewChangePermissionsAction(logger).WithParameters(
+			task_engine.StaticParameter{Value: suite.tempFile},
+			task_engine.StaticParameter{Value: tt.mode},
+			false,
+		)
+		suite.Require().NoError(err)
+
+		inState, err := action.Wrapped.Check(context.Background())
+		suite.NoError(err)
+		suite.Equal(tt.inState, inState, tt.mode)
+	}
+}
+
 func TestChangePermissionsTestSuite(t *testing.T) {
 	suite.Run(t, new(ChangePermissionsTestSuite))
 }
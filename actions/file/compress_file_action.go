@@ -69,6 +69,8 @@ type CompressFileAction struct {
 	SourcePath      string
 	DestinationPath string
 	CompressionType CompressionType
+	// Changed is true when the last run compressed the file
+	Changed bool
 
 	// Parameter-aware fields
 	SourcePathParam      task_engine.ActionParameter
@@ -77,6 +79,7 @@ type CompressFileAction struct {
 }
 
 func (a *CompressFileAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve parameters using the ParameterResolver
 	if a.SourcePathParam != nil {
 		sourceValue, err := a.ResolveStringParameter(execCtx, a.SourcePathParam, "source path")
@@ -162,6 +165,7 @@ func (a *CompressFileAction) Execute(execCtx context.Context) error {
 			"compressionRatio", fmt.Sprintf("%.1f%%", compressionRatio))
 	}
 
+	a.Changed = true
 	return nil
 }
 
@@ -184,5 +188,6 @@ func (a *CompressFileAction) GetOutput() interface{} {
 		"source":          a.SourcePath,
 		"destination":     a.DestinationPath,
 		"compressionType": string(a.CompressionType),
+		"changed":         a.Changed,
 	})
 }
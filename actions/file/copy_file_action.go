@@ -35,6 +35,11 @@ type CopyFileAction struct {
 	// Runtime resolved values
 	Source      string
 	Destination string
+	// Changed is true when the last run copied anything
+	Changed bool
+
+	// bytes copied so far in the current run, reported as progress
+	copied int64
 }
 
 // WithParameters sets the parameters for file copying and returns a wrapped Action
@@ -54,8 +59,46 @@ func (a *CopyFileAction) WithParameters(
 	return constructor.WrapAction(a, "Copy File", "copy-file-action"), nil
 }
 
+// Check reports whether the destination already holds the same content as a
+// source file. Directory copies are always performed.
+func (a *CopyFileAction) Check(execCtx context.Context) (bool, error) {
+	if err := a.resolve(execCtx); err != nil {
+		return false, err
+	}
+	info, err := os.Stat(a.Source)
+	if err != nil || info.IsDir() {
+		return false, nil
+	}
+	return filesEqual(a.Source, a.Destination)
+}
+
 func (a *CopyFileAction) Execute(execCtx context.Context) error {
-	// Resolve parameters using the ParameterResolver
+	a.Changed = false
+	if err := a.resolve(execCtx); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(a.Source); os.IsNotExist(err) {
+		a.Logger.Error("Source path does not exist", "source", a.Source)
+		return err
+	}
+
+	a.copied = 0
+
+	// If recursive flag is set, use recursive copy logic
+	var err error
+	if a.Recursive {
+		err = a.executeRecursiveCopy(execCtx)
+	} else {
+		// Otherwise, use the original file-based copy logic
+		err = a.executeFileCopy(execCtx)
+	}
+	a.Changed = err == nil
+	return err
+}
+
+// resolve resolves the source and destination parameters
+func (a *CopyFileAction) resolve(execCtx context.Context) error {
 	if a.SourceParam != nil {
 		sourceValue, err := a.ResolveStringParameter(execCtx, a.SourceParam, "source")
 		if err != nil {
@@ -71,22 +114,10 @@ func (a *CopyFileAction) Execute(execCtx context.Context) error {
 		}
 		a.Destination = destValue
 	}
-
-	if _, err := os.Stat(a.Source); os.IsNotExist(err) {
-		a.Logger.Error("Source path does not exist", "source", a.Source)
-		return err
-	}
-
-	// If recursive flag is set, use recursive copy logic
-	if a.Recursive {
-		return a.executeRecursiveCopy()
-	}
-
-	// Otherwise, use the original file-based copy logic
-	return a.executeFileCopy()
+	return nil
 }
 
-func (a *CopyFileAction) executeRecursiveCopy() error {
+func (a *CopyFileAction) executeRecursiveCopy(ctx context.Context) error {
 	sourceInfo, err := os.Stat(a.Source)
 	if err != nil {
 		a.Logger.Error("Failed to stat source", "source", a.Source, "error", err)
@@ -95,7 +126,7 @@ func (a *CopyFileAction) executeRecursiveCopy() error {
 
 	// If source is a file, just copy it normally
 	if !sourceInfo.IsDir() {
-		return a.executeFileCopy()
+		return a.executeFileCopy(ctx)
 	}
 
 	// For directories, create destination directory and copy contents recursively
@@ -150,7 +181,7 @@ func (a *CopyFileAction) executeRecursiveCopy() error {
 
 		case info.Mode()&os.ModeType == 0:
 			// Regular file
-			if err := a.copyFile(path, destPath, info.Mode()); err != nil {
+			if err := a.copyFile(ctx, path, destPath, info.Mode()); err != nil {
 				a.Logger.Error("Failed to copy file", "source", path, "destination", destPath, "error", err)
 				return err
 			}
@@ -165,7 +196,7 @@ func (a *CopyFileAction) executeRecursiveCopy() error {
 	})
 }
 
-func (a *CopyFileAction) copyFile(src, dst string, mode os.FileMode) error {
+func (a *CopyFileAction) copyFile(ctx context.Context, src, dst string, mode os.FileMode) error {
 	// Sanitize paths to prevent path traversal attacks
 	sanitizedSrc, err := SanitizePath(src)
 	if err != nil {
@@ -196,8 +227,8 @@ func (a *CopyFileAction) copyFile(src, dst string, mode os.FileMode) error {
 	}
 	defer dstFile.Close()
 
-	// Copy content
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
+	// Copy content; the total size of a directory tree is not known up front
+	if _, err := io.Copy(a.progressWriter(ctx, dstFile, 0), srcFile); err != nil {
 		return err
 	}
 
@@ -232,7 +263,7 @@ func (a *CopyFileAction) copySymlink(src, dst string) error {
 	return os.Symlink(target, sanitizedDst)
 }
 
-func (a *CopyFileAction) executeFileCopy() error {
+func (a *CopyFileAction) executeFileCopy(ctx context.Context) error {
 	if a.CreateDir {
 		destDir := filepath.Dir(a.Destination)
 		if err := os.MkdirAll(destDir, 0o750); err != nil {
@@ -255,7 +286,12 @@ func (a *CopyFileAction) executeFileCopy() error {
 	}
 	defer destFile.Close()
 
-	_, err = io.Copy(destFile, srcFile)
+	var total int64
+	if info, statErr := srcFile.Stat(); statErr == nil {
+		total = info.Size()
+	}
+
+	_, err = io.Copy(a.progressWriter(ctx, destFile, total), srcFile)
 	if err != nil {
 		a.Logger.Debug("Failed to copy file", "error", err, "source", a.Source, "destination", a.Destination)
 		return err
@@ -264,6 +300,28 @@ func (a *CopyFileAction) executeFileCopy() error {
 	return nil
 }
 
+// progressWriter wraps w so that every write reports the bytes copied so far.
+// Without a progress reporter w is returned as is, so io.Copy can still use
+// its io.ReaderFrom (copy_file_range or sendfile for *os.File).
+func (a *CopyFileAction) progressWriter(ctx context.Context, w io.Writer, total int64) io.Writer {
+	if _, ok := task_engine.ProgressReporterFromContext(ctx); !ok {
+		return w
+	}
+	return writerFunc(func(p []byte) (int, error) {
+		n, err := w.Write(p)
+		a.copied += int64(n)
+		task_engine.ReportProgress(ctx, a.copied, total, "bytes")
+		return n, err
+	})
+}
+
+// writerFunc adapts a function to io.Writer
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
 // GetOutput returns metadata about the copy operation
 func (a *CopyFileAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, true, map[string]interface{}{
@@ -271,5 +329,6 @@ func (a *CopyFileAction) GetOutput() interface{} {
 		"destination": a.Destination,
 		"createDir":   a.CreateDir,
 		"recursive":   a.Recursive,
+		"changed":     a.Changed,
 	})
 }
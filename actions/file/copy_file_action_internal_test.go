@@ -0,0 +1,31 @@
+package file
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressWriterKeepsReaderFromWithoutReporter(t *testing.T) {
+	dst, err := os.Create(filepath.Join(t.TempDir(), "destination"))
+	require.NoError(t, err)
+	defer dst.Close()
+
+	action := NewCopyFileAction(nil)
+	w := action.progressWriter(context.Background(), dst, 0)
+	assert.Same(t, dst, w, "io.Copy can use the file's copy_file_range/sendfile fast path")
+
+	ctx := context.WithValue(context.Background(), task_engine.ProgressKey, task_engine.ProgressReporter(func(task_engine.ActionProgress) {}))
+	w = action.progressWriter(ctx, dst, 0)
+	_, isFile := w.(*os.File)
+	assert.False(t, isFile, "writes are counted when a reporter is listening")
+	_, err = io.WriteString(w, "data")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), action.copied)
+}
@@ -1203,3 +1203,30 @@ func (suite *CopyFileActionTestSuite) TestCopyFileAction_GetOutput() {
 func TestCopyFileActionTestSuite(t *testing.T) {
 	suite.Run(t, new(CopyFileActionTestSuite))
 }
+
+func (suite *CopyFileActionTestSuite) TestCopyFile_ReportsBytesProgress() {
+	sourceFile := filepath.Join(suite.tempDir, "progress_source.txt")
+	destinationFile := filepath.Join(suite.tempDir, "progress_destination.txt")
+	content := []byte("progress reporting content")
+	suite.Require().NoError(os.WriteFile(sourceFile, content, 0o600))
+
+	var reports []task_engine.ActionProgress
+	ctx := context.WithValue(context.Background(), task_engine.ProgressKey, task_engine.ProgressReporter(func(p task_engine.ActionProgress) {
+		reports = append(reports, p)
+	}))
+
+	copyAction, err := file.NewCopyFileAction(nil).WithParameters(
+		task_engine.StaticParameter{Value: sourceFile},
+		task_engine.StaticParameter{Value: destinationFile},
+		false,
+		false,
+	)
+	suite.Require().NoError(err)
+	suite.Require().NoError(copyAction.Execute(ctx))
+
+	suite.Require().NotEmpty(reports)
+	last := reports[len(reports)-1]
+	suite.Equal(int64(len(content)), last.Current)
+	suite.Equal(int64(len(content)), last.Total)
+	suite.Equal("bytes", last.Unit)
+}
@@ -42,40 +42,35 @@ type CreateDirectoriesAction struct {
 	RootPath         string
 	Directories      []string
 	CreatedDirsCount int
+	// Changed is true when the last run created at least one directory
+	Changed bool
 
 	// Parameter-aware fields
 	RootPathParam    task_engine.ActionParameter
 	DirectoriesParam task_engine.ActionParameter
 }
 
-func (a *CreateDirectoriesAction) Execute(execCtx context.Context) error {
-	// Resolve parameters using the ParameterResolver
-	if a.RootPathParam != nil {
-		rootPathValue, err := a.ResolveStringParameter(execCtx, a.RootPathParam, "root path")
-		if err != nil {
-			return err
-		}
-		a.RootPath = rootPathValue
+// Check reports whether every directory already exists
+func (a *CreateDirectoriesAction) Check(execCtx context.Context) (bool, error) {
+	if err := a.resolve(execCtx); err != nil {
+		return false, err
 	}
-
-	if a.DirectoriesParam != nil {
-		directoriesValue, err := a.ResolveParameter(execCtx, a.DirectoriesParam, "directories")
-		if err != nil {
-			return err
+	for _, dir := range a.Directories {
+		if strings.TrimSpace(dir) == "" {
+			continue
 		}
-		if directoriesSlice, ok := directoriesValue.([]string); ok {
-			a.Directories = directoriesSlice
-		} else {
-			return fmt.Errorf("directories parameter is not a []string, got %T", directoriesValue)
+		info, err := os.Stat(filepath.Join(a.RootPath, dir))
+		if err != nil || !info.IsDir() {
+			return false, nil
 		}
 	}
+	return true, nil
+}
 
-	if a.RootPath == "" {
-		return fmt.Errorf("root path cannot be empty")
-	}
-
-	if len(a.Directories) == 0 {
-		return fmt.Errorf("directories list cannot be empty")
+func (a *CreateDirectoriesAction) Execute(execCtx context.Context) error {
+	a.Changed = false
+	if err := a.resolve(execCtx); err != nil {
+		return err
 	}
 
 	a.Logger.Info("Creating directories", "rootPath", a.RootPath, "directories", a.Directories)
@@ -101,6 +96,7 @@ func (a *CreateDirectoriesAction) Execute(execCtx context.Context) error {
 		}
 
 		a.Logger.Debug("Created directory", "path", fullPath)
+		a.Changed = true
 		createdCount++
 	}
 
@@ -109,6 +105,38 @@ func (a *CreateDirectoriesAction) Execute(execCtx context.Context) error {
 	return nil
 }
 
+// resolve resolves and validates the parameters
+func (a *CreateDirectoriesAction) resolve(execCtx context.Context) error {
+	if a.RootPathParam != nil {
+		rootPathValue, err := a.ResolveStringParameter(execCtx, a.RootPathParam, "root path")
+		if err != nil {
+			return err
+		}
+		a.RootPath = rootPathValue
+	}
+
+	if a.DirectoriesParam != nil {
+		directoriesValue, err := a.ResolveParameter(execCtx, a.DirectoriesParam, "directories")
+		if err != nil {
+			return err
+		}
+		if directoriesSlice, ok := directoriesValue.([]string); ok {
+			a.Directories = directoriesSlice
+		} else {
+			return fmt.Errorf("directories parameter is not a []string, got %T", directoriesValue)
+		}
+	}
+
+	if a.RootPath == "" {
+		return fmt.Errorf("root path cannot be empty")
+	}
+
+	if len(a.Directories) == 0 {
+		return fmt.Errorf("directories list cannot be empty")
+	}
+	return nil
+}
+
 // GetOutput returns metadata about the directory creation
 func (a *CreateDirectoriesAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, true, map[string]interface{}{
@@ -116,5 +144,6 @@ func (a *CreateDirectoriesAction) GetOutput() interface{} {
 		"directories": a.Directories,
 		"created":     a.CreatedDirsCount,
 		"total":       len(a.Directories),
+		"changed":     a.Changed,
 	})
 }
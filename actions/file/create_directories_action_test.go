@@ -191,6 +191,26 @@ func (suite *CreateDirectoriesActionTestSuite) TestCreateDirectories_RelativePat
 	}
 }
 
+func (suite *CreateDirectoriesActionTestSuite) TestCreateDirectories_Changed() {
+	directories := []string{"data", "logs"}
+	action, err := file.NewCreateDirectoriesAction(mocks.NewDiscardLogger()).WithParameters(
+		task_engine.StaticParameter{Value: suite.rootPath},
+		task_engine.StaticParameter{Value: directories},
+	)
+	suite.Require().NoError(err)
+
+	inState, err := action.Wrapped.Check(context.Background())
+	suite.NoError(err)
+	suite.False(inState)
+
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.True(action.Wrapped.Changed)
+
+	inState, err = action.Wrapped.Check(context.Background())
+	suite.NoError(err)
+	suite.True(inState)
+}
+
 func TestCreateDirectoriesActionTestSuite(t *testing.T) {
 	suite.Run(t, new(CreateDirectoriesActionTestSuite))
 }
@@ -20,6 +20,8 @@ type CreateSymlinkAction struct {
 	LinkPath   string
 	Overwrite  bool
 	CreateDirs bool
+	// Changed is true when the last run created the symlink
+	Changed bool
 
 	// Parameter-aware fields
 	TargetParam   task_engine.ActionParameter
@@ -52,8 +54,29 @@ func (a *CreateSymlinkAction) WithParameters(
 	return constructor.WrapAction(a, "Create Symlink", "create-symlink-action"), nil
 }
 
-func (a *CreateSymlinkAction) Execute(execCtx context.Context) error {
-	// Resolve parameters using the ParameterResolver
+// Check reports whether the link already exists with exactly the target
+func (a *CreateSymlinkAction) Check(execCtx context.Context) (bool, error) {
+	if err := a.resolve(execCtx); err != nil {
+		return false, err
+	}
+	sanitizedTarget, err := SanitizePath(a.Target)
+	if err != nil {
+		return false, fmt.Errorf("invalid target path: %w", err)
+	}
+	sanitizedLinkPath, err := SanitizePath(a.LinkPath)
+	if err != nil {
+		return false, fmt.Errorf("invalid link path: %w", err)
+	}
+	// Require the exact target so relative links are rewritten as requested
+	actualTarget, err := os.Readlink(sanitizedLinkPath)
+	if err != nil {
+		return false, nil
+	}
+	return actualTarget == sanitizedTarget, nil
+}
+
+// resolve resolves the target and link path parameters
+func (a *CreateSymlinkAction) resolve(execCtx context.Context) error {
 	if a.TargetParam != nil {
 		targetValue, err := a.ResolveStringParameter(execCtx, a.TargetParam, "target")
 		if err != nil {
@@ -69,6 +92,14 @@ func (a *CreateSymlinkAction) Execute(execCtx context.Context) error {
 		}
 		a.LinkPath = linkPathValue
 	}
+	return nil
+}
+
+func (a *CreateSymlinkAction) Execute(execCtx context.Context) error {
+	a.Changed = false
+	if err := a.resolve(execCtx); err != nil {
+		return err
+	}
 
 	// Sanitize paths to prevent path traversal attacks
 	sanitizedTarget, err := SanitizePath(a.Target)
@@ -118,6 +149,7 @@ func (a *CreateSymlinkAction) Execute(execCtx context.Context) error {
 		return fmt.Errorf("failed to verify symlink %s: %w", sanitizedLinkPath, err)
 	}
 
+	a.Changed = true
 	a.Logger.Info("Successfully created symlink", "target", sanitizedTarget, "link", sanitizedLinkPath)
 	return nil
 }
@@ -129,6 +161,7 @@ func (a *CreateSymlinkAction) GetOutput() interface{} {
 		"linkPath":  a.LinkPath,
 		"overwrite": a.Overwrite,
 		"created":   true,
+		"changed":   a.Changed,
 	})
 }
 
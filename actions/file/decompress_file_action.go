@@ -57,6 +57,8 @@ type DecompressFileAction struct {
 	SourcePath      string
 	DestinationPath string
 	CompressionType CompressionType
+	// Changed is true when the last run decompressed the file
+	Changed bool
 
 	// Parameter-aware fields
 	SourcePathParam      task_engine.ActionParameter
@@ -64,6 +66,7 @@ type DecompressFileAction struct {
 }
 
 func (a *DecompressFileAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve parameters using the ParameterResolver
 	if a.SourcePathParam != nil {
 		sourceValue, err := a.ResolveStringParameter(execCtx, a.SourcePathParam, "source path")
@@ -157,6 +160,7 @@ func (a *DecompressFileAction) Execute(execCtx context.Context) error {
 			"compressionRatio", fmt.Sprintf("%.1f%%", compressionRatio))
 	}
 
+	a.Changed = true
 	return nil
 }
 
@@ -187,6 +191,7 @@ func (a *DecompressFileAction) GetOutput() interface{} {
 		"source":          a.SourcePath,
 		"destination":     a.DestinationPath,
 		"compressionType": string(a.CompressionType),
+		"changed":         a.Changed,
 	})
 }
 
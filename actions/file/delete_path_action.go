@@ -20,6 +20,8 @@ type DeletePathAction struct {
 	DryRun          bool
 	IncludeHidden   bool
 	ExcludePatterns []string
+	// Changed is true when the last run deleted the path
+	Changed bool
 
 	// Parameter-aware fields
 	PathParam task_engine.ActionParameter
@@ -61,24 +63,24 @@ func (a *DeletePathAction) WithParameters(
 	return constructor.WrapAction(a, "Delete Path", "delete-path-action"), nil
 }
 
-func (a *DeletePathAction) Execute(execCtx context.Context) error {
-	// Resolve path parameter using the ParameterResolver
-	if a.PathParam != nil {
-		pathValue, err := a.ResolveStringParameter(execCtx, a.PathParam, "path")
-		if err != nil {
-			return err
-		}
-		a.Path = pathValue
+// Check reports whether the path is already absent
+func (a *DeletePathAction) Check(execCtx context.Context) (bool, error) {
+	sanitizedPath, err := a.resolve(execCtx)
+	if err != nil {
+		return false, err
 	}
-
-	if a.Path == "" {
-		return fmt.Errorf("path cannot be empty")
+	_, err = os.Stat(sanitizedPath)
+	if os.IsNotExist(err) {
+		return true, nil
 	}
+	return false, nil
+}
 
-	// Sanitize path to prevent path traversal attacks
-	sanitizedPath, err := SanitizePath(a.Path)
+func (a *DeletePathAction) Execute(execCtx context.Context) error {
+	a.Changed = false
+	sanitizedPath, err := a.resolve(execCtx)
 	if err != nil {
-		return fmt.Errorf("invalid path: %w", err)
+		return err
 	}
 	info, err := os.Stat(sanitizedPath)
 	if os.IsNotExist(err) {
@@ -90,19 +92,43 @@ func (a *DeletePathAction) Execute(execCtx context.Context) error {
 		return fmt.Errorf("failed to stat path %s: %w", sanitizedPath, err)
 	}
 
-	// If it's a directory and recursive flag is set, use recursive delete logic
-	if info.IsDir() && a.Recursive {
-		return a.executeRecursiveDelete()
-	}
-
 	// If it's a directory but recursive flag is not set, return error
 	if info.IsDir() && !a.Recursive {
 		a.Logger.Error("Cannot delete directory without recursive flag", "path", sanitizedPath)
 		return fmt.Errorf("cannot delete directory %s without recursive flag", sanitizedPath)
 	}
 
-	// Otherwise, use the original file-based delete logic
-	return a.executeFileDelete(sanitizedPath)
+	if info.IsDir() {
+		// Directories with the recursive flag use the recursive delete logic
+		err = a.executeRecursiveDelete()
+	} else {
+		// Otherwise, use the original file-based delete logic
+		err = a.executeFileDelete(sanitizedPath)
+	}
+	a.Changed = err == nil && !a.DryRun
+	return err
+}
+
+// resolve resolves the path parameter and returns the sanitized path
+func (a *DeletePathAction) resolve(execCtx context.Context) (string, error) {
+	if a.PathParam != nil {
+		pathValue, err := a.ResolveStringParameter(execCtx, a.PathParam, "path")
+		if err != nil {
+			return "", err
+		}
+		a.Path = pathValue
+	}
+
+	if a.Path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+
+	// Sanitize path to prevent path traversal attacks
+	sanitizedPath, err := SanitizePath(a.Path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	return sanitizedPath, nil
 }
 
 func (a *DeletePathAction) executeRecursiveDelete() error {
@@ -271,5 +297,6 @@ func (a *DeletePathAction) GetOutput() interface{} {
 		"path":      a.Path,
 		"recursive": a.Recursive,
 		"dryRun":    a.DryRun,
+		"changed":   a.Changed,
 	})
 }
@@ -497,6 +497,23 @@ func (suite *DeletePathActionTestSuite) TestDeletePathAction_GetOutput() {
 	suite.Equal(true, m["success"])
 }
 
+func (suite *DeletePathActionTestSuite) TestDeletePath_Check() {
+	filePath := filepath.Join(suite.tempDir, "stale.txt")
+	suite.Require().NoError(os.WriteFile(filePath, []byte("x"), 0o600))
+
+	action, err := file.NewDeletePathAction(mocks.NewDiscardLogger()).WithParameters(
+		task_engine.StaticParameter{Value: filePath}, false, false, false, nil,
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.False(action.Skipped())
+	suite.True(action.Wrapped.Changed)
+
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.True(action.Skipped(), "an absent path is already in the desired state")
+}
+
 func TestDeletePathActionTestSuite(t *testing.T) {
 	suite.Run(t, new(DeletePathActionTestSuite))
 }
@@ -0,0 +1,139 @@
+package file
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// allPaths reports whether match holds for path and, when recursive, for
+// everything beneath it. A missing path never matches.
+func allPaths(path string, recursive bool, match func(path string, info os.FileInfo) bool) (bool, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !recursive || !info.IsDir() {
+		return match(path, info), nil
+	}
+
+	matched := true
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !match(p, info) {
+			matched = false
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return matched, err
+}
+
+// lookupOwnership resolves user and group names (or numeric IDs) to IDs;
+// an empty name yields -1
+func lookupOwnership(owner, group string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	if owner != "" {
+		if uid, err = strconv.Atoi(owner); err != nil {
+			u, lookupErr := user.Lookup(owner)
+			if lookupErr != nil {
+				return 0, 0, lookupErr
+			}
+			if uid, err = strconv.Atoi(u.Uid); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if group != "" {
+		if gid, err = strconv.Atoi(group); err != nil {
+			g, lookupErr := user.LookupGroup(group)
+			if lookupErr != nil {
+				return 0, 0, lookupErr
+			}
+			if gid, err = strconv.Atoi(g.Gid); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return uid, gid, nil
+}
+
+// filesEqual reports whether two regular files hold the same bytes; a missing
+// file is never equal
+func filesEqual(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, ignoreNotExist(err)
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, ignoreNotExist(err)
+	}
+	if !infoA.Mode().IsRegular() || !infoB.Mode().IsRegular() || infoA.Size() != infoB.Size() {
+		return false, nil
+	}
+
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	bufA := make([]byte, 32*1024)
+	bufB := make([]byte, 32*1024)
+	for {
+		n, errA := io.ReadFull(fa, bufA)
+		m, errB := io.ReadFull(fb, bufB)
+		if n != m || !bytes.Equal(bufA[:n], bufB[:m]) {
+			return false, nil
+		}
+		if errA == io.EOF || errA == io.ErrUnexpectedEOF {
+			return errB == io.EOF || errB == io.ErrUnexpectedEOF, nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+	}
+}
+
+func ignoreNotExist(err error) error {
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// fileHasContent reports whether path is a regular file holding exactly content
+func fileHasContent(path string, content []byte) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !info.Mode().IsRegular() || info.Size() != int64(len(content)) {
+		return false, nil
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(existing, content), nil
+}
@@ -71,6 +71,8 @@ type ExtractFileAction struct {
 	SourcePath      string
 	DestinationPath string
 	ArchiveType     ArchiveType
+	// Changed is true when the last run extracted the archive
+	Changed bool
 
 	// Parameter-aware fields
 	SourcePathParam      task_engine.ActionParameter
@@ -78,6 +80,7 @@ type ExtractFileAction struct {
 }
 
 func (a *ExtractFileAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve parameters using the ParameterResolver
 	if a.SourcePathParam != nil {
 		sourceValue, err := a.ResolveStringParameter(execCtx, a.SourcePathParam, "source path")
@@ -172,6 +175,7 @@ func (a *ExtractFileAction) Execute(execCtx context.Context) error {
 		"destination", a.DestinationPath,
 		"archiveType", a.ArchiveType)
 
+	a.Changed = true
 	return nil
 }
 
@@ -333,6 +337,7 @@ func (a *ExtractFileAction) GetOutput() interface{} {
 		"source":      a.SourcePath,
 		"destination": a.DestinationPath,
 		"archiveType": string(a.ArchiveType),
+		"changed":     a.Changed,
 	})
 }
 
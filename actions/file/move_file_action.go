@@ -49,6 +49,8 @@ type MoveFileAction struct {
 	Source      string
 	Destination string
 	CreateDirs  bool
+	// Changed is true when the last run moved the path
+	Changed bool
 
 	// Parameter-aware fields
 	SourceParam      task_engine.ActionParameter
@@ -63,6 +65,7 @@ func (a *MoveFileAction) SetCommandRunner(runner command.CommandRunner) {
 }
 
 func (a *MoveFileAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve parameters using the ParameterResolver
 	if a.SourceParam != nil {
 		sourceValue, err := a.ResolveStringParameter(execCtx, a.SourceParam, "source")
@@ -112,6 +115,7 @@ func (a *MoveFileAction) Execute(execCtx context.Context) error {
 	}
 
 	a.Logger.Info("Successfully moved file/directory", "source", a.Source, "destination", a.Destination)
+	a.Changed = true
 	return nil
 }
 
@@ -121,5 +125,6 @@ func (a *MoveFileAction) GetOutput() interface{} {
 		"source":      a.Source,
 		"destination": a.Destination,
 		"createDirs":  a.CreateDirs,
+		"changed":     a.Changed,
 	})
 }
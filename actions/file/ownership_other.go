@@ -0,0 +1,10 @@
+//go:build !unix
+
+package file
+
+import "os"
+
+// fileOwnership is not available on this platform
+func fileOwnership(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
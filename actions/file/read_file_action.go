@@ -103,6 +103,7 @@ func (a *ReadFileAction) GetOutput() interface{} {
 			"content":  nil,
 			"fileSize": 0,
 			"filePath": a.FilePath,
+			"changed":  false,
 		})
 	}
 
@@ -110,5 +111,6 @@ func (a *ReadFileAction) GetOutput() interface{} {
 		"content":  *a.OutputBuffer,
 		"fileSize": len(*a.OutputBuffer),
 		"filePath": a.FilePath,
+		"changed":  false,
 	})
 }
@@ -23,6 +23,8 @@ type ReplaceLinesAction struct {
 	ReplaceParamPatterns map[*regexp.Regexp]task_engine.ActionParameter
 	// Optional file path parameter
 	FilePathParam task_engine.ActionParameter
+	// Changed is true when the last run altered at least one line
+	Changed bool
 }
 
 // NewReplaceLinesAction creates a new ReplaceLinesAction with the given logger
@@ -47,6 +49,7 @@ func (a *ReplaceLinesAction) WithParameters(filePathParam task_engine.ActionPara
 }
 
 func (a *ReplaceLinesAction) Execute(ctx context.Context) error {
+	a.Changed = false
 	// Resolve file path parameter if provided using the ParameterResolver
 	if a.FilePathParam != nil {
 		pathValue, err := a.ResolveStringParameter(ctx, a.FilePathParam, "file path")
@@ -99,13 +102,16 @@ func (a *ReplaceLinesAction) Execute(ctx context.Context) error {
 	defer file.Close()
 
 	var updatedLines []string
+	changed := false
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := scanner.Text()
+		original := scanner.Text()
+		line := original
 
 		for pattern, replacement := range resolvedReplacements {
 			if pattern.MatchString(line) {
 				line = pattern.ReplaceAllString(line, replacement)
+				changed = changed || line != original
 
 				// Apply only the first matching replacement
 				break
@@ -122,6 +128,11 @@ func (a *ReplaceLinesAction) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to read file %s: %w", a.FilePath, err)
 	}
 
+	// Leave the file untouched when no replacement altered it
+	if !changed {
+		return nil
+	}
+
 	file, err = os.Create(a.FilePath)
 	if err != nil {
 		a.Logger.Error("Failed to open file for writing",
@@ -151,6 +162,7 @@ func (a *ReplaceLinesAction) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to flush writer for file %s: %w", a.FilePath, err)
 	}
 
+	a.Changed = true
 	return nil
 }
 
@@ -158,5 +170,6 @@ func (a *ReplaceLinesAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, true, map[string]interface{}{
 		"filePath": a.FilePath,
 		"patterns": len(a.ReplacePatterns),
+		"changed":  a.Changed,
 	})
 }
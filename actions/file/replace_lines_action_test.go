@@ -567,6 +567,27 @@ func joinStrings(strs []string, separator string) string {
 }
 
 // TestReplaceLinesTestSuite runs the ReplaceLinesTestSuite.
+
+func (suite *ReplaceLinesTestSuite) TestNoChangeLeavesFileUntouched() {
+	// No trailing newline, which a rewrite would add
+	path := writeTestFile(suite.T(), "key=value")
+	defer os.Remove(path)
+
+	action, err := file.NewReplaceLinesAction(command_mock.NewDiscardLogger()).WithParameters(
+		engine.StaticParameter{Value: path},
+		map[*regexp.Regexp]engine.ActionParameter{
+			regexp.MustCompile("^key=.*$"): engine.StaticParameter{Value: "key=value"},
+		},
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(action.Wrapped.Execute(context.Background()))
+	suite.False(action.Wrapped.Changed)
+	content, err := os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.Equal("key=value", string(content))
+}
+
 func TestReplaceLinesTestSuite(t *testing.T) {
 	suite.Run(t, new(ReplaceLinesTestSuite))
 }
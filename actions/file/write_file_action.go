@@ -52,65 +52,32 @@ type WriteFileAction struct {
 	Content     engine.ActionParameter // Now supports ActionParameter
 	Overwrite   bool
 	InputBuffer *bytes.Buffer // Optional buffer to read content from
+	// Changed is true when the last run wrote the file
+	Changed bool
 	// Internal state for output
 	writtenContent []byte
 	writeError     error
 	PathParam      engine.ActionParameter // optional path parameter
 }
 
-func (a *WriteFileAction) Execute(execCtx context.Context) error {
-	// Resolve path parameter if provided using the ParameterResolver
-	effectivePath := a.FilePath
-	if a.PathParam != nil {
-		pathValue, err := a.ResolveStringParameter(execCtx, a.PathParam, "path")
-		if err != nil {
-			a.writeError = err
-			return a.writeError
-		}
-		effectivePath = pathValue
+// Check reports whether the file already exists with exactly the content
+// that would be written
+func (a *WriteFileAction) Check(execCtx context.Context) (bool, error) {
+	sanitizedPath, content, err := a.resolve(execCtx)
+	if err != nil {
+		return false, err
 	}
+	return fileHasContent(sanitizedPath, content)
+}
 
-	// Sanitize path to prevent path traversal attacks
-	sanitizedPath, err := SanitizePath(effectivePath)
+func (a *WriteFileAction) Execute(execCtx context.Context) error {
+	a.Changed = false
+	sanitizedPath, contentToWrite, err := a.resolve(execCtx)
 	if err != nil {
-		a.writeError = fmt.Errorf("invalid file path: %w", err)
+		a.writeError = err
 		return a.writeError
 	}
 
-	var contentToWrite []byte
-
-	// Resolve content parameter if provided using the ParameterResolver
-	if a.Content != nil {
-		resolvedContent, err := a.ResolveParameter(execCtx, a.Content, "content")
-		if err != nil {
-			a.writeError = err
-			return a.writeError
-		}
-
-		// Convert resolved content to bytes
-		switch v := resolvedContent.(type) {
-		case []byte:
-			contentToWrite = v
-		case string:
-			contentToWrite = []byte(v)
-		case *[]byte:
-			if v != nil {
-				contentToWrite = *v
-			}
-		default:
-			a.writeError = fmt.Errorf("unsupported content type: %T", resolvedContent)
-			return a.writeError
-		}
-	}
-
-	// Use input buffer if no content parameter resolved or if content is empty
-	if a.InputBuffer != nil && len(contentToWrite) == 0 {
-		contentToWrite = a.InputBuffer.Bytes()
-		a.Logger.Debug("Using content from input buffer", "buffer_length", len(contentToWrite))
-	} else if len(contentToWrite) > 0 {
-		a.Logger.Debug("Using resolved content", "content_length", len(contentToWrite))
-	}
-
 	// Allow empty content (empty files are valid)
 	// Store the content that was written for output
 	a.writtenContent = make([]byte, len(contentToWrite))
@@ -145,6 +112,7 @@ func (a *WriteFileAction) Execute(execCtx context.Context) error {
 		return a.writeError
 	}
 
+	a.Changed = true
 	a.Logger.Info("Successfully wrote file", "path", sanitizedPath)
 	return nil
 }
@@ -156,5 +124,58 @@ func (a *WriteFileAction) GetOutput() interface{} {
 		"contentLength": len(a.writtenContent),
 		"overwrite":     a.Overwrite,
 		"error":         a.writeError,
+		"changed":       a.Changed,
 	})
 }
+
+// resolve resolves the path and the content to write
+func (a *WriteFileAction) resolve(execCtx context.Context) (string, []byte, error) {
+	// Resolve path parameter if provided using the ParameterResolver
+	effectivePath := a.FilePath
+	if a.PathParam != nil {
+		pathValue, err := a.ResolveStringParameter(execCtx, a.PathParam, "path")
+		if err != nil {
+			return "", nil, err
+		}
+		effectivePath = pathValue
+	}
+
+	// Sanitize path to prevent path traversal attacks
+	sanitizedPath, err := SanitizePath(effectivePath)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid file path: %w", err)
+	}
+
+	var contentToWrite []byte
+
+	// Resolve content parameter if provided using the ParameterResolver
+	if a.Content != nil {
+		resolvedContent, err := a.ResolveParameter(execCtx, a.Content, "content")
+		if err != nil {
+			return "", nil, err
+		}
+
+		// Convert resolved content to bytes
+		switch v := resolvedContent.(type) {
+		case []byte:
+			contentToWrite = v
+		case string:
+			contentToWrite = []byte(v)
+		case *[]byte:
+			if v != nil {
+				contentToWrite = *v
+			}
+		default:
+			return "", nil, fmt.Errorf("unsupported content type: %T", resolvedContent)
+		}
+	}
+
+	// Use input buffer if no content parameter resolved or if content is empty
+	if a.InputBuffer != nil && len(contentToWrite) == 0 {
+		contentToWrite = a.InputBuffer.Bytes()
+		a.Logger.Debug("Using content from input buffer", "buffer_length", len(contentToWrite))
+	} else if len(contentToWrite) > 0 {
+		a.Logger.Debug("Using resolved content", "content_length", len(contentToWrite))
+	}
+	return sanitizedPath, contentToWrite, nil
+}
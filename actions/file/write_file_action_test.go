@@ -286,6 +286,32 @@ func (suite *WriteFileTestSuite) TestWriteFileAction_GetOutput() {
 	suite.Equal(true, m["success"]) // No writeError, so success is true
 }
 
+func (suite *WriteFileTestSuite) TestExecuteSkipsIdenticalContent() {
+	targetFile := filepath.Join(suite.tempDir, "config.txt")
+	suite.Require().NoError(os.WriteFile(targetFile, []byte("same"), 0o600))
+
+	action, err := file.NewWriteFileAction(command_mock.NewDiscardLogger()).WithParameters(
+		engine.StaticParameter{Value: targetFile},
+		engine.StaticParameter{Value: "same"},
+		true,
+		nil,
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.True(action.Skipped())
+	suite.Equal(false, action.GetOutput().(map[string]interface{})["changed"])
+
+	action.Wrapped.Content = engine.StaticParameter{Value: "different"}
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.False(action.Skipped())
+	suite.Equal(true, action.GetOutput().(map[string]interface{})["changed"])
+
+	content, err := os.ReadFile(targetFile)
+	suite.Require().NoError(err)
+	suite.Equal("different", string(content))
+}
+
 func TestWriteFileTestSuite(t *testing.T) {
 	suite.Run(t, new(WriteFileTestSuite))
 }
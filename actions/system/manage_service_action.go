@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	task_engine "github.com/ndizazzo/task-engine"
 	"github.com/ndizazzo/task-engine/actions/common"
@@ -33,6 +34,8 @@ type ManageServiceAction struct {
 	ServiceName      string
 	ActionType       string
 	CommandProcessor command.CommandRunner
+	// Changed is true when the last run ran systemctl
+	Changed bool
 }
 
 // WithParameters sets the parameters for service management and returns a wrapped Action
@@ -48,8 +51,43 @@ func (a *ManageServiceAction) WithParameters(
 	return constructor.WrapAction(a, "Manage Service", "manage-service-action"), nil
 }
 
+// Check reports whether a start or stop is unnecessary because the service is
+// already active or inactive. Restarts are always performed.
+func (a *ManageServiceAction) Check(execCtx context.Context) (bool, error) {
+	if err := a.resolve(execCtx); err != nil {
+		return false, err
+	}
+	if a.ActionType == "restart" {
+		return false, nil
+	}
+
+	// is-active exits non-zero for anything but an active unit, so only its
+	// output tells an inactive unit apart from a failure to ask
+	output, _ := a.CommandProcessor.RunCommandWithContext(execCtx, "systemctl", "is-active", a.ServiceName)
+	state := strings.TrimSpace(output)
+	if a.ActionType == "start" {
+		return state == "active", nil
+	}
+	return state == "inactive" || state == "failed", nil
+}
+
 func (a *ManageServiceAction) Execute(execCtx context.Context) error {
-	// Resolve required parameters
+	a.Changed = false
+	if err := a.resolve(execCtx); err != nil {
+		return err
+	}
+
+	_, err := a.CommandProcessor.RunCommandWithContext(execCtx, "systemctl", a.ActionType, a.ServiceName)
+	if err != nil {
+		return fmt.Errorf("failed to %s service %s: %w", a.ActionType, a.ServiceName, err)
+	}
+
+	a.Changed = true
+	return nil
+}
+
+// resolve resolves and validates the parameters
+func (a *ManageServiceAction) resolve(execCtx context.Context) error {
 	serviceName, err := a.ResolveStringParameter(execCtx, a.ServiceNameParam, "service name")
 	if err != nil {
 		return err
@@ -64,17 +102,10 @@ func (a *ManageServiceAction) Execute(execCtx context.Context) error {
 
 	switch a.ActionType {
 	case "start", "stop", "restart":
-		// valid
+		return nil
 	default:
 		return fmt.Errorf("invalid action type: %s; must be 'start', 'stop', or 'restart'", a.ActionType)
 	}
-
-	_, err = a.CommandProcessor.RunCommand("systemctl", a.ActionType, a.ServiceName)
-	if err != nil {
-		return fmt.Errorf("failed to %s service %s: %w", a.ActionType, a.ServiceName, err)
-	}
-
-	return nil
 }
 
 // GetOutput returns the service operation performed
@@ -82,5 +113,6 @@ func (a *ManageServiceAction) GetOutput() interface{} {
 	return a.BuildStandardOutput(nil, true, map[string]interface{}{
 		"service": a.ServiceName,
 		"action":  a.ActionType,
+		"changed": a.Changed,
 	})
 }
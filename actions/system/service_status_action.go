@@ -23,6 +23,11 @@ type ServiceStatus struct {
 	Exists      bool   `json:"exists"`
 }
 
+// ServiceStatusOutput holds the service statuses retrieved by ServiceStatusAction
+type ServiceStatusOutput struct {
+	Services []ServiceStatus
+}
+
 // NewServiceStatusAction creates a new ServiceStatusAction with the given logger
 func NewServiceStatusAction(logger *slog.Logger) *ServiceStatusAction {
 	return &ServiceStatusAction{
@@ -129,9 +134,19 @@ func (a *ServiceStatusAction) Execute(execCtx context.Context) error {
 func (a *ServiceStatusAction) GetOutput() interface{} {
 	return a.BuildOutputWithCount(a.ServiceStatuses, true, map[string]interface{}{
 		"services": a.ServiceStatuses,
+		"changed":  false,
 	})
 }
 
+var _ task_engine.ActionWithTypedOutput[ServiceStatusOutput] = (*ServiceStatusAction)(nil)
+
+// TypedOutput returns the same results as GetOutput as a ServiceStatusOutput
+func (a *ServiceStatusAction) TypedOutput() ServiceStatusOutput {
+	return ServiceStatusOutput{
+		Services: a.ServiceStatuses,
+	}
+}
+
 // getServiceStatus gets the status of a single service using systemctl show
 func (a *ServiceStatusAction) getServiceStatus(execCtx context.Context, serviceName string) (ServiceStatus, error) {
 	// Use systemctl show with specific properties for reliable parsing
@@ -80,13 +80,15 @@ func (a *ShutdownAction) Execute(ctx context.Context) error {
 	}
 
 	additionalFlags := shutdownArgs(operation, delay)
-	_, err := a.CommandProcessor.RunCommand("shutdown", additionalFlags...)
+	_, err := a.CommandProcessor.RunCommandWithContext(ctx, "shutdown", additionalFlags...)
 	return err
 }
 
 // GetOutput returns the requested shutdown operation and delay
 func (a *ShutdownAction) GetOutput() interface{} {
-	return a.BuildSimpleOutput(true, "")
+	output := a.BuildSimpleOutput(true, "")
+	output["changed"] = true
+	return output
 }
 
 func shutdownArgs(operation ShutdownCommandOperation, duration time.Duration) []string {
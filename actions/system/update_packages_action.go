@@ -70,6 +70,12 @@ type UpdatePackagesAction struct {
 	PackageNames   []string
 	PackageManager PackageManager
 	CommandRunner  command.CommandRunner
+	// OutputHandler optionally receives every line of package manager output
+	OutputHandler command.LineHandler
+	// Result of the last package manager command that was run
+	Result command.CommandResult
+	// Changed is false when the package manager reported nothing to install or upgrade
+	Changed bool
 
 	// Parameter-aware fields
 	PackageNamesParam   task_engine.ActionParameter
@@ -82,6 +88,7 @@ func (a *UpdatePackagesAction) SetCommandRunner(runner command.CommandRunner) {
 }
 
 func (a *UpdatePackagesAction) Execute(execCtx context.Context) error {
+	a.Changed = false
 	// Resolve package names parameter using the ParameterResolver
 	if a.PackageNamesParam != nil {
 		packageNamesValue, err := a.ResolveParameter(execCtx, a.PackageNamesParam, "package names")
@@ -145,9 +152,9 @@ func (a *UpdatePackagesAction) Execute(execCtx context.Context) error {
 func (a *UpdatePackagesAction) installWithApt(execCtx context.Context) error {
 	// First update package list
 	a.Logger.Info("Updating apt package list")
-	_, err := a.CommandRunner.RunCommandWithContext(execCtx, "apt", "update")
+	err := a.run(execCtx, "apt", "update")
 	if err != nil {
-		a.Logger.Error("Failed to update apt package list", "error", err)
+		a.Logger.Error("Failed to update apt package list", "error", err, "exitCode", a.Result.ExitCode, "stderr", a.Result.Stderr)
 		return fmt.Errorf("failed to update apt package list: %w", err)
 	}
 
@@ -155,15 +162,15 @@ func (a *UpdatePackagesAction) installWithApt(execCtx context.Context) error {
 	args := append([]string{"install", "-y"}, a.PackageNames...)
 	a.Logger.Info("Installing packages with apt", "packages", a.PackageNames)
 
-	output, err := a.CommandRunner.RunCommandWithContext(execCtx, "apt", args...)
-	if err != nil {
-		a.Logger.Error("Failed to install packages with apt", "packages", a.PackageNames, "error", err)
+	if err := a.run(execCtx, "apt", args...); err != nil {
+		a.Logger.Error("Failed to install packages with apt", "packages", a.PackageNames, "error", err, "exitCode", a.Result.ExitCode, "stderr", a.Result.Stderr)
 		return fmt.Errorf("failed to install packages with apt: %w", err)
 	}
 
+	a.Changed = !strings.Contains(a.Result.Stdout, "0 upgraded, 0 newly installed, 0 to remove")
 	a.Logger.Info("Successfully installed packages with apt",
 		"packages", a.PackageNames,
-		"output", output)
+		"output", a.Result.Stdout)
 	return nil
 }
 
@@ -173,23 +180,58 @@ func (a *UpdatePackagesAction) installWithBrew(execCtx context.Context) error {
 	args := append([]string{"install"}, a.PackageNames...)
 	a.Logger.Info("Installing packages with brew", "packages", a.PackageNames)
 
-	output, err := a.CommandRunner.RunCommandWithContext(execCtx, "brew", args...)
-	if err != nil {
-		a.Logger.Error("Failed to install packages with brew", "packages", a.PackageNames, "error", err)
+	if err := a.run(execCtx, "brew", args...); err != nil {
+		a.Logger.Error("Failed to install packages with brew", "packages", a.PackageNames, "error", err, "exitCode", a.Result.ExitCode, "stderr", a.Result.Stderr)
 		return fmt.Errorf("failed to install packages with brew: %w", err)
 	}
 
+	// brew warns once per package that is already installed and up to date
+	a.Changed = strings.Count(a.Result.Stdout+a.Result.Stderr, "is already installed") < len(a.PackageNames)
 	a.Logger.Info("Successfully installed packages with brew",
 		"packages", a.PackageNames,
-		"output", output)
+		"output", a.Result.Stdout)
 	return nil
 }
 
+// run executes a package manager command, recording its result and forwarding
+// its output to the logger, the OutputHandler and the task's progress as it arrives
+func (a *UpdatePackagesAction) run(ctx context.Context, name string, args ...string) error {
+	logLine := command.LogLines(a.Logger, "package manager output", "packageManager", string(a.PackageManager))
+	result, err := command.RunWithOptions(ctx, a.CommandRunner, command.Cmd{
+		Name: name,
+		Args: args,
+		OnLine: func(stream command.OutputStream, line string) {
+			logLine(stream, line)
+			if a.OutputHandler != nil {
+				a.OutputHandler(stream, line)
+			}
+			task_engine.ReportActionProgress(ctx, task_engine.ActionProgress{Message: line})
+		},
+	})
+	a.Result = result
+	return err
+}
+
+// ResourceLocks serializes runs of the same package manager across tasks
+func (a *UpdatePackagesAction) ResourceLocks() []string {
+	manager := a.PackageManager
+	if value, ok := common.StaticString(a.PackageManagerParam); ok {
+		manager = PackageManager(value)
+	}
+	if manager == "" {
+		return nil
+	}
+	return []string{string(manager)}
+}
+
 // GetOutput returns information about attempted package installation
 func (a *UpdatePackagesAction) GetOutput() interface{} {
-	return a.BuildStandardOutput(nil, true, map[string]interface{}{
+	return a.BuildStandardOutput(nil, a.Result.Success(), map[string]interface{}{
 		"packages":       a.PackageNames,
 		"packageManager": string(a.PackageManager),
+		"stderr":         a.Result.Stderr,
+		"exitCode":       a.Result.ExitCode,
+		"changed":        a.Changed,
 	})
 }
 
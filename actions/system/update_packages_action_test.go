@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/command"
 	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -37,6 +39,15 @@ func (suite *UpdatePackagesActionTestSuite) TestNewUpdatePackagesActionConstruct
 	suite.NotNil(action.Wrapped)
 }
 
+func (suite *UpdatePackagesActionTestSuite) TestResourceLocks() {
+	action, err := NewUpdatePackagesAction(mocks.NewDiscardLogger()).WithParameters(
+		task_engine.StaticParameter{Value: []string{"curl"}},
+		task_engine.StaticParameter{Value: "apt"},
+	)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"apt"}, action.ResourceLocks())
+}
+
 func (suite *UpdatePackagesActionTestSuite) TestNewUpdatePackagesActionConstructor_WithNilLogger() {
 	constructor := NewUpdatePackagesAction(nil)
 	action, err := constructor.WithParameters(
@@ -70,10 +81,29 @@ func (suite *UpdatePackagesActionTestSuite) TestNewUpdatePackagesActionConstruct
 	suite.NoError(err)
 	suite.Equal([]string{"curl", "wget"}, action.Wrapped.PackageNames)
 	suite.Equal(AptPackageManager, action.Wrapped.PackageManager)
+	suite.True(action.Wrapped.Changed)
 
 	mockRunner.AssertExpectations(suite.T())
 }
 
+func (suite *UpdatePackagesActionTestSuite) TestExecute_AptNothingToDo() {
+	mockRunner := &mocks.MockCommandRunner{}
+	mockRunner.On("RunCommandWithContext", context.Background(), "apt", "update").Return("Reading package lists... Done", nil)
+	mockRunner.On("RunCommandWithContext", context.Background(), "apt", "install", "-y", "curl").
+		Return("curl is already the newest version (7.88.1-10).\n0 upgraded, 0 newly installed, 0 to remove and 3 not upgraded.", nil)
+
+	action, err := NewUpdatePackagesAction(mocks.NewDiscardLogger()).WithParameters(
+		task_engine.StaticParameter{Value: []string{"curl"}},
+		task_engine.StaticParameter{Value: "apt"},
+	)
+	suite.Require().NoError(err)
+	action.Wrapped.SetCommandRunner(mockRunner)
+
+	suite.Require().NoError(action.Wrapped.Execute(context.Background()))
+	suite.False(action.Wrapped.Changed)
+	suite.Equal(false, action.Wrapped.GetOutput().(map[string]interface{})["changed"])
+}
+
 func (suite *UpdatePackagesActionTestSuite) TestNewUpdatePackagesActionConstructor_Execute_WithBrewManager() {
 	logger := mocks.NewDiscardLogger()
 
@@ -401,3 +431,49 @@ func (suite *UpdatePackagesActionTestSuite) TestNewUpdatePackagesActionConstruct
 
 	mockRunner.AssertExpectations(suite.T())
 }
+
+func (suite *UpdatePackagesActionTestSuite) TestExecute_StreamsOutputLines() {
+	logger := mocks.NewDiscardLogger()
+
+	mockRunner := &mocks.MockStreamingCommandRunner{}
+	mockRunner.On("RunCommandStream", context.Background(), "brew", "install", "jq").Return("==> Downloading jq\n==> Pouring jq", nil)
+
+	action, err := NewUpdatePackagesAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: []string{"jq"}},
+		task_engine.StaticParameter{Value: "brew"},
+	)
+	suite.Require().NoError(err)
+	action.Wrapped.SetCommandRunner(mockRunner)
+
+	var lines []string
+	action.Wrapped.OutputHandler = func(stream command.OutputStream, line string) {
+		suite.Equal(command.Stdout, stream)
+		lines = append(lines, line)
+	}
+
+	suite.NoError(action.Wrapped.Execute(context.Background()))
+	suite.Equal([]string{"==> Downloading jq", "==> Pouring jq"}, lines)
+	mockRunner.AssertExpectations(suite.T())
+}
+
+func (suite *UpdatePackagesActionTestSuite) TestExecute_FailureSurfacesExitCode() {
+	logger := mocks.NewDiscardLogger()
+
+	mockRunner := &mocks.MockCommandRunner{}
+	mockRunner.On("RunCommandWithOptions", context.Background(), mock.MatchedBy(func(cmd command.Cmd) bool {
+		return cmd.Name == "apt" && cmd.Args[0] == "update"
+	})).Return(command.CommandResult{Stderr: "E: Could not open lock file\n", ExitCode: 100}, errors.New("exit status 100"))
+
+	action, err := NewUpdatePackagesAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: []string{"curl"}},
+		task_engine.StaticParameter{Value: "apt"},
+	)
+	suite.Require().NoError(err)
+	action.Wrapped.SetCommandRunner(mockRunner)
+
+	suite.Error(action.Wrapped.Execute(context.Background()))
+	output := action.Wrapped.GetOutput().(map[string]interface{})
+	suite.Equal(100, output["exitCode"])
+	suite.Equal("E: Could not open lock file\n", output["stderr"])
+	suite.Equal(false, output["success"])
+}
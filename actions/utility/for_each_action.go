@@ -0,0 +1,160 @@
+package utility
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/actions/common"
+)
+
+// ForEachTemplate builds the sub-action for a single element of the resolved list.
+// The element and its index are passed as parameters so they can be handed
+// straight to another action's WithParameters.
+type ForEachTemplate func(item task_engine.ActionParameter, index task_engine.ActionParameter) (task_engine.ActionWrapper, error)
+
+// ForEachAction resolves a slice parameter and runs a sub-action per element,
+// either sequentially or with bounded parallelism.
+type ForEachAction struct {
+	task_engine.BaseAction
+	common.ParameterResolver
+	common.OutputBuilder
+	// Parameter fields
+	ItemsParam       task_engine.ActionParameter
+	ConcurrencyParam task_engine.ActionParameter
+	// Template used to instantiate the sub-action for each element
+	Template ForEachTemplate
+	// Runtime resolved values
+	Items       []interface{}
+	Concurrency int
+	// Execution results
+	Outputs map[int]interface{}
+	// Failed holds the indexes of the items whose sub-action failed
+	Failed []int
+}
+
+// NewForEachAction creates a new ForEachAction with the given logger
+func NewForEachAction(logger *slog.Logger) *ForEachAction {
+	return &ForEachAction{
+		BaseAction:        task_engine.NewBaseAction(logger),
+		ParameterResolver: *common.NewParameterResolver(logger),
+		OutputBuilder:     *common.NewOutputBuilder(logger),
+	}
+}
+
+// WithParameters sets the list to iterate, the optional concurrency limit and the
+// sub-action template, and returns a wrapped Action. A nil concurrency parameter
+// (or a value below 2) runs the items sequentially.
+func (a *ForEachAction) WithParameters(
+	itemsParam task_engine.ActionParameter,
+	concurrencyParam task_engine.ActionParameter,
+	template ForEachTemplate,
+) (*task_engine.Action[*ForEachAction], error) {
+	if template == nil {
+		return nil, fmt.Errorf("for-each template cannot be nil")
+	}
+	a.ItemsParam = itemsParam
+	a.ConcurrencyParam = concurrencyParam
+	a.Template = template
+
+	constructor := common.NewBaseConstructor[*ForEachAction](a.Logger)
+	return constructor.WrapAction(a, "For Each", "for-each-action"), nil
+}
+
+func (a *ForEachAction) Execute(ctx context.Context) error {
+	a.Failed = nil
+	items, err := a.ResolveSliceParameter(ctx, a.ItemsParam, "items")
+	if err != nil {
+		return err
+	}
+	a.Items = items
+
+	a.Concurrency = 1
+	if a.ConcurrencyParam != nil {
+		concurrency, err := a.ResolveIntParameter(ctx, a.ConcurrencyParam, "concurrency")
+		if err != nil {
+			return err
+		}
+		if concurrency > 1 {
+			a.Concurrency = concurrency
+		}
+	}
+
+	// Build every sub-action up front so template errors surface before anything runs
+	subActions := make([]task_engine.ActionWrapper, len(items))
+	for i, item := range items {
+		sub, err := a.Template(task_engine.StaticParameter{Value: item}, task_engine.StaticParameter{Value: i})
+		if err != nil {
+			a.Failed = append(a.Failed, i)
+			return fmt.Errorf("failed to build sub-action for item %d: %w", i, err)
+		}
+		if sub == nil {
+			a.Failed = append(a.Failed, i)
+			return fmt.Errorf("template returned a nil sub-action for item %d", i)
+		}
+		subActions[i] = sub
+	}
+
+	a.Logger.Info("Running for-each sub-actions", "count", len(subActions), "concurrency", a.Concurrency)
+	a.Outputs = make(map[int]interface{}, len(subActions))
+
+	if a.Concurrency == 1 {
+		for i, sub := range subActions {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := sub.Execute(task_engine.WithChildAction(ctx, sub.GetID())); err != nil {
+				a.Logger.Error("For-each sub-action failed", "index", i, "actionID", sub.GetID(), "error", err)
+				a.Failed = append(a.Failed, i)
+				return fmt.Errorf("for-each item %d (%s) failed: %w", i, sub.GetID(), err)
+			}
+			a.Outputs[i] = sub.GetOutput()
+		}
+		return nil
+	}
+
+	return a.executeParallel(ctx, subActions)
+}
+
+// executeParallel runs the sub-actions with at most Concurrency in flight. The
+// first failure cancels the remaining items and is returned.
+func (a *ForEachAction) executeParallel(ctx context.Context, subActions []task_engine.ActionWrapper) error {
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	runConcurrently(ctx, subActions, a.Concurrency, func(i int, sub task_engine.ActionWrapper, err error) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			a.Logger.Error("For-each sub-action failed", "index", i, "actionID", sub.GetID(), "error", err)
+			a.Failed = append(a.Failed, i)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("for-each item %d (%s) failed: %w", i, sub.GetID(), err)
+			}
+			return true
+		}
+		a.Outputs[i] = sub.GetOutput()
+		return false
+	})
+
+	if firstErr != nil {
+		sort.Ints(a.Failed)
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// GetOutput returns the per-item outputs keyed by index and the indexes of
+// the failed items
+func (a *ForEachAction) GetOutput() interface{} {
+	return map[string]interface{}{
+		"outputs": a.Outputs,
+		"failed":  append([]int(nil), a.Failed...),
+		"count":   len(a.Items),
+		"success": len(a.Failed) == 0,
+	}
+}
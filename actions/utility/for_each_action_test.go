@@ -0,0 +1,217 @@
+package utility_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/actions/utility"
+	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingItemAction captures the item and index it was built with
+type recordingItemAction struct {
+	task_engine.BaseAction
+	ItemParam  task_engine.ActionParameter
+	IndexParam task_engine.ActionParameter
+	Delay      time.Duration
+	Fail       bool
+	OnExecute  func()
+	OnDone     func()
+	item       string
+	index      int
+}
+
+func (a *recordingItemAction) Execute(ctx context.Context) error {
+	gc, _ := ctx.Value(task_engine.GlobalContextKey).(*task_engine.GlobalContext)
+	item, err := task_engine.ResolveString(ctx, a.ItemParam, gc)
+	if err != nil {
+		return err
+	}
+	index, err := task_engine.ResolveAs[int](ctx, a.IndexParam, gc)
+	if err != nil {
+		return err
+	}
+	a.item, a.index = item, index
+	if a.OnExecute != nil {
+		a.OnExecute()
+	}
+	if a.OnDone != nil {
+		defer a.OnDone()
+	}
+	if a.Delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.Delay):
+		}
+	}
+	if a.Fail {
+		return errors.New("item failed")
+	}
+	return nil
+}
+
+func (a *recordingItemAction) GetOutput() interface{} {
+	return map[string]interface{}{"item": a.item, "index": a.index}
+}
+
+type ForEachActionTestSuite struct {
+	suite.Suite
+}
+
+func TestForEachActionTestSuite(t *testing.T) {
+	suite.Run(t, new(ForEachActionTestSuite))
+}
+
+func (suite *ForEachActionTestSuite) TestSequentialCollectsOutputsByIndex() {
+	logger := mocks.NewDiscardLogger()
+	var order []string
+	var mu sync.Mutex
+
+	action, err := utility.NewForEachAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: []string{"nginx", "redis", "postgres"}},
+		nil,
+		func(item, index task_engine.ActionParameter) (task_engine.ActionWrapper, error) {
+			sub := &recordingItemAction{BaseAction: task_engine.NewBaseAction(logger), ItemParam: item, IndexParam: index}
+			sub.OnExecute = func() {
+				mu.Lock()
+				order = append(order, sub.item)
+				mu.Unlock()
+			}
+			return task_engine.NewAction(sub, "Restart Service", logger), nil
+		},
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.Equal([]string{"nginx", "redis", "postgres"}, order)
+
+	out := action.GetOutput().(map[string]interface{})
+	suite.Equal(3, out["count"])
+	outputs := out["outputs"].(map[int]interface{})
+	suite.Len(outputs, 3)
+	suite.Equal(map[string]interface{}{"item": "redis", "index": 1}, outputs[1])
+	suite.Equal(true, out["success"])
+	suite.Empty(out["failed"])
+}
+
+func (suite *ForEachActionTestSuite) TestParallelRespectsConcurrencyLimit() {
+	logger := mocks.NewDiscardLogger()
+	var inFlight, maxInFlight int32
+
+	action, err := utility.NewForEachAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: []int{1, 2, 3, 4, 5, 6}},
+		task_engine.StaticParameter{Value: 2},
+		func(item, index task_engine.ActionParameter) (task_engine.ActionWrapper, error) {
+			sub := &recordingItemAction{
+				BaseAction: task_engine.NewBaseAction(logger),
+				ItemParam:  item,
+				IndexParam: index,
+				Delay:      20 * time.Millisecond,
+			}
+			sub.OnExecute = func() {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+			}
+			sub.OnDone = func() { atomic.AddInt32(&inFlight, -1) }
+			return task_engine.NewAction(sub, "Item", logger), nil
+		},
+	)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(action.Execute(context.Background()))
+	suite.LessOrEqual(atomic.LoadInt32(&maxInFlight), int32(2))
+	suite.Len(action.Wrapped.Outputs, 6)
+	suite.Equal(2, action.Wrapped.Concurrency)
+}
+
+func (suite *ForEachActionTestSuite) TestFailureStopsIteration() {
+	logger := mocks.NewDiscardLogger()
+	var executed int32
+
+	action, err := utility.NewForEachAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: []string{"a", "b", "c"}},
+		nil,
+		func(item, index task_engine.ActionParameter) (task_engine.ActionWrapper, error) {
+			i, _ := index.Resolve(context.Background(), nil)
+			sub := &recordingItemAction{
+				BaseAction: task_engine.NewBaseAction(logger),
+				ItemParam:  item,
+				IndexParam: index,
+				Fail:       i.(int) == 1,
+				OnExecute:  func() { atomic.AddInt32(&executed, 1) },
+			}
+			return task_engine.NewAction(sub, "Item", logger), nil
+		},
+	)
+	suite.Require().NoError(err)
+
+	execErr := action.Execute(context.Background())
+	suite.Error(execErr)
+	suite.Contains(execErr.Error(), "for-each item 1")
+	suite.Equal(int32(2), atomic.LoadInt32(&executed))
+
+	out := action.GetOutput().(map[string]interface{})
+	suite.Equal(false, out["success"])
+	suite.Equal([]int{1}, out["failed"])
+}
+
+func (suite *ForEachActionTestSuite) TestItemsFromPreviousActionOutput() {
+	logger := mocks.NewDiscardLogger()
+	gc := task_engine.NewGlobalContext()
+	gc.StoreActionOutput("list-files", map[string]interface{}{"files": []string{"a.txt", "b.txt"}})
+
+	action, err := utility.NewForEachAction(logger).WithParameters(
+		task_engine.ActionOutputField("list-files", "files"),
+		nil,
+		func(item, index task_engine.ActionParameter) (task_engine.ActionWrapper, error) {
+			sub := &recordingItemAction{BaseAction: task_engine.NewBaseAction(logger), ItemParam: item, IndexParam: index}
+			return task_engine.NewAction(sub, "Copy File", logger), nil
+		},
+	)
+	suite.Require().NoError(err)
+
+	ctx := context.WithValue(context.Background(), task_engine.GlobalContextKey, gc)
+	suite.Require().NoError(action.Execute(ctx))
+	suite.Equal(map[string]interface{}{"item": "b.txt", "index": 1}, action.Wrapped.Outputs[1])
+}
+
+func (suite *ForEachActionTestSuite) TestInvalidInputs() {
+	logger := mocks.NewDiscardLogger()
+
+	_, err := utility.NewForEachAction(logger).WithParameters(task_engine.StaticParameter{Value: []string{}}, nil, nil)
+	suite.Error(err)
+
+	action, err := utility.NewForEachAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: "not-a-slice"},
+		nil,
+		func(item, index task_engine.ActionParameter) (task_engine.ActionWrapper, error) {
+			return nil, nil
+		},
+	)
+	suite.Require().NoError(err)
+	suite.Error(action.Execute(context.Background()))
+
+	action, err = utility.NewForEachAction(logger).WithParameters(
+		task_engine.StaticParameter{Value: []string{"x"}},
+		nil,
+		func(item, index task_engine.ActionParameter) (task_engine.ActionWrapper, error) {
+			return nil, errors.New("bad template")
+		},
+	)
+	suite.Require().NoError(err)
+	execErr := action.Execute(context.Background())
+	suite.Error(execErr)
+	suite.Contains(execErr.Error(), "bad template")
+}
@@ -0,0 +1,215 @@
+package utility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/actions/common"
+)
+
+// ParallelMode controls how a ParallelGroupAction reacts to a failing child
+type ParallelMode string
+
+const (
+	// ParallelFailFast cancels the remaining children on the first error
+	ParallelFailFast ParallelMode = "fail-fast"
+	// ParallelCollectAll waits for every child and returns all errors joined
+	ParallelCollectAll ParallelMode = "collect-all"
+)
+
+// ParallelGroupOption is a function type for configuring ParallelGroupAction
+type ParallelGroupOption func(*ParallelGroupAction)
+
+// WithGroupConcurrency limits how many children run at once (0 means no limit)
+func WithGroupConcurrency(limit int) ParallelGroupOption {
+	return func(a *ParallelGroupAction) {
+		a.Concurrency = limit
+	}
+}
+
+// WithGroupMode sets the failure handling mode
+func WithGroupMode(mode ParallelMode) ParallelGroupOption {
+	return func(a *ParallelGroupAction) {
+		a.Mode = mode
+	}
+}
+
+// ParallelGroupAction runs several actions concurrently as a single step of a
+// task. Each child's output is stored under its own ID in the GlobalContext.
+type ParallelGroupAction struct {
+	task_engine.BaseAction
+	// Children to run concurrently
+	Actions []task_engine.ActionWrapper
+	// Configuration
+	Concurrency int
+	Mode        ParallelMode
+	// Execution results
+	Completed []string
+	Failed    []string
+	mu        sync.Mutex
+}
+
+// NewParallelGroupAction creates a new ParallelGroupAction with the given logger
+func NewParallelGroupAction(logger *slog.Logger) *ParallelGroupAction {
+	return &ParallelGroupAction{
+		BaseAction: task_engine.NewBaseAction(logger),
+		Mode:       ParallelFailFast,
+	}
+}
+
+// WithParameters sets the children and options and returns a wrapped Action
+func (a *ParallelGroupAction) WithParameters(
+	actions []task_engine.ActionWrapper,
+	opts ...ParallelGroupOption,
+) (*task_engine.Action[*ParallelGroupAction], error) {
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("parallel group requires at least one action")
+	}
+	for i, child := range actions {
+		if child == nil {
+			return nil, fmt.Errorf("parallel group action %d is nil", i)
+		}
+	}
+	a.Actions = actions
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.Mode != ParallelFailFast && a.Mode != ParallelCollectAll {
+		return nil, fmt.Errorf("invalid parallel group mode %q", a.Mode)
+	}
+	if a.Concurrency < 0 {
+		return nil, fmt.Errorf("parallel group concurrency cannot be negative")
+	}
+
+	constructor := common.NewBaseConstructor[*ParallelGroupAction](a.Logger)
+	return constructor.WrapAction(a, "Parallel Group", "parallel-group-action"), nil
+}
+
+func (a *ParallelGroupAction) Execute(ctx context.Context) error {
+	globalContext, ok := ctx.Value(task_engine.GlobalContextKey).(*task_engine.GlobalContext)
+	if !ok {
+		globalContext = task_engine.NewGlobalContext()
+		ctx = context.WithValue(ctx, task_engine.GlobalContextKey, globalContext)
+	}
+
+	limit := a.Concurrency
+	if limit == 0 || limit > len(a.Actions) {
+		limit = len(a.Actions)
+	}
+
+	a.mu.Lock()
+	a.Completed = nil
+	a.Failed = nil
+	a.mu.Unlock()
+
+	a.Logger.Info("Running parallel group", "count", len(a.Actions), "concurrency", limit, "mode", a.Mode)
+
+	errs := make([]error, len(a.Actions))
+	runConcurrently(ctx, a.Actions, limit, func(i int, child task_engine.ActionWrapper, err error) bool {
+		if err != nil {
+			a.Logger.Error("Parallel action failed", "actionID", child.GetID(), "error", err)
+			errs[i] = fmt.Errorf("parallel action %s failed: %w", child.GetID(), err)
+			a.mu.Lock()
+			a.Failed = append(a.Failed, child.GetID())
+			a.mu.Unlock()
+			return a.Mode == ParallelFailFast
+		}
+
+		storeChildOutput(child, globalContext)
+		a.mu.Lock()
+		a.Completed = append(a.Completed, child.GetID())
+		a.mu.Unlock()
+		return false
+	})
+
+	if a.Mode == ParallelFailFast {
+		// Report the failure that triggered cancellation rather than the siblings' context errors
+		for _, err := range errs {
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+		}
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// runConcurrently executes actions with at most limit of them in flight, each
+// with its own ID as the executing action (see WithChildAction). done is
+// called as each action finishes, possibly concurrently; returning true
+// cancels the actions still running and skips those not yet started.
+func runConcurrently(ctx context.Context, actions []task_engine.ActionWrapper, limit int, done func(i int, action task_engine.ActionWrapper, err error) (stop bool)) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i, action := range actions {
+		select {
+		case sem <- struct{}{}:
+		case <-runCtx.Done():
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, action task_engine.ActionWrapper) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := action.Execute(task_engine.WithChildAction(runCtx, action.GetID()))
+			if done(i, action, err) {
+				cancel()
+			}
+		}(i, action)
+	}
+	wg.Wait()
+}
+
+// storeChildOutput mirrors what a Task does after each action so that children
+// of the group can be referenced by their own IDs
+func storeChildOutput(child task_engine.ActionWrapper, globalContext *task_engine.GlobalContext) {
+	if output := child.GetOutput(); output != nil {
+		globalContext.StoreActionOutput(child.GetID(), output)
+	}
+	if provider, ok := child.(task_engine.TypedOutputProvider); ok {
+		if typed, ok := provider.TypedOutputValue(); ok {
+			globalContext.StoreTypedActionOutput(child.GetID(), typed)
+		}
+	}
+	if resultProvider, ok := child.(task_engine.ResultProvider); ok {
+		globalContext.StoreActionResult(child.GetID(), resultProvider)
+	}
+}
+
+// ResourceLocks returns the locks declared by the group's children, sorted
+// and without duplicates
+func (a *ParallelGroupAction) ResourceLocks() []string {
+	return task_engine.ResourceLocksOf(a.Actions...)
+}
+
+// GetOutput returns the IDs of completed and failed children
+func (a *ParallelGroupAction) GetOutput() interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return map[string]interface{}{
+		"completed": append([]string(nil), a.Completed...),
+		"failed":    append([]string(nil), a.Failed...),
+		"count":     len(a.Actions),
+		"success":   len(a.Failed) == 0,
+	}
+}
@@ -0,0 +1,223 @@
+package utility_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/actions/utility"
+	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/suite"
+)
+
+// groupChildAction waits for Delay (or cancellation) and then returns Err
+type groupChildAction struct {
+	task_engine.BaseAction
+	Delay    time.Duration
+	Err      error
+	Output   map[string]interface{}
+	inFlight *int32
+	maxSeen  *int32
+	canceled *int32
+}
+
+func (a *groupChildAction) Execute(ctx context.Context) error {
+	if a.inFlight != nil {
+		n := atomic.AddInt32(a.inFlight, 1)
+		defer atomic.AddInt32(a.inFlight, -1)
+		for {
+			m := atomic.LoadInt32(a.maxSeen)
+			if n <= m || atomic.CompareAndSwapInt32(a.maxSeen, m, n) {
+				break
+			}
+		}
+	}
+	select {
+	case <-ctx.Done():
+		if a.canceled != nil {
+			atomic.AddInt32(a.canceled, 1)
+		}
+		return ctx.Err()
+	case <-time.After(a.Delay):
+	}
+	return a.Err
+}
+
+func (a *groupChildAction) GetOutput() interface{} { return a.Output }
+
+type ParallelGroupActionTestSuite struct {
+	suite.Suite
+}
+
+func TestParallelGroupActionTestSuite(t *testing.T) {
+	suite.Run(t, new(ParallelGroupActionTestSuite))
+}
+
+func (suite *ParallelGroupActionTestSuite) child(id string, delay time.Duration, err error) *task_engine.Action[*groupChildAction] {
+	logger := mocks.NewDiscardLogger()
+	return task_engine.NewAction(&groupChildAction{
+		BaseAction: task_engine.NewBaseAction(logger),
+		Delay:      delay,
+		Err:        err,
+		Output:     map[string]interface{}{"id": id},
+	}, id, logger, id)
+}
+
+func (suite *ParallelGroupActionTestSuite) TestOutputsStoredUnderChildIDs() {
+	logger := mocks.NewDiscardLogger()
+	group, err := utility.NewParallelGroupAction(logger).WithParameters([]task_engine.ActionWrapper{
+		suite.child("pull-nginx", 5*time.Millisecond, nil),
+		suite.child("pull-redis", 5*time.Millisecond, nil),
+	})
+	suite.Require().NoError(err)
+
+	task := &task_engine.Task{ID: "pull-all", Logger: logger, Actions: []task_engine.ActionWrapper{group}}
+	gc := task_engine.NewGlobalContext()
+	suite.Require().NoError(task.RunWithContext(context.Background(), gc))
+
+	for _, id := range []string{"pull-nginx", "pull-redis"} {
+		v, err := task_engine.ActionOutputFieldAs[string](gc, id, "id")
+		suite.Require().NoError(err)
+		suite.Equal(id, v)
+	}
+	completed, err := task_engine.ActionOutputFieldAs[[]string](gc, "parallel-group-action", "completed")
+	suite.Require().NoError(err)
+	suite.ElementsMatch([]string{"pull-nginx", "pull-redis"}, completed)
+}
+
+// executionRecordingAction records the action ID it runs as and reports progress
+type executionRecordingAction struct {
+	task_engine.BaseAction
+	RanAs string
+}
+
+func (a *executionRecordingAction) Execute(ctx context.Context) error {
+	info, _ := task_engine.ExecutionFromContext(ctx)
+	a.RanAs = info.ActionID
+	task_engine.ReportProgress(ctx, 1, 1, "steps")
+	return nil
+}
+
+func (suite *ParallelGroupActionTestSuite) TestChildrenRunAsThemselves() {
+	logger := mocks.NewDiscardLogger()
+	children := map[string]*executionRecordingAction{}
+	var wrapped []task_engine.ActionWrapper
+	for _, id := range []string{"pull-nginx", "pull-redis"} {
+		children[id] = &executionRecordingAction{BaseAction: task_engine.NewBaseAction(logger)}
+		child := task_engine.NewAction(children[id], id, logger, id)
+		child.Locks = []string{"docker"}
+		wrapped = append(wrapped, child)
+	}
+	group, err := utility.NewParallelGroupAction(logger).WithParameters(wrapped)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"docker"}, group.ResourceLocks())
+
+	var mu sync.Mutex
+	progressFrom := map[string]bool{}
+	task := &task_engine.Task{
+		ID:      "pull-all",
+		Logger:  logger,
+		Actions: []task_engine.ActionWrapper{group},
+		EventListener: func(event task_engine.TaskEvent) {
+			if event.Type == task_engine.EventActionProgress {
+				mu.Lock()
+				progressFrom[event.ActionID] = true
+				mu.Unlock()
+			}
+		},
+	}
+	suite.Require().NoError(task.Run(context.Background()))
+
+	for id, child := range children {
+		suite.Equal(id, child.RanAs)
+		suite.True(progressFrom[id], "progress of %s is reported under its own ID", id)
+	}
+}
+
+func (suite *ParallelGroupActionTestSuite) TestFailFastCancelsSiblings() {
+	logger := mocks.NewDiscardLogger()
+	boom := errors.New("boom")
+	var canceled int32
+
+	slow := suite.child("slow", time.Second, nil)
+	slow.Wrapped.canceled = &canceled
+	group, err := utility.NewParallelGroupAction(logger).WithParameters([]task_engine.ActionWrapper{
+		suite.child("fails", 5*time.Millisecond, boom),
+		slow,
+	})
+	suite.Require().NoError(err)
+
+	start := time.Now()
+	execErr := group.Execute(context.Background())
+	suite.Less(time.Since(start), 500*time.Millisecond)
+	suite.ErrorIs(execErr, boom)
+	suite.Contains(execErr.Error(), "parallel action fails failed")
+	suite.Equal(int32(1), atomic.LoadInt32(&canceled))
+}
+
+func (suite *ParallelGroupActionTestSuite) TestCollectAllJoinsErrors() {
+	logger := mocks.NewDiscardLogger()
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+
+	group, err := utility.NewParallelGroupAction(logger).WithParameters(
+		[]task_engine.ActionWrapper{
+			suite.child("a", 5*time.Millisecond, errA),
+			suite.child("b", 10*time.Millisecond, errB),
+			suite.child("c", 15*time.Millisecond, nil),
+		},
+		utility.WithGroupMode(utility.ParallelCollectAll),
+	)
+	suite.Require().NoError(err)
+
+	execErr := group.Execute(context.Background())
+	suite.ErrorIs(execErr, errA)
+	suite.ErrorIs(execErr, errB)
+
+	out := group.GetOutput().(map[string]interface{})
+	suite.Equal([]string{"c"}, out["completed"])
+	suite.ElementsMatch([]string{"a", "b"}, out["failed"])
+	suite.Equal(false, out["success"])
+}
+
+func (suite *ParallelGroupActionTestSuite) TestConcurrencyLimit() {
+	logger := mocks.NewDiscardLogger()
+	var inFlight, maxSeen int32
+
+	children := make([]task_engine.ActionWrapper, 0, 5)
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		c := suite.child(id, 10*time.Millisecond, nil)
+		c.Wrapped.inFlight = &inFlight
+		c.Wrapped.maxSeen = &maxSeen
+		children = append(children, c)
+	}
+	group, err := utility.NewParallelGroupAction(logger).WithParameters(children, utility.WithGroupConcurrency(2))
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(group.Execute(context.Background()))
+	suite.LessOrEqual(atomic.LoadInt32(&maxSeen), int32(2))
+	suite.Len(group.Wrapped.Completed, 5)
+}
+
+func (suite *ParallelGroupActionTestSuite) TestInvalidConfiguration() {
+	logger := mocks.NewDiscardLogger()
+
+	_, err := utility.NewParallelGroupAction(logger).WithParameters(nil)
+	suite.Error(err)
+
+	_, err = utility.NewParallelGroupAction(logger).WithParameters(
+		[]task_engine.ActionWrapper{suite.child("a", 0, nil)},
+		utility.WithGroupMode("sometimes"),
+	)
+	suite.Error(err)
+
+	_, err = utility.NewParallelGroupAction(logger).WithParameters(
+		[]task_engine.ActionWrapper{suite.child("a", 0, nil)},
+		utility.WithGroupConcurrency(-1),
+	)
+	suite.Error(err)
+}
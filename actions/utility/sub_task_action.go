@@ -0,0 +1,96 @@
+package utility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/actions/common"
+)
+
+// failedActionReporter is implemented by tasks that record which action failed
+type failedActionReporter interface {
+	GetFailedActionID() string
+}
+
+// SubTaskAction runs a whole task as a single action of its parent. The child
+// shares the parent's GlobalContext and cancellation, and its task output
+// becomes the output of this action.
+//
+// The SubTaskAction owns the embedded task: a Task holds the state of one run,
+// so it must not be embedded twice or run elsewhere at the same time. A run
+// started while another is in progress fails with ErrTaskRunning.
+type SubTaskAction struct {
+	task_engine.BaseAction
+	// Task to run as part of the parent task
+	Task task_engine.TaskInterface
+	// Execution results
+	Output interface{}
+}
+
+// NewSubTaskAction creates a new SubTaskAction with the given logger
+func NewSubTaskAction(logger *slog.Logger) *SubTaskAction {
+	return &SubTaskAction{
+		BaseAction: task_engine.NewBaseAction(logger),
+	}
+}
+
+// WithParameters sets the task to embed and returns a wrapped Action. The task
+// is used as given; set its Logger to see the child's own log lines.
+func (a *SubTaskAction) WithParameters(task task_engine.TaskInterface) (*task_engine.Action[*SubTaskAction], error) {
+	if task == nil {
+		return nil, fmt.Errorf("sub-task cannot be nil")
+	}
+	a.Task = task
+
+	constructor := common.NewBaseConstructor[*SubTaskAction](a.Logger)
+	return constructor.WrapAction(a, "Sub Task", task_engine.BuildActionID("sub-task", task.GetID())), nil
+}
+
+func (a *SubTaskAction) Execute(ctx context.Context) error {
+	if a.Task == nil {
+		return fmt.Errorf("sub-task is not defined")
+	}
+
+	globalContext, ok := ctx.Value(task_engine.GlobalContextKey).(*task_engine.GlobalContext)
+	if !ok {
+		globalContext = task_engine.NewGlobalContext()
+	}
+
+	a.Logger.Info("Running sub-task", "subTaskID", a.Task.GetID(), "parentPath", task_engine.TaskPathFromContext(ctx))
+	err := a.Task.RunWithContext(ctx, globalContext)
+	if output, outputErr := task_engine.EntityValue(globalContext, "task", a.Task.GetID(), ""); outputErr == nil {
+		a.Output = output
+	}
+	if err == nil {
+		return nil
+	}
+
+	// A deeper sub-task already reported the full path
+	var pathErr *task_engine.ActionPathError
+	if errors.As(err, &pathErr) {
+		return err
+	}
+
+	path := append(task_engine.TaskPathFromContext(ctx), a.Task.GetID())
+	if reporter, ok := a.Task.(failedActionReporter); ok && reporter.GetFailedActionID() != "" {
+		path = append(path, reporter.GetFailedActionID())
+	}
+	a.Logger.Error("Sub-task failed", "subTaskID", a.Task.GetID(), "error", err)
+	return &task_engine.ActionPathError{Path: path, Err: err}
+}
+
+// ResourceLocks returns the locks declared by the embedded task
+func (a *SubTaskAction) ResourceLocks() []string {
+	if d, ok := a.Task.(task_engine.ResourceLockDeclarer); ok {
+		return d.ResourceLocks()
+	}
+	return nil
+}
+
+// GetOutput returns the child task's output
+func (a *SubTaskAction) GetOutput() interface{} {
+	return a.Output
+}
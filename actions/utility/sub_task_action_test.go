@@ -0,0 +1,163 @@
+package utility_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/actions/utility"
+	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/suite"
+)
+
+// stepAction produces a fixed output, optionally failing or waiting for cancellation
+type stepAction struct {
+	task_engine.BaseAction
+	Output map[string]interface{}
+	Err    error
+	Block  bool
+}
+
+func (a *stepAction) Execute(ctx context.Context) error {
+	if a.Block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return a.Err
+}
+
+func (a *stepAction) GetOutput() interface{} { return a.Output }
+
+type SubTaskActionTestSuite struct {
+	suite.Suite
+}
+
+func TestSubTaskActionTestSuite(t *testing.T) {
+	suite.Run(t, new(SubTaskActionTestSuite))
+}
+
+func (suite *SubTaskActionTestSuite) step(id string, output map[string]interface{}, err error) task_engine.ActionWrapper {
+	logger := mocks.NewDiscardLogger()
+	return task_engine.NewAction(&stepAction{BaseAction: task_engine.NewBaseAction(logger), Output: output, Err: err}, id, logger, id)
+}
+
+func (suite *SubTaskActionTestSuite) TestChildSharesGlobalContextAndOutput() {
+	logger := mocks.NewDiscardLogger()
+	child := &task_engine.Task{
+		ID:      "docker-setup",
+		Name:    "Docker Setup",
+		Actions: []task_engine.ActionWrapper{suite.step("pull", map[string]interface{}{"image": "nginx:latest"}, nil)},
+	}
+	sub, err := utility.NewSubTaskAction(logger).WithParameters(child)
+	suite.Require().NoError(err)
+	suite.Equal("sub-task-docker-setup-action", sub.GetID())
+
+	parent := &task_engine.Task{
+		ID:     "deploy",
+		Name:   "Deploy",
+		Logger: logger,
+		Actions: []task_engine.ActionWrapper{
+			sub,
+			suite.step("after", nil, nil),
+		},
+	}
+	gc := task_engine.NewGlobalContext()
+	suite.Require().NoError(parent.RunWithContext(context.Background(), gc))
+
+	// The child's action outputs land in the shared context
+	image, err := task_engine.ActionOutputFieldAs[string](gc, "pull", "image")
+	suite.Require().NoError(err)
+	suite.Equal("nginx:latest", image)
+
+	// The sub-task action's output is the child's task output
+	success, err := task_engine.ActionOutputFieldAs[bool](gc, sub.GetID(), "success")
+	suite.Require().NoError(err)
+	suite.True(success)
+	taskID, err := task_engine.ActionOutputFieldAs[string](gc, sub.GetID(), "taskID")
+	suite.Require().NoError(err)
+	suite.Equal("docker-setup", taskID)
+}
+
+func (suite *SubTaskActionTestSuite) TestFailureReportsFullActionPath() {
+	logger := mocks.NewDiscardLogger()
+	boom := errors.New("boom")
+
+	grandchild := &task_engine.Task{
+		ID:      "pull-images",
+		Actions: []task_engine.ActionWrapper{suite.step("pull-nginx", nil, boom)},
+	}
+	inner, err := utility.NewSubTaskAction(logger).WithParameters(grandchild)
+	suite.Require().NoError(err)
+
+	child := &task_engine.Task{
+		ID:      "docker-setup",
+		Actions: []task_engine.ActionWrapper{inner},
+	}
+	outer, err := utility.NewSubTaskAction(logger).WithParameters(child)
+	suite.Require().NoError(err)
+
+	parent := &task_engine.Task{
+		ID:      "deploy",
+		Logger:  logger,
+		Actions: []task_engine.ActionWrapper{outer},
+	}
+
+	runErr := parent.Run(context.Background())
+	suite.Require().Error(runErr)
+	suite.ErrorIs(runErr, boom)
+
+	var pathErr *task_engine.ActionPathError
+	suite.Require().ErrorAs(runErr, &pathErr)
+	suite.Equal([]string{"deploy", "docker-setup", "pull-images", "pull-nginx"}, pathErr.Path)
+	suite.Contains(runErr.Error(), "deploy/docker-setup/pull-images/pull-nginx")
+	suite.Equal(outer.GetID(), parent.GetFailedActionID())
+}
+
+func (suite *SubTaskActionTestSuite) TestParentCancellationStopsChild() {
+	logger := mocks.NewDiscardLogger()
+	child := &task_engine.Task{
+		ID: "long-child",
+		Actions: []task_engine.ActionWrapper{
+			task_engine.NewAction(&stepAction{BaseAction: task_engine.NewBaseAction(logger), Block: true}, "Block", logger),
+		},
+	}
+	sub, err := utility.NewSubTaskAction(logger).WithParameters(child)
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	execErr := sub.Execute(ctx)
+	suite.ErrorIs(execErr, context.DeadlineExceeded)
+}
+
+func (suite *SubTaskActionTestSuite) TestSharedTaskIsNotRunConcurrently() {
+	logger := mocks.NewDiscardLogger()
+	child := &task_engine.Task{
+		ID: "shared",
+		Actions: []task_engine.ActionWrapper{
+			task_engine.NewAction(&stepAction{BaseAction: task_engine.NewBaseAction(logger), Block: true}, "Block", logger),
+		},
+	}
+	first, err := utility.NewSubTaskAction(logger).WithParameters(child)
+	suite.Require().NoError(err)
+	second, err := utility.NewSubTaskAction(logger).WithParameters(child)
+	suite.Require().NoError(err)
+	suite.Nil(child.Logger, "the caller's task is left unchanged")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- first.Execute(ctx) }()
+	suite.Eventually(func() bool { return child.GetProgress().Running }, time.Second, 5*time.Millisecond)
+
+	suite.ErrorIs(second.Execute(context.Background()), task_engine.ErrTaskRunning)
+	cancel()
+	suite.ErrorIs(<-done, context.Canceled)
+}
+
+func (suite *SubTaskActionTestSuite) TestNilTask() {
+	_, err := utility.NewSubTaskAction(mocks.NewDiscardLogger()).WithParameters(nil)
+	suite.Error(err)
+}
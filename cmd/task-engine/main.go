@@ -0,0 +1,248 @@
+// Command task-engine controls a running task engine daemon over its Unix
+// socket, so host scripts can define and trigger tasks without linking the
+// library.
+//
+//	task-engine [-socket path] add [-replace] <definition.yaml>
+//	task-engine [-socket path] run [-wait] [-input name=value]... <task>
+//	task-engine [-socket path] stop <task>
+//	task-engine [-socket path] status [-json] [task]
+//	task-engine [-socket path] tail [-n lines] [-f] <task>
+//
+// The socket defaults to $TASK_ENGINE_SOCKET, then /run/task-engine/engine.sock.
+// The exit status is 1 when a request fails (or a waited-for run does not
+// succeed) and 2 on usage errors.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ndizazzo/task-engine/daemon/client"
+	"github.com/ndizazzo/task-engine/daemon/protocol"
+)
+
+// waitInterval is how often run -wait polls the task's state
+const waitInterval = 200 * time.Millisecond
+
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("task-engine", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	socketPath := flags.String("socket", defaultSocketPath(), "daemon socket `path`")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: task-engine [-socket path] <add|run|stop|status|tail> [args]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	commands := map[string]func(context.Context, *client.Client, []string, io.Writer) error{
+		"add":    addCommand,
+		"run":    runCommand,
+		"stop":   stopCommand,
+		"status": statusCommand,
+		"tail":   tailCommand,
+	}
+	command, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "task-engine: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	c, err := client.Dial(ctx, *socketPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "task-engine: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+
+	if err := command(ctx, c, flags.Args()[1:], stdout); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "task-engine %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+	return 0
+}
+
+func defaultSocketPath() string {
+	if path := os.Getenv("TASK_ENGINE_SOCKET"); path != "" {
+		return path
+	}
+	return protocol.DefaultSocketPath
+}
+
+// parse parses a subcommand's flags and checks its positional argument count
+func parse(flags *flag.FlagSet, args []string, minArgs, maxArgs int, usage string) error {
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: task-engine %s %s\n", flags.Name(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < minArgs || flags.NArg() > maxArgs {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func addCommand(ctx context.Context, c *client.Client, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("add", flag.ContinueOnError)
+	replace := flags.Bool("replace", false, "redefine an existing idle task")
+	if err := parse(flags, args, 1, 1, "[-replace] <definition.yaml>"); err != nil {
+		return err
+	}
+
+	def, err := protocol.LoadTaskDefinition(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	status, err := c.AddTask(ctx, def, *replace)
+	if err != nil {
+		return err
+	}
+	printStatus(stdout, status)
+	return nil
+}
+
+func runCommand(ctx context.Context, c *client.Client, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	wait := flags.Bool("wait", false, "wait for the run to finish and fail unless it succeeds")
+	inputs := map[string]interface{}{}
+	flags.Func("input", "task input as `name=value` (repeatable)", func(value string) error {
+		name, v, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("expected name=value, got %q", value)
+		}
+		inputs[name] = v
+		return nil
+	})
+	if err := parse(flags, args, 1, 1, "[-wait] [-input name=value]... <task>"); err != nil {
+		return err
+	}
+
+	status, err := c.RunTask(ctx, flags.Arg(0), inputs)
+	if err != nil {
+		return err
+	}
+	if !*wait {
+		printStatus(stdout, status)
+		return nil
+	}
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+	for status.State != "idle" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		statuses, err := c.Status(ctx, status.ID)
+		if err != nil {
+			return err
+		}
+		status = statuses[0]
+	}
+	printStatus(stdout, status)
+	if status.Status != "success" {
+		return fmt.Errorf("task %s finished with status %q", status.ID, status.Status)
+	}
+	return nil
+}
+
+func stopCommand(ctx context.Context, c *client.Client, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("stop", flag.ContinueOnError)
+	if err := parse(flags, args, 1, 1, "<task>"); err != nil {
+		return err
+	}
+	status, err := c.StopTask(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	printStatus(stdout, status)
+	return nil
+}
+
+func statusCommand(ctx context.Context, c *client.Client, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	if err := parse(flags, args, 0, 1, "[-json] [task]"); err != nil {
+		return err
+	}
+	statuses, err := c.Status(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	}
+	for _, status := range statuses {
+		printStatus(stdout, status)
+	}
+	return nil
+}
+
+func tailCommand(ctx context.Context, c *client.Client, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("tail", flag.ContinueOnError)
+	lines := flags.Int("n", 20, "number of lines to show (0 for all)")
+	follow := flags.Bool("f", false, "keep printing new lines")
+	if err := parse(flags, args, 1, 1, "[-n lines] [-f] <task>"); err != nil {
+		return err
+	}
+
+	printEntry := func(entry protocol.LogEntry) {
+		fmt.Fprintf(stdout, "%s %s\n", entry.Time.Format(time.RFC3339), entry.Line)
+	}
+	if *follow {
+		return c.Follow(ctx, flags.Arg(0), *lines, printEntry)
+	}
+	entries, err := c.Tail(ctx, flags.Arg(0), *lines)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		printEntry(entry)
+	}
+	return nil
+}
+
+func printStatus(w io.Writer, status protocol.TaskStatus) {
+	line := fmt.Sprintf("%s\t%s", status.ID, status.State)
+	if status.Status != "" {
+		line += "\t" + status.Status
+	}
+	if status.Progress != "" {
+		line += "\t" + status.Progress
+	}
+	if status.Error != "" {
+		line += "\terror: " + status.Error
+	}
+	fmt.Fprintln(w, line)
+}
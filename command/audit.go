@@ -0,0 +1,215 @@
+package command
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+)
+
+// RedactedValue replaces the parts of arguments matched by a redaction pattern
+const RedactedValue = "[REDACTED]"
+
+// AuditEntry is one line of the command audit log
+type AuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	TaskID    string    `json:"taskId,omitempty"`
+	ActionID  string    `json:"actionId,omitempty"`
+	RunID     string    `json:"runId,omitempty"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args"`
+	Dir       string    `json:"dir,omitempty"`
+	// ExitCode is -1 when the process did not exit normally or could not start
+	ExitCode int           `json:"exitCode"`
+	Signaled bool          `json:"signaled,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// OutputSHA256 is the hex SHA-256 of the returned output (stdout for
+	// RunCommandWithOptions, whose stderr is hashed separately)
+	OutputSHA256 string `json:"outputSha256"`
+	StderrSHA256 string `json:"stderrSha256,omitempty"`
+}
+
+// AuditOption is a function type for configuring an AuditCommandRunner
+type AuditOption func(*AuditCommandRunner)
+
+// WithAuditRedaction replaces every match of the patterns in the logged
+// arguments with RedactedValue, e.g. regexp.MustCompile(`(?i)(?:password|token)=.*`).
+// The matched text is also removed from the logged error and directory.
+func WithAuditRedaction(patterns ...*regexp.Regexp) AuditOption {
+	return func(r *AuditCommandRunner) {
+		r.redact = append(r.redact, patterns...)
+	}
+}
+
+// WithAuditClock sets the clock used for entry timestamps
+func WithAuditClock(now func() time.Time) AuditOption {
+	return func(r *AuditCommandRunner) {
+		r.now = now
+	}
+}
+
+// AuditCommandRunner wraps another CommandRunner and appends an AuditEntry
+// in JSON Lines format for every command it runs. Task, run and action IDs
+// are taken from the context (see task_engine.ExecutionFromContext), so they
+// are only present for the context-aware methods.
+type AuditCommandRunner struct {
+	runner CommandRunner
+	redact []*regexp.Regexp
+	now    func() time.Time
+
+	mu  sync.Mutex
+	log io.Writer
+}
+
+// NewAuditCommandRunner wraps runner, writing entries to log
+func NewAuditCommandRunner(runner CommandRunner, log io.Writer, opts ...AuditOption) *AuditCommandRunner {
+	r := &AuditCommandRunner{runner: runner, log: log, now: time.Now}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// OpenAuditLog opens path for appending audit entries, creating it with
+// owner-only permissions if needed
+func OpenAuditLog(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// RunCommand executes and audits a command
+func (r *AuditCommandRunner) RunCommand(command string, args ...string) (string, error) {
+	start := r.now()
+	output, err := r.runner.RunCommand(command, args...)
+	return output, r.audit(context.Background(), start, command, args, "", CommandResult{Stdout: output}, false, err)
+}
+
+// RunCommandWithContext executes and audits a command
+func (r *AuditCommandRunner) RunCommandWithContext(ctx context.Context, command string, args ...string) (string, error) {
+	start := r.now()
+	output, err := r.runner.RunCommandWithContext(ctx, command, args...)
+	return output, r.audit(ctx, start, command, args, "", CommandResult{Stdout: output}, false, err)
+}
+
+// RunCommandInDir executes and audits a command in a working directory
+func (r *AuditCommandRunner) RunCommandInDir(workingDir string, command string, args ...string) (string, error) {
+	start := r.now()
+	output, err := r.runner.RunCommandInDir(workingDir, command, args...)
+	return output, r.audit(context.Background(), start, command, args, workingDir, CommandResult{Stdout: output}, false, err)
+}
+
+// RunCommandInDirWithContext executes and audits a command in a working directory
+func (r *AuditCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, command string, args ...string) (string, error) {
+	start := r.now()
+	output, err := r.runner.RunCommandInDirWithContext(ctx, workingDir, command, args...)
+	return output, r.audit(ctx, start, command, args, workingDir, CommandResult{Stdout: output}, false, err)
+}
+
+// RunCommandStream streams and audits a command
+func (r *AuditCommandRunner) RunCommandStream(ctx context.Context, opts StreamOptions, command string, args ...string) (string, error) {
+	start := r.now()
+	output, err := RunStreaming(ctx, r.runner, opts, command, args...)
+	return output, r.audit(ctx, start, command, args, opts.WorkingDir, CommandResult{Stdout: output}, false, err)
+}
+
+// RunCommandWithOptions executes and audits cmd; Env and Stdin are not logged
+func (r *AuditCommandRunner) RunCommandWithOptions(ctx context.Context, cmd Cmd) (CommandResult, error) {
+	start := r.now()
+	result, err := RunWithOptions(ctx, r.runner, cmd)
+	return result, r.audit(ctx, start, cmd.Name, cmd.Args, cmd.Dir, result, true, err)
+}
+
+// audit writes the entry for a finished command. A failure to write is
+// joined to the command's own error so it cannot go unnoticed.
+func (r *AuditCommandRunner) audit(ctx context.Context, start time.Time, command string, args []string, dir string, result CommandResult, separateStreams bool, runErr error) error {
+	if !separateStreams {
+		fillExitStatus(ctx, &result, runErr)
+	}
+
+	redactedArgs, secrets := r.redactArgs(args)
+	entry := AuditEntry{
+		Timestamp:    start.UTC(),
+		Command:      command,
+		Args:         redactedArgs,
+		Dir:          r.redactText(dir, secrets),
+		ExitCode:     result.ExitCode,
+		Signaled:     result.Signaled,
+		Duration:     r.now().Sub(start),
+		OutputSHA256: hashOutput(result.Stdout),
+	}
+	if separateStreams {
+		entry.StderrSHA256 = hashOutput(result.Stderr)
+	}
+	if runErr != nil {
+		// Errors such as PolicyDeniedError quote the whole command line
+		entry.Error = r.redactText(runErr.Error(), secrets)
+	}
+	if info, ok := task_engine.ExecutionFromContext(ctx); ok {
+		entry.TaskID = info.TaskID
+		entry.RunID = info.RunID
+		entry.ActionID = info.ActionID
+	}
+
+	line, err := json.Marshal(entry)
+	if err == nil {
+		r.mu.Lock()
+		_, err = r.log.Write(append(line, '\n'))
+		r.mu.Unlock()
+	}
+	if err != nil {
+		return errors.Join(runErr, fmt.Errorf("failed to write audit entry for %s: %w", command, err))
+	}
+	return runErr
+}
+
+// redactArgs applies the redaction patterns to each argument and also returns
+// the redacted parts, so they can be removed from text that repeats them
+func (r *AuditCommandRunner) redactArgs(args []string) (redacted, secrets []string) {
+	redacted = make([]string, len(args))
+	for i, arg := range args {
+		for _, pattern := range r.redact {
+			secrets = append(secrets, pattern.FindAllString(arg, -1)...)
+			arg = pattern.ReplaceAllLiteralString(arg, RedactedValue)
+		}
+		redacted[i] = arg
+	}
+	return redacted, secrets
+}
+
+// redactText applies the redaction patterns to text and replaces the
+// redacted parts of the arguments wherever they appear, plain or quoted
+func (r *AuditCommandRunner) redactText(text string, secrets []string) string {
+	for _, pattern := range r.redact {
+		text = pattern.ReplaceAllLiteralString(text, RedactedValue)
+	}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		text = strings.ReplaceAll(text, secret, RedactedValue)
+		if quoted := strconv.Quote(secret); quoted[1:len(quoted)-1] != secret {
+			text = strings.ReplaceAll(text, quoted[1:len(quoted)-1], RedactedValue)
+		}
+	}
+	return text
+}
+
+func hashOutput(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAuditEntries(t *testing.T, r io.Reader) []AuditEntry {
+	t.Helper()
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var entry AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+// auditedAction runs one command through its runner
+type auditedAction struct {
+	task_engine.BaseAction
+	runner CommandRunner
+}
+
+func (a *auditedAction) Execute(ctx context.Context) error {
+	_, err := a.runner.RunCommandWithContext(ctx, "echo", "--token=s3cret", "deployed")
+	return err
+}
+
+func (a *auditedAction) GetOutput() interface{} { return nil }
+
+func TestAuditRunnerRecordsTaskAndActionIDs(t *testing.T) {
+	var log bytes.Buffer
+	runner := NewAuditCommandRunner(NewDefaultCommandRunner(), &log,
+		WithAuditRedaction(regexp.MustCompile(`^--token=.*`)))
+
+	task := &task_engine.Task{
+		ID:     "deploy",
+		Name:   "Deploy",
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Actions: []task_engine.ActionWrapper{
+			&task_engine.Action[*auditedAction]{ID: "announce", Wrapped: &auditedAction{runner: runner}},
+		},
+	}
+	require.NoError(t, task.Run(context.Background()))
+
+	entries := readAuditEntries(t, &log)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "deploy", entry.TaskID)
+	assert.Equal(t, task.RunID, entry.RunID)
+	assert.Equal(t, "announce", entry.ActionID)
+	assert.Equal(t, "echo", entry.Command)
+	assert.Equal(t, []string{RedactedValue, "deployed"}, entry.Args)
+	assert.Equal(t, 0, entry.ExitCode)
+	assert.Empty(t, entry.Error)
+	assert.Equal(t, hashOutput("--token=s3cret deployed"), entry.OutputSHA256)
+	assert.NotContains(t, log.String(), "s3cret")
+}
+
+func TestAuditRunnerRecordsFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	f, err := OpenAuditLog(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	clock := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	runner := NewAuditCommandRunner(NewDefaultCommandRunner(), f,
+		WithAuditClock(func() time.Time { return clock }))
+
+	_, err = runner.RunCommandInDir(t.TempDir(), "sh", "-c", "exit 4")
+	assert.Error(t, err)
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{Name: "sh", Args: []string{"-c", "echo out; echo err 1>&2"}})
+	require.NoError(t, err)
+	assert.Equal(t, "out\n", result.Stdout)
+
+	// Entries are appended across runners sharing the file
+	f2, err := OpenAuditLog(path)
+	require.NoError(t, err)
+	defer f2.Close()
+	_, _ = NewAuditCommandRunner(NewDefaultCommandRunner(), f2).RunCommand("true")
+
+	data, err := os.Open(path)
+	require.NoError(t, err)
+	defer data.Close()
+	entries := readAuditEntries(t, data)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, 4, entries[0].ExitCode)
+	assert.Equal(t, "exit status 4", entries[0].Error)
+	assert.NotEmpty(t, entries[0].Dir)
+	assert.Equal(t, clock, entries[0].Timestamp)
+	assert.Empty(t, entries[0].TaskID)
+
+	assert.Equal(t, hashOutput("out\n"), entries[1].OutputSHA256)
+	assert.Equal(t, hashOutput("err\n"), entries[1].StderrSHA256)
+	assert.Equal(t, "true", entries[2].Command)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestAuditRunnerSurfacesWriteErrors(t *testing.T) {
+	runner := NewAuditCommandRunner(NewDefaultCommandRunner(), failingWriter{})
+	output, err := runner.RunCommand("echo", "hi")
+	assert.Equal(t, "hi", output)
+	assert.ErrorContains(t, err, "failed to write audit entry for echo: disk full")
+}
+
+func TestAuditRunnerRedactsSecretsEverywhere(t *testing.T) {
+	policy, err := ParseCommandPolicy([]byte(`
+rules:
+  - name: no-curl
+    effect: deny
+    binary: curl
+`))
+	require.NoError(t, err)
+	denying, err := NewPolicyCommandRunner(NewDefaultCommandRunner(), policy)
+	require.NoError(t, err)
+
+	var log bytes.Buffer
+	runner := NewAuditCommandRunner(denying, &log, WithAuditRedaction(regexp.MustCompile(`^token=.*`)))
+	_, err = runner.RunCommandInDirWithContext(context.Background(), "/srv/token=SECRET123", "curl", "token=SECRET123", `token="quoted\SECRET"`)
+	require.ErrorIs(t, err, ErrCommandDenied)
+
+	entries := readAuditEntries(t, bytes.NewReader(log.Bytes()))
+	require.Len(t, entries, 1)
+	assert.Equal(t, []string{RedactedValue, RedactedValue}, entries[0].Args)
+	assert.Contains(t, entries[0].Error, "denied by rule no-curl")
+	assert.NotContains(t, log.String(), "SECRET", "secrets in args stay out of the error and dir too")
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"os/exec"
 	"strings"
+	"syscall"
+
+	"github.com/ndizazzo/task-engine/internal/stopsignal"
 )
 
 // CommandRunner interface for executing system commands
@@ -30,6 +33,7 @@ func (r *DefaultCommandRunner) RunCommand(command string, args ...string) (strin
 // RunCommandWithContext executes a command with context and returns the output
 func (r *DefaultCommandRunner) RunCommandWithContext(ctx context.Context, command string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, command, args...)
+	stopGracefully(ctx, cmd)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return string(output), err
@@ -45,6 +49,7 @@ func (r *DefaultCommandRunner) RunCommandInDir(workingDir string, command string
 // RunCommandInDirWithContext executes a command in a specific working directory with context
 func (r *DefaultCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, command string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, command, args...)
+	stopGracefully(ctx, cmd)
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
@@ -54,3 +59,31 @@ func (r *DefaultCommandRunner) RunCommandInDirWithContext(ctx context.Context, w
 	}
 	return strings.TrimSpace(string(output)), nil
 }
+
+// stopGracefully lets a command exit on SIGTERM when the task running it is
+// being stopped by a TaskManager; it is killed when the stop grace period
+// runs out. Any other cancellation kills it at once. The command runs in its
+// own process group and signals go to the whole group, so processes it
+// started (e.g. by a shell) are stopped with it.
+func stopGracefully(ctx context.Context, cmd *exec.Cmd) {
+	signals, ok := stopsignal.FromContext(ctx)
+	if !ok {
+		return
+	}
+	startProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		select {
+		case <-signals.Stopping:
+		default:
+			return signalProcessGroup(cmd.Process, syscall.SIGKILL)
+		}
+		if err := signalProcessGroup(cmd.Process, syscall.SIGTERM); err != nil {
+			return signalProcessGroup(cmd.Process, syscall.SIGKILL)
+		}
+		go func() {
+			<-signals.Kill
+			_ = signalProcessGroup(cmd.Process, syscall.SIGKILL)
+		}()
+		return nil
+	}
+}
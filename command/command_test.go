@@ -2,10 +2,15 @@ package command
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/ndizazzo/task-engine/internal/stopsignal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDefaultCommandRunner(t *testing.T) {
@@ -132,3 +137,38 @@ func TestCommandErrorHandling(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "", result) // No output for immediate failure
 }
+
+func TestStopSignalsReachTheProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	stopped := filepath.Join(dir, "stopped")
+	// The subshell is a grandchild of the runner; it reports SIGTERM
+	script := fmt.Sprintf(`(trap 'echo term > %s; exit 0' TERM; touch %s; sleep 30 & wait) & wait`, stopped, ready)
+
+	stopping := make(chan struct{})
+	ctx, cancel := context.WithCancel(stopsignal.NewContext(context.Background(), stopsignal.Signals{Stopping: stopping, Kill: make(chan struct{})}))
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewDefaultCommandRunner().RunCommandWithContext(ctx, "sh", "-c", script)
+		done <- err
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(ready)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(stopping)
+	cancel()
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("command did not exit on SIGTERM")
+	}
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(stopped)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond, "SIGTERM reaches the processes the command started")
+}
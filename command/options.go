@@ -0,0 +1,198 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+)
+
+// Cmd describes a single command invocation for RunCommandWithOptions
+type Cmd struct {
+	Name string
+	Args []string
+	// Dir is the working directory (empty for the current one)
+	Dir string
+	// Env holds extra "KEY=value" entries added to the current environment
+	Env []string
+	// Stdin, if set, is connected to the command's standard input
+	Stdin io.Reader
+	// Timeout bounds this call in addition to the context (zero means none)
+	Timeout time.Duration
+	// OnLine optionally receives each line of output as it is produced
+	OnLine LineHandler
+	// MaxOutputBytes caps the retained stdout and stderr separately; see StreamOptions
+	MaxOutputBytes int
+}
+
+// CommandResult is the outcome of RunCommandWithOptions
+type CommandResult struct {
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+	// ExitCode is -1 when the process did not exit normally or the status is unknown
+	ExitCode int           `json:"exitCode"`
+	Duration time.Duration `json:"duration"`
+	// Signaled is true when the process was terminated by a signal, which
+	// includes being killed because the context or Timeout expired
+	Signaled bool `json:"signaled"`
+	TimedOut bool `json:"timedOut"`
+}
+
+// Success reports whether the command exited normally with status zero
+func (r CommandResult) Success() bool {
+	return r.ExitCode == 0 && !r.Signaled
+}
+
+// CommandError is returned by RunWithOptions for a command that ran but did
+// not succeed. It keeps the command's result so a task's ActionError can
+// report the exit code and stderr; its message is that of Err.
+type CommandError struct {
+	Name   string
+	Args   []string
+	Result CommandResult
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the command's exit code, -1 when it was killed by a signal
+func (e *CommandError) ExitCode() int {
+	return e.Result.ExitCode
+}
+
+// Stderr returns the command's retained standard error
+func (e *CommandError) Stderr() string {
+	return e.Result.Stderr
+}
+
+// ErrorKind reports commands stopped by their Timeout as timeouts and leaves
+// other failures to task_engine.ClassifyError
+func (e *CommandError) ErrorKind() task_engine.ErrorKind {
+	if e.Result.TimedOut {
+		return task_engine.ErrorKindTimeout
+	}
+	return ""
+}
+
+// OptionsCommandRunner is implemented by runners that support the full Cmd
+// options and report separate streams and exit codes
+type OptionsCommandRunner interface {
+	CommandRunner
+	RunCommandWithOptions(ctx context.Context, cmd Cmd) (CommandResult, error)
+}
+
+// RunWithOptions runs cmd through runner, using RunCommandWithOptions when the
+// runner implements it and RunBuffered otherwise. A command that ran but did
+// not succeed is reported as a *CommandError.
+func RunWithOptions(ctx context.Context, runner CommandRunner, cmd Cmd) (CommandResult, error) {
+	var result CommandResult
+	var err error
+	if r, ok := runner.(OptionsCommandRunner); ok {
+		result, err = r.RunCommandWithOptions(ctx, cmd)
+	} else {
+		result, err = RunBuffered(ctx, runner, cmd)
+	}
+	var cmdErr *CommandError
+	if err != nil && !errors.As(err, &cmdErr) && (result.ExitCode > 0 || result.Signaled || result.TimedOut) {
+		err = &CommandError{Name: cmd.Name, Args: cmd.Args, Result: result, Err: err}
+	}
+	return result, err
+}
+
+// RunBuffered emulates RunCommandWithOptions on top of the basic CommandRunner
+// methods (streaming through RunStreaming). Output is combined into Stdout and
+// the exit code is taken from the error when it is an *exec.ExitError.
+// Env and Stdin cannot be honored and are rejected.
+func RunBuffered(ctx context.Context, runner CommandRunner, cmd Cmd) (CommandResult, error) {
+	if len(cmd.Env) > 0 || cmd.Stdin != nil {
+		return CommandResult{ExitCode: -1}, fmt.Errorf("command runner %T does not support environment or stdin options", runner)
+	}
+	if cmd.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	output, err := RunStreaming(ctx, runner, StreamOptions{
+		WorkingDir:     cmd.Dir,
+		OnLine:         cmd.OnLine,
+		MaxOutputBytes: cmd.MaxOutputBytes,
+	}, cmd.Name, cmd.Args...)
+
+	result := CommandResult{Stdout: output, Duration: time.Since(start)}
+	fillExitStatus(ctx, &result, err)
+	return result, err
+}
+
+// RunCommandWithOptions executes cmd and returns its separate output streams,
+// exit code and duration. A non-zero exit is returned as an error alongside the result.
+func (r *DefaultCommandRunner) RunCommandWithOptions(ctx context.Context, cmd Cmd) (CommandResult, error) {
+	if cmd.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+		defer cancel()
+	}
+
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	stopGracefully(ctx, c)
+	c.Dir = cmd.Dir
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	c.Stdin = cmd.Stdin
+
+	limit := cmd.MaxOutputBytes
+	if limit == 0 {
+		limit = DefaultMaxOutputBytes
+	}
+	stdout := &tailBuffer{limit: limit}
+	stderr := &tailBuffer{limit: limit}
+	var handlerMu sync.Mutex
+	stdoutLines := newLineWriter(Stdout, cmd.OnLine, &handlerMu)
+	stderrLines := newLineWriter(Stderr, cmd.OnLine, &handlerMu)
+	c.Stdout = io.MultiWriter(stdout, stdoutLines)
+	c.Stderr = io.MultiWriter(stderr, stderrLines)
+
+	start := time.Now()
+	err := c.Run()
+	stdoutLines.flush()
+	stderrLines.flush()
+
+	result := CommandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	fillExitStatus(ctx, &result, err)
+	return result, err
+}
+
+// fillExitStatus derives ExitCode, Signaled and TimedOut from a run error
+func fillExitStatus(ctx context.Context, result *CommandResult, err error) {
+	result.TimedOut = err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		// ExitCode is -1 for processes terminated by a signal
+		result.Signaled = result.ExitCode == -1
+	default:
+		result.ExitCode = -1
+	}
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommandWithOptionsSeparatesStreams(t *testing.T) {
+	runner := NewDefaultCommandRunner()
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{
+		Name: "sh",
+		Args: []string{"-c", "echo out; echo err 1>&2; exit 3"},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "out\n", result.Stdout)
+	assert.Equal(t, "err\n", result.Stderr)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.False(t, result.Signaled)
+	assert.False(t, result.Success())
+	assert.Greater(t, result.Duration, time.Duration(0))
+}
+
+func TestRunCommandWithOptionsEnvStdinAndDir(t *testing.T) {
+	runner := NewDefaultCommandRunner()
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{
+		Name:  "sh",
+		Args:  []string{"-c", "pwd; echo $GREETING; cat"},
+		Dir:   "/",
+		Env:   []string{"GREETING=hello"},
+		Stdin: strings.NewReader("from stdin"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/\nhello\nfrom stdin", result.Stdout)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.True(t, result.Success())
+}
+
+func TestRunCommandWithOptionsTimeout(t *testing.T) {
+	runner := NewDefaultCommandRunner()
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{
+		Name:    "sleep",
+		Args:    []string{"5"},
+		Timeout: 20 * time.Millisecond,
+	})
+	assert.Error(t, err)
+	assert.True(t, result.TimedOut)
+	assert.True(t, result.Signaled)
+	assert.Equal(t, -1, result.ExitCode)
+}
+
+func TestRunBufferedDerivesExitCode(t *testing.T) {
+	// DefaultCommandRunner's basic methods return *exec.ExitError
+	result, err := RunBuffered(context.Background(), NewDefaultCommandRunner(), Cmd{
+		Name: "sh",
+		Args: []string{"-c", "echo partial; exit 7"},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 7, result.ExitCode)
+	assert.Equal(t, "partial\n", result.Stdout)
+
+	_, err = RunBuffered(context.Background(), NewDefaultCommandRunner(), Cmd{Name: "true", Env: []string{"A=b"}})
+	assert.Error(t, err, "Env cannot be honored by the basic methods")
+}
+
+func TestRunWithOptionsFallsBackForBasicRunners(t *testing.T) {
+	runner := &bufferedRunner{}
+
+	var lines []string
+	result, err := RunWithOptions(context.Background(), runner, Cmd{
+		Name:   "anything",
+		OnLine: func(stream OutputStream, line string) { lines = append(lines, line) },
+	})
+	assert.EqualError(t, err, "buffered")
+	assert.Equal(t, "first\nsecond", result.Stdout)
+	assert.Equal(t, -1, result.ExitCode)
+	assert.Equal(t, []string{"first", "second"}, lines)
+}
+
+func TestRunWithOptionsReportsCommandErrors(t *testing.T) {
+	_, err := RunWithOptions(context.Background(), NewDefaultCommandRunner(), Cmd{
+		Name: "sh",
+		Args: []string{"-c", "echo denied 1>&2; exit 4"},
+	})
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, "sh", cmdErr.Name)
+	assert.Equal(t, 4, cmdErr.ExitCode())
+	assert.Equal(t, "denied\n", cmdErr.Stderr())
+	assert.Equal(t, "exit status 4", err.Error())
+	assert.Equal(t, task_engine.ErrorKindPermanent, task_engine.ClassifyError(err))
+
+	_, err = RunWithOptions(context.Background(), NewDefaultCommandRunner(), Cmd{
+		Name:    "sleep",
+		Args:    []string{"5"},
+		Timeout: 10 * time.Millisecond,
+	})
+	assert.Equal(t, task_engine.ErrorKindTimeout, task_engine.ClassifyError(err))
+}
@@ -0,0 +1,307 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyEffect is the outcome of a policy rule
+type PolicyEffect string
+
+const (
+	PolicyAllow PolicyEffect = "allow"
+	PolicyDeny  PolicyEffect = "deny"
+)
+
+// ErrCommandDenied is matched by every *PolicyDeniedError
+var ErrCommandDenied = errors.New("command denied by policy")
+
+// PolicyDeniedError is returned instead of running a command the policy denies
+type PolicyDeniedError struct {
+	// Rule is the name of the matching deny rule, or empty for the default effect
+	Rule    string
+	TaskID  string
+	Command string
+	Args    []string
+	Dir     string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	reason := "default policy"
+	if e.Rule != "" {
+		reason = "rule " + e.Rule
+	}
+	msg := fmt.Sprintf("command %q denied by %s", strings.TrimSpace(e.Command+" "+strings.Join(e.Args, " ")), reason)
+	if e.TaskID != "" {
+		msg += " for task " + e.TaskID
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrCommandDenied) true for policy denials
+func (e *PolicyDeniedError) Is(target error) bool {
+	return target == ErrCommandDenied
+}
+
+// ErrorKind classifies policy denials for task_engine.ClassifyError
+func (e *PolicyDeniedError) ErrorKind() task_engine.ErrorKind {
+	return task_engine.ErrorKindPolicy
+}
+
+// PolicyRule matches commands and decides whether they may run. Empty fields
+// match anything; all non-empty fields must match.
+type PolicyRule struct {
+	Name   string       `yaml:"name" json:"name"`
+	Effect PolicyEffect `yaml:"effect" json:"effect"`
+	// Binary is a glob; without a "/" it is matched against the base name of
+	// the command, so "docker" also covers "/usr/bin/docker"
+	Binary string `yaml:"binary,omitempty" json:"binary,omitempty"`
+	// Args are regular expressions that must each match at least one argument
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+	// Dir is a glob for the working directory; a trailing "/**" also matches
+	// everything beneath the directory
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+	// Tasks are globs for the ID of the task running the command
+	Tasks []string `yaml:"tasks,omitempty" json:"tasks,omitempty"`
+
+	args []*regexp.Regexp
+}
+
+// CommandPolicy is an ordered list of rules; the first matching rule decides,
+// and Default (allow when empty) applies when none matches
+type CommandPolicy struct {
+	Default PolicyEffect `yaml:"default,omitempty" json:"default,omitempty"`
+	Rules   []PolicyRule `yaml:"rules" json:"rules"`
+}
+
+// PolicyRequest describes a command about to run
+type PolicyRequest struct {
+	TaskID  string
+	Command string
+	Args    []string
+	Dir     string
+}
+
+// LoadCommandPolicy reads a policy from a YAML (or JSON) file
+func LoadCommandPolicy(path string) (*CommandPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read command policy %s: %w", path, err)
+	}
+	policy, err := ParseCommandPolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// ParseCommandPolicy parses and validates a YAML (or JSON) policy document
+func ParseCommandPolicy(data []byte) (*CommandPolicy, error) {
+	var policy CommandPolicy
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, err
+	}
+	if err := policy.Compile(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Compile validates the policy and prepares its patterns. Policies built in
+// code must be compiled before use; the Load and Parse functions and
+// NewPolicyCommandRunner do it.
+func (p *CommandPolicy) Compile() error {
+	if p.Default != "" && p.Default != PolicyAllow && p.Default != PolicyDeny {
+		return fmt.Errorf("invalid default effect %q", p.Default)
+	}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if rule.Effect != PolicyAllow && rule.Effect != PolicyDeny {
+			return fmt.Errorf("rule %s: invalid effect %q", rule.Name, rule.Effect)
+		}
+		for _, glob := range append([]string{rule.Binary, strings.TrimSuffix(rule.Dir, "/**")}, rule.Tasks...) {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("rule %s: invalid pattern %q: %w", rule.Name, glob, err)
+			}
+		}
+		rule.args = rule.args[:0]
+		for _, expr := range rule.Args {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("rule %s: invalid argument pattern %q: %w", rule.Name, expr, err)
+			}
+			rule.args = append(rule.args, re)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the effect for req and the name of the rule that decided it
+// (empty when the default applied)
+func (p *CommandPolicy) Evaluate(req PolicyRequest) (PolicyEffect, string) {
+	for _, rule := range p.Rules {
+		if rule.matches(req) {
+			return rule.Effect, rule.Name
+		}
+	}
+	if p.Default == "" {
+		return PolicyAllow, ""
+	}
+	return p.Default, ""
+}
+
+// Check returns a *PolicyDeniedError when req is denied
+func (p *CommandPolicy) Check(req PolicyRequest) error {
+	effect, rule := p.Evaluate(req)
+	if effect == PolicyAllow {
+		return nil
+	}
+	return &PolicyDeniedError{Rule: rule, TaskID: req.TaskID, Command: req.Command, Args: req.Args, Dir: req.Dir}
+}
+
+func (r PolicyRule) matches(req PolicyRequest) bool {
+	if r.Binary != "" {
+		name := req.Command
+		if !strings.Contains(r.Binary, "/") {
+			name = path.Base(name)
+		}
+		if ok, _ := path.Match(r.Binary, name); !ok {
+			return false
+		}
+	}
+	if r.Dir != "" && !matchDir(r.Dir, req.Dir) {
+		return false
+	}
+	if len(r.Tasks) > 0 && !matchAny(r.Tasks, req.TaskID) {
+		return false
+	}
+	for _, re := range r.args {
+		if !anyArgMatches(re, req.Args) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchDir(pattern, dir string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/**"); ok {
+		for d := path.Clean(dir); ; d = path.Dir(d) {
+			if matched, _ := path.Match(base, d); matched {
+				return true
+			}
+			if d == "/" || d == "." {
+				return false
+			}
+		}
+	}
+	matched, _ := path.Match(pattern, dir)
+	return matched
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func anyArgMatches(re *regexp.Regexp, args []string) bool {
+	for _, arg := range args {
+		if re.MatchString(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyCommandRunner wraps another CommandRunner and checks every command
+// against a CommandPolicy before running it. The task ID is taken from the
+// context (see task_engine.ExecutionFromContext), so task rules only match
+// the context-aware methods.
+type PolicyCommandRunner struct {
+	runner CommandRunner
+	policy *CommandPolicy
+}
+
+// NewPolicyCommandRunner wraps runner with policy, compiling it first so
+// policies built in code work without calling Compile
+func NewPolicyCommandRunner(runner CommandRunner, policy *CommandPolicy) (*PolicyCommandRunner, error) {
+	if runner == nil {
+		return nil, fmt.Errorf("command runner cannot be nil")
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("command policy cannot be nil")
+	}
+	if err := policy.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid command policy: %w", err)
+	}
+	return &PolicyCommandRunner{runner: runner, policy: policy}, nil
+}
+
+// RunCommand runs a command the policy allows
+func (r *PolicyCommandRunner) RunCommand(command string, args ...string) (string, error) {
+	if err := r.check(context.Background(), command, args, ""); err != nil {
+		return "", err
+	}
+	return r.runner.RunCommand(command, args...)
+}
+
+// RunCommandWithContext runs a command the policy allows
+func (r *PolicyCommandRunner) RunCommandWithContext(ctx context.Context, command string, args ...string) (string, error) {
+	if err := r.check(ctx, command, args, ""); err != nil {
+		return "", err
+	}
+	return r.runner.RunCommandWithContext(ctx, command, args...)
+}
+
+// RunCommandInDir runs a command the policy allows in a working directory
+func (r *PolicyCommandRunner) RunCommandInDir(workingDir string, command string, args ...string) (string, error) {
+	if err := r.check(context.Background(), command, args, workingDir); err != nil {
+		return "", err
+	}
+	return r.runner.RunCommandInDir(workingDir, command, args...)
+}
+
+// RunCommandInDirWithContext runs a command the policy allows in a working directory
+func (r *PolicyCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, command string, args ...string) (string, error) {
+	if err := r.check(ctx, command, args, workingDir); err != nil {
+		return "", err
+	}
+	return r.runner.RunCommandInDirWithContext(ctx, workingDir, command, args...)
+}
+
+// RunCommandStream streams a command the policy allows
+func (r *PolicyCommandRunner) RunCommandStream(ctx context.Context, opts StreamOptions, command string, args ...string) (string, error) {
+	if err := r.check(ctx, command, args, opts.WorkingDir); err != nil {
+		return "", err
+	}
+	return RunStreaming(ctx, r.runner, opts, command, args...)
+}
+
+// RunCommandWithOptions runs cmd when the policy allows it
+func (r *PolicyCommandRunner) RunCommandWithOptions(ctx context.Context, cmd Cmd) (CommandResult, error) {
+	if err := r.check(ctx, cmd.Name, cmd.Args, cmd.Dir); err != nil {
+		return CommandResult{ExitCode: -1}, err
+	}
+	return RunWithOptions(ctx, r.runner, cmd)
+}
+
+func (r *PolicyCommandRunner) check(ctx context.Context, command string, args []string, dir string) error {
+	info, _ := task_engine.ExecutionFromContext(ctx)
+	return r.policy.Check(PolicyRequest{TaskID: info.TaskID, Command: command, Args: args, Dir: dir})
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+default: deny
+rules:
+  - name: no-privileged-containers
+    effect: deny
+    binary: docker
+    args: ["^--privileged$"]
+  - name: compose-in-srv
+    effect: allow
+    binary: docker
+    dir: /srv/**
+  - name: docker-for-deploy
+    effect: allow
+    binary: docker
+    tasks: ["deploy-*"]
+  - effect: allow
+    binary: /usr/bin/systemctl
+`
+
+func TestCommandPolicyEvaluation(t *testing.T) {
+	policy, err := ParseCommandPolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		req    PolicyRequest
+		effect PolicyEffect
+		rule   string
+	}{
+		{"first match wins", PolicyRequest{TaskID: "deploy-web", Command: "docker", Args: []string{"run", "--privileged", "alpine"}}, PolicyDeny, "no-privileged-containers"},
+		{"binary base name", PolicyRequest{Command: "/usr/local/bin/docker", Args: []string{"compose", "up"}, Dir: "/srv/app/current"}, PolicyAllow, "compose-in-srv"},
+		{"dir itself", PolicyRequest{Command: "docker", Dir: "/srv"}, PolicyAllow, "compose-in-srv"},
+		{"task glob", PolicyRequest{TaskID: "deploy-web", Command: "docker", Args: []string{"ps"}}, PolicyAllow, "docker-for-deploy"},
+		{"other task", PolicyRequest{TaskID: "backup", Command: "docker", Args: []string{"ps"}, Dir: "/tmp"}, PolicyDeny, ""},
+		{"full path binary", PolicyRequest{Command: "/usr/bin/systemctl"}, PolicyAllow, "rule-4"},
+		{"full path must match exactly", PolicyRequest{Command: "systemctl"}, PolicyDeny, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effect, rule := policy.Evaluate(tt.req)
+			assert.Equal(t, tt.effect, effect)
+			assert.Equal(t, tt.rule, rule)
+		})
+	}
+
+	allowAll := &CommandPolicy{}
+	require.NoError(t, allowAll.Compile())
+	assert.NoError(t, allowAll.Check(PolicyRequest{Command: "rm", Args: []string{"-rf", "/"}}))
+}
+
+func TestCommandPolicyValidation(t *testing.T) {
+	_, err := ParseCommandPolicy([]byte("rules:\n  - effect: maybe\n"))
+	assert.ErrorContains(t, err, `rule rule-1: invalid effect "maybe"`)
+	_, err = ParseCommandPolicy([]byte("rules:\n  - effect: deny\n    args: ['(']\n"))
+	assert.ErrorContains(t, err, "invalid argument pattern")
+	_, err = ParseCommandPolicy([]byte("rules:\n  - effect: deny\n    binary: 'dock['\n"))
+	assert.ErrorContains(t, err, "invalid pattern")
+	_, err = ParseCommandPolicy([]byte("rules:\n  - effect: deny\n    command: docker\n"))
+	assert.ErrorContains(t, err, "field command not found")
+	_, err = ParseCommandPolicy([]byte("default: sometimes\n"))
+	assert.ErrorContains(t, err, "invalid default effect")
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"default": "deny", "rules": [{"effect": "allow", "binary": "echo"}]}`), 0o600))
+	policy, err := LoadCommandPolicy(path)
+	require.NoError(t, err)
+	assert.NoError(t, policy.Check(PolicyRequest{Command: "echo"}))
+}
+
+func TestPolicyRunnerDeniesBeforeRunning(t *testing.T) {
+	policy, err := ParseCommandPolicy([]byte(`
+rules:
+  - name: no-echo-in-deploy
+    effect: deny
+    binary: echo
+    tasks: [deploy]
+`))
+	require.NoError(t, err)
+	runner, err := NewPolicyCommandRunner(NewDefaultCommandRunner(), policy)
+	require.NoError(t, err)
+
+	// Outside the task the command is allowed
+	output, err := runner.RunCommand("echo", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, "hi", output)
+
+	task := &task_engine.Task{
+		ID:     "deploy",
+		Name:   "Deploy",
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Actions: []task_engine.ActionWrapper{
+			&task_engine.Action[*auditedAction]{ID: "announce", Wrapped: &auditedAction{runner: runner}},
+		},
+	}
+	err = task.Run(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrCommandDenied)
+
+	var denied *PolicyDeniedError
+	require.ErrorAs(t, task.GetError(), &denied)
+	assert.Equal(t, "no-echo-in-deploy", denied.Rule)
+	assert.Equal(t, "deploy", denied.TaskID)
+	assert.Equal(t, "echo", denied.Command)
+	assert.Equal(t, `command "echo --token=s3cret deployed" denied by rule no-echo-in-deploy for task deploy`, denied.Error())
+
+	result, err := runner.RunCommandWithOptions(executionContext("deploy"), Cmd{Name: "echo"})
+	assert.ErrorIs(t, err, ErrCommandDenied)
+	assert.Equal(t, -1, result.ExitCode)
+}
+
+func TestPolicyRunnerCompilesPoliciesBuiltInCode(t *testing.T) {
+	policy := &CommandPolicy{Rules: []PolicyRule{
+		{Effect: PolicyDeny, Binary: "echo", Args: []string{"^--privileged$"}},
+	}}
+	runner, err := NewPolicyCommandRunner(NewDefaultCommandRunner(), policy)
+	require.NoError(t, err)
+
+	output, err := runner.RunCommand("echo", "ps")
+	require.NoError(t, err, "the argument pattern must be compiled to narrow the rule")
+	assert.Equal(t, "ps", output)
+
+	_, err = runner.RunCommand("echo", "--privileged")
+	var denied *PolicyDeniedError
+	require.ErrorAs(t, err, &denied)
+	assert.Equal(t, "rule-1", denied.Rule)
+	assert.Contains(t, denied.Error(), "denied by rule rule-1")
+
+	_, err = NewPolicyCommandRunner(NewDefaultCommandRunner(), &CommandPolicy{Rules: []PolicyRule{{Effect: "maybe"}}})
+	assert.ErrorContains(t, err, "invalid command policy")
+}
+
+func executionContext(taskID string) context.Context {
+	return context.WithValue(context.Background(), task_engine.ExecutionKey, task_engine.ExecutionInfo{TaskID: taskID})
+}
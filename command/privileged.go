@@ -0,0 +1,236 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EscalationMethod is the program used to run privileged commands
+type EscalationMethod string
+
+const (
+	// SudoEscalation runs commands with "sudo -n"
+	SudoEscalation EscalationMethod = "sudo"
+	// DoasEscalation runs commands with "doas -n"
+	DoasEscalation EscalationMethod = "doas"
+)
+
+// DefaultPrivilegedCommands are the commands the built-in actions need root for
+// (ManageServiceAction, UpdatePackagesAction, ChangeOwnershipAction and ShutdownAction)
+var DefaultPrivilegedCommands = []string{"systemctl", "apt", "chown", "shutdown"}
+
+// ErrPasswordRequired is returned when the escalation program would have to
+// prompt for a password. Configure it to allow the command without one
+// (NOPASSWD in sudoers, nopass in doas.conf).
+var ErrPasswordRequired = errors.New("privilege escalation requires a password")
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// unsafeEnvNames can change what a privileged process loads or executes and
+// are never passed through
+var unsafeEnvNames = map[string]bool{
+	"PATH": true, "IFS": true, "ENV": true, "BASH_ENV": true,
+	"SHELLOPTS": true, "BASHOPTS": true, "PS4": true,
+}
+
+// PrivilegedOption is a function type for configuring a PrivilegedCommandRunner
+type PrivilegedOption func(*PrivilegedCommandRunner) error
+
+// WithEscalationMethod selects sudo (the default) or doas
+func WithEscalationMethod(method EscalationMethod) PrivilegedOption {
+	return func(r *PrivilegedCommandRunner) error {
+		if method != SudoEscalation && method != DoasEscalation {
+			return fmt.Errorf("unsupported escalation method: %s", method)
+		}
+		r.method = method
+		return nil
+	}
+}
+
+// WithPrivilegedCommands replaces the allowlist of commands that are escalated.
+// Names are matched exactly, so "/usr/bin/systemctl" must be listed separately.
+func WithPrivilegedCommands(commands ...string) PrivilegedOption {
+	return func(r *PrivilegedCommandRunner) error {
+		r.allowed = make(map[string]bool, len(commands))
+		for _, c := range commands {
+			if c == "" {
+				return fmt.Errorf("privileged command cannot be empty")
+			}
+			r.allowed[c] = true
+		}
+		return nil
+	}
+}
+
+// WithPreservedEnv allows Cmd.Env entries with the given names to reach
+// escalated commands. Loader and shell variables such as LD_PRELOAD and PATH are refused.
+func WithPreservedEnv(names ...string) PrivilegedOption {
+	return func(r *PrivilegedCommandRunner) error {
+		for _, name := range names {
+			if !envNamePattern.MatchString(name) {
+				return fmt.Errorf("invalid environment variable name: %q", name)
+			}
+			if isUnsafeEnvName(name) {
+				return fmt.Errorf("environment variable %s cannot be passed to privileged commands", name)
+			}
+			r.preservedEnv[name] = true
+		}
+		return nil
+	}
+}
+
+// PrivilegedCommandRunner wraps another CommandRunner and runs allowlisted
+// commands through sudo or doas in non-interactive mode; every other command
+// is passed through unchanged. It never prompts: when a password would be
+// needed the call fails with ErrPasswordRequired.
+type PrivilegedCommandRunner struct {
+	runner       CommandRunner
+	method       EscalationMethod
+	allowed      map[string]bool
+	preservedEnv map[string]bool
+}
+
+// NewPrivilegedCommandRunner wraps runner, escalating DefaultPrivilegedCommands
+// with sudo unless configured otherwise
+func NewPrivilegedCommandRunner(runner CommandRunner, opts ...PrivilegedOption) (*PrivilegedCommandRunner, error) {
+	if runner == nil {
+		return nil, fmt.Errorf("command runner cannot be nil")
+	}
+	r := &PrivilegedCommandRunner{
+		runner:       runner,
+		method:       SudoEscalation,
+		preservedEnv: make(map[string]bool),
+	}
+	if err := WithPrivilegedCommands(DefaultPrivilegedCommands...)(r); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// IsPrivileged reports whether command is escalated by this runner
+func (r *PrivilegedCommandRunner) IsPrivileged(command string) bool {
+	return r.allowed[command]
+}
+
+// RunCommand executes a command, escalating it when allowlisted
+func (r *PrivilegedCommandRunner) RunCommand(command string, args ...string) (string, error) {
+	name, fullArgs := r.wrap(command, args, nil)
+	output, err := r.runner.RunCommand(name, fullArgs...)
+	return output, r.checkEscalation(command, output, err)
+}
+
+// RunCommandWithContext executes a command with context, escalating it when allowlisted
+func (r *PrivilegedCommandRunner) RunCommandWithContext(ctx context.Context, command string, args ...string) (string, error) {
+	name, fullArgs := r.wrap(command, args, nil)
+	output, err := r.runner.RunCommandWithContext(ctx, name, fullArgs...)
+	return output, r.checkEscalation(command, output, err)
+}
+
+// RunCommandInDir executes a command in a working directory, escalating it when allowlisted
+func (r *PrivilegedCommandRunner) RunCommandInDir(workingDir string, command string, args ...string) (string, error) {
+	name, fullArgs := r.wrap(command, args, nil)
+	output, err := r.runner.RunCommandInDir(workingDir, name, fullArgs...)
+	return output, r.checkEscalation(command, output, err)
+}
+
+// RunCommandInDirWithContext executes a command in a working directory with
+// context, escalating it when allowlisted
+func (r *PrivilegedCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, command string, args ...string) (string, error) {
+	name, fullArgs := r.wrap(command, args, nil)
+	output, err := r.runner.RunCommandInDirWithContext(ctx, workingDir, name, fullArgs...)
+	return output, r.checkEscalation(command, output, err)
+}
+
+// RunCommandStream streams a command through the wrapped runner, escalating it when allowlisted
+func (r *PrivilegedCommandRunner) RunCommandStream(ctx context.Context, opts StreamOptions, command string, args ...string) (string, error) {
+	name, fullArgs := r.wrap(command, args, nil)
+	output, err := RunStreaming(ctx, r.runner, opts, name, fullArgs...)
+	return output, r.checkEscalation(command, output, err)
+}
+
+// RunCommandWithOptions runs cmd through the wrapped runner, escalating it when
+// allowlisted. Env entries of escalated commands must be named in WithPreservedEnv.
+func (r *PrivilegedCommandRunner) RunCommandWithOptions(ctx context.Context, cmd Cmd) (CommandResult, error) {
+	command := cmd.Name
+	if r.IsPrivileged(command) {
+		names, err := r.envNames(cmd.Env)
+		if err != nil {
+			return CommandResult{ExitCode: -1}, err
+		}
+		cmd.Name, cmd.Args = r.wrap(command, cmd.Args, names)
+	}
+	result, err := RunWithOptions(ctx, r.runner, cmd)
+	return result, r.checkEscalation(command, result.Stderr+result.Stdout, err)
+}
+
+// wrap prefixes an allowlisted command with the escalation program
+func (r *PrivilegedCommandRunner) wrap(command string, args []string, envNames []string) (string, []string) {
+	if !r.IsPrivileged(command) {
+		return command, args
+	}
+	prefix := []string{"-n"}
+	if r.method == SudoEscalation {
+		// sudo resets the environment, so variables must be kept explicitly;
+		// doas decides this in doas.conf (keepenv / setenv)
+		if len(envNames) > 0 {
+			prefix = append(prefix, "--preserve-env="+strings.Join(envNames, ","))
+		}
+		prefix = append(prefix, "--")
+	}
+	fullArgs := make([]string, 0, len(prefix)+1+len(args))
+	fullArgs = append(fullArgs, prefix...)
+	fullArgs = append(fullArgs, command)
+	fullArgs = append(fullArgs, args...)
+	return string(r.method), fullArgs
+}
+
+// envNames validates "KEY=value" entries against the preserved names
+func (r *PrivilegedCommandRunner) envNames(env []string) ([]string, error) {
+	names := make([]string, 0, len(env))
+	for _, entry := range env {
+		name, _, ok := strings.Cut(entry, "=")
+		if !ok || !r.preservedEnv[name] {
+			return nil, fmt.Errorf("environment variable %q is not allowed for privileged commands", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// checkEscalation turns a refused non-interactive escalation into ErrPasswordRequired
+func (r *PrivilegedCommandRunner) checkEscalation(command string, output string, err error) error {
+	if err == nil || !r.IsPrivileged(command) || !r.passwordPrompted(output) {
+		return err
+	}
+	return fmt.Errorf("%w: %s refused to run %s non-interactively; allow it without a password: %w",
+		ErrPasswordRequired, r.method, command, err)
+}
+
+// passwordPrompted reports whether the escalation program said it needs a password
+func (r *PrivilegedCommandRunner) passwordPrompted(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if !strings.HasPrefix(line, string(r.method)+":") {
+			continue
+		}
+		for _, marker := range []string{"password is required", "terminal is required", "no tty present", "authorization required", "authentication failed"} {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isUnsafeEnvName(name string) bool {
+	upper := strings.ToUpper(name)
+	return unsafeEnvNames[upper] || strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_")
+}
@@ -0,0 +1,98 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installFakeEscalation puts a script named program on PATH
+func installFakeEscalation(t *testing.T, program, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, program), []byte("#!/bin/sh\n"+script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPrivilegedRunnerEscalatesOnlyAllowlistedCommands(t *testing.T) {
+	installFakeEscalation(t, "sudo", `echo "sudo $*"`)
+	runner, err := NewPrivilegedCommandRunner(NewDefaultCommandRunner())
+	require.NoError(t, err)
+
+	output, err := runner.RunCommand("systemctl", "restart", "nginx")
+	require.NoError(t, err)
+	assert.Equal(t, "sudo -n -- systemctl restart nginx", output)
+
+	output, err = runner.RunCommandInDirWithContext(context.Background(), t.TempDir(), "chown", "-R", "app:app", "/srv")
+	require.NoError(t, err)
+	assert.Equal(t, "sudo -n -- chown -R app:app /srv", output)
+
+	output, err = runner.RunCommand("echo", "unprivileged")
+	require.NoError(t, err)
+	assert.Equal(t, "unprivileged", output)
+	assert.False(t, runner.IsPrivileged("/usr/bin/systemctl"))
+}
+
+func TestPrivilegedRunnerPreservesAllowedEnv(t *testing.T) {
+	installFakeEscalation(t, "sudo", `echo "sudo $* GREETING=$GREETING"`)
+	runner, err := NewPrivilegedCommandRunner(NewDefaultCommandRunner(), WithPreservedEnv("GREETING"))
+	require.NoError(t, err)
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{
+		Name: "apt",
+		Args: []string{"update"},
+		Env:  []string{"GREETING=hello"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "sudo -n --preserve-env=GREETING -- apt update GREETING=hello\n", result.Stdout)
+
+	_, err = runner.RunCommandWithOptions(context.Background(), Cmd{
+		Name: "apt",
+		Args: []string{"update"},
+		Env:  []string{"OTHER=1"},
+	})
+	assert.ErrorContains(t, err, `"OTHER" is not allowed`)
+
+	_, err = NewPrivilegedCommandRunner(NewDefaultCommandRunner(), WithPreservedEnv("LD_PRELOAD"))
+	assert.Error(t, err)
+	_, err = NewPrivilegedCommandRunner(NewDefaultCommandRunner(), WithPreservedEnv("PATH"))
+	assert.Error(t, err)
+	_, err = NewPrivilegedCommandRunner(NewDefaultCommandRunner(), WithPreservedEnv("NOT VALID"))
+	assert.Error(t, err)
+}
+
+func TestPrivilegedRunnerReportsPasswordRequired(t *testing.T) {
+	installFakeEscalation(t, "doas", `echo "doas: Authorization required" 1>&2; exit 1`)
+	runner, err := NewPrivilegedCommandRunner(NewDefaultCommandRunner(),
+		WithEscalationMethod(DoasEscalation),
+		WithPrivilegedCommands("shutdown"),
+	)
+	require.NoError(t, err)
+
+	_, err = runner.RunCommand("shutdown", "-r", "now")
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+	assert.ErrorContains(t, err, "doas refused to run shutdown")
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{Name: "shutdown", Args: []string{"-h", "now"}})
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+	assert.Equal(t, 1, result.ExitCode)
+
+	// Ordinary failures of the escalated command are returned unchanged
+	installFakeEscalation(t, "doas", `echo "shutdown: invalid time" 1>&2; exit 1`)
+	_, err = runner.RunCommand("shutdown", "later")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrPasswordRequired)
+}
+
+func TestNewPrivilegedCommandRunnerValidation(t *testing.T) {
+	_, err := NewPrivilegedCommandRunner(nil)
+	assert.Error(t, err)
+	_, err = NewPrivilegedCommandRunner(NewDefaultCommandRunner(), WithEscalationMethod("su"))
+	assert.ErrorContains(t, err, "unsupported escalation method")
+	_, err = NewPrivilegedCommandRunner(NewDefaultCommandRunner(), WithPrivilegedCommands(""))
+	assert.Error(t, err)
+}
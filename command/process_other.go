@@ -0,0 +1,20 @@
+//go:build !unix
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// startProcessGroup is a no-op where process groups are not available
+func startProcessGroup(*exec.Cmd) {}
+
+// signalProcessGroup signals p itself where process groups are not available
+func signalProcessGroup(p *os.Process, sig syscall.Signal) error {
+	if sig == syscall.SIGKILL {
+		return p.Kill()
+	}
+	return p.Signal(sig)
+}
@@ -0,0 +1,422 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// DefaultSSHDialTimeout bounds establishing a new SSH connection
+const DefaultSSHDialTimeout = 15 * time.Second
+
+// SSHOption is a function type for configuring an SSHCommandRunner
+type SSHOption func(*sshSettings) error
+
+type sshSettings struct {
+	authMethods     []ssh.AuthMethod
+	agentSocket     string
+	knownHostsFiles []string
+	hostKeyCallback ssh.HostKeyCallback
+	dialTimeout     time.Duration
+}
+
+// WithSSHKeyFile authenticates with the private key at path
+func WithSSHKeyFile(path string) SSHOption {
+	return func(s *sshSettings) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read ssh key %s: %w", path, err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		if err != nil {
+			return fmt.Errorf("failed to parse ssh key %s: %w", path, err)
+		}
+		s.authMethods = append(s.authMethods, ssh.PublicKeys(signer))
+		return nil
+	}
+}
+
+// WithSSHSigner authenticates with an already loaded key
+func WithSSHSigner(signer ssh.Signer) SSHOption {
+	return func(s *sshSettings) error {
+		if signer == nil {
+			return fmt.Errorf("ssh signer cannot be nil")
+		}
+		s.authMethods = append(s.authMethods, ssh.PublicKeys(signer))
+		return nil
+	}
+}
+
+// WithSSHAgent authenticates with the keys held by the SSH agent listening on
+// socketPath, or on $SSH_AUTH_SOCK when socketPath is empty
+func WithSSHAgent(socketPath string) SSHOption {
+	return func(s *sshSettings) error {
+		if socketPath == "" {
+			socketPath = os.Getenv("SSH_AUTH_SOCK")
+		}
+		if socketPath == "" {
+			return fmt.Errorf("no ssh agent socket: SSH_AUTH_SOCK is not set")
+		}
+		s.agentSocket = socketPath
+		return nil
+	}
+}
+
+// WithKnownHostsFile verifies the server's host key against the given
+// known_hosts files (default ~/.ssh/known_hosts)
+func WithKnownHostsFile(paths ...string) SSHOption {
+	return func(s *sshSettings) error {
+		s.knownHostsFiles = append(s.knownHostsFiles, paths...)
+		return nil
+	}
+}
+
+// WithHostKeyCallback replaces known_hosts verification with a custom callback
+func WithHostKeyCallback(callback ssh.HostKeyCallback) SSHOption {
+	return func(s *sshSettings) error {
+		s.hostKeyCallback = callback
+		return nil
+	}
+}
+
+// WithSSHDialTimeout sets the timeout for establishing the connection
+func WithSSHDialTimeout(timeout time.Duration) SSHOption {
+	return func(s *sshSettings) error {
+		s.dialTimeout = timeout
+		return nil
+	}
+}
+
+// SSHCommandRunner runs commands on a remote host over SSH. A single
+// connection is reused for every command (each command gets its own session)
+// and is re-established transparently if it drops.
+type SSHCommandRunner struct {
+	addr        string
+	config      *ssh.ClientConfig
+	agentSocket string
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// NewSSHCommandRunner creates a runner for user@addr; addr may omit the port (22).
+// At least one authentication option is required. The host key is verified
+// against ~/.ssh/known_hosts unless WithKnownHostsFile or WithHostKeyCallback is given.
+func NewSSHCommandRunner(addr, user string, opts ...SSHOption) (*SSHCommandRunner, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("ssh address cannot be empty")
+	}
+	if user == "" {
+		return nil, fmt.Errorf("ssh user cannot be empty")
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	settings := sshSettings{dialTimeout: DefaultSSHDialTimeout}
+	for _, opt := range opts {
+		if err := opt(&settings); err != nil {
+			return nil, err
+		}
+	}
+	if len(settings.authMethods) == 0 && settings.agentSocket == "" {
+		return nil, fmt.Errorf("ssh runner requires a key or agent for authentication")
+	}
+
+	hostKeyCallback := settings.hostKeyCallback
+	if hostKeyCallback == nil {
+		files := settings.knownHostsFiles
+		if len(files) == 0 {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to locate known_hosts: %w", err)
+			}
+			files = []string{filepath.Join(home, ".ssh", "known_hosts")}
+		}
+		callback, err := knownhosts.New(files...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known_hosts: %w", err)
+		}
+		hostKeyCallback = callback
+	}
+
+	return &SSHCommandRunner{
+		addr:        addr,
+		agentSocket: settings.agentSocket,
+		config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            settings.authMethods,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         settings.dialTimeout,
+		},
+	}, nil
+}
+
+// Close closes the underlying connection; a later command reconnects
+func (r *SSHCommandRunner) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		return nil
+	}
+	err := r.client.Close()
+	r.client = nil
+	return err
+}
+
+// RunCommand executes a command on the remote host and returns the output
+func (r *SSHCommandRunner) RunCommand(command string, args ...string) (string, error) {
+	return r.RunCommandInDirWithContext(context.Background(), "", command, args...)
+}
+
+// RunCommandWithContext executes a command on the remote host with context
+func (r *SSHCommandRunner) RunCommandWithContext(ctx context.Context, command string, args ...string) (string, error) {
+	return r.RunCommandInDirWithContext(ctx, "", command, args...)
+}
+
+// RunCommandInDir executes a command in a remote working directory
+func (r *SSHCommandRunner) RunCommandInDir(workingDir string, command string, args ...string) (string, error) {
+	return r.RunCommandInDirWithContext(context.Background(), workingDir, command, args...)
+}
+
+// RunCommandInDirWithContext executes a command in a remote working directory
+// with context. Canceling the context kills the remote process.
+func (r *SSHCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, command string, args ...string) (string, error) {
+	combined := &tailBuffer{}
+	err := r.run(ctx, Cmd{Name: command, Args: args, Dir: workingDir}, combined, combined)
+	if err != nil {
+		return combined.String(), err
+	}
+	return strings.TrimSpace(combined.String()), nil
+}
+
+// RunCommandStream executes a command on the remote host, delivering its output as it arrives
+func (r *SSHCommandRunner) RunCommandStream(ctx context.Context, opts StreamOptions, command string, args ...string) (string, error) {
+	limit := opts.MaxOutputBytes
+	if limit == 0 {
+		limit = DefaultMaxOutputBytes
+	}
+	combined := &tailBuffer{limit: limit}
+	var handlerMu sync.Mutex
+	stdout := newLineWriter(Stdout, opts.OnLine, &handlerMu)
+	stderr := newLineWriter(Stderr, opts.OnLine, &handlerMu)
+
+	err := r.run(ctx, Cmd{Name: command, Args: args, Dir: opts.WorkingDir},
+		streamWriter(combined, stdout, opts.Stdout), streamWriter(combined, stderr, opts.Stderr))
+	stdout.flush()
+	stderr.flush()
+
+	if err != nil {
+		return combined.String(), err
+	}
+	return strings.TrimSpace(combined.String()), nil
+}
+
+// RunCommandWithOptions executes cmd on the remote host. Env entries are set
+// through env(1) on the remote side, since most servers refuse SSH setenv requests.
+func (r *SSHCommandRunner) RunCommandWithOptions(ctx context.Context, cmd Cmd) (CommandResult, error) {
+	if cmd.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+		defer cancel()
+	}
+
+	limit := cmd.MaxOutputBytes
+	if limit == 0 {
+		limit = DefaultMaxOutputBytes
+	}
+	stdout := &tailBuffer{limit: limit}
+	stderr := &tailBuffer{limit: limit}
+	var handlerMu sync.Mutex
+	stdoutLines := newLineWriter(Stdout, cmd.OnLine, &handlerMu)
+	stderrLines := newLineWriter(Stderr, cmd.OnLine, &handlerMu)
+
+	start := time.Now()
+	err := r.run(ctx, cmd, io.MultiWriter(stdout, stdoutLines), io.MultiWriter(stderr, stderrLines))
+	stdoutLines.flush()
+	stderrLines.flush()
+
+	result := CommandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+		TimedOut: err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded),
+	}
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+		if exitErr.Signal() != "" {
+			result.ExitCode = -1
+			result.Signaled = true
+		}
+	default:
+		result.ExitCode = -1
+		result.Signaled = ctx.Err() != nil
+	}
+	return result, err
+}
+
+// run executes cmd in a new session on the shared connection
+func (r *SSHCommandRunner) run(ctx context.Context, cmd Cmd, stdout, stderr io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	session, err := r.newSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+	session.Stdin = cmd.Stdin
+
+	if err := session.Start(remoteCommand(cmd)); err != nil {
+		return fmt.Errorf("failed to start remote command %s: %w", cmd.Name, err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Ask the server to kill the process; closing the session hangs up
+		// on servers that do not support signals
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// newSession opens a session, (re)connecting when there is no usable
+// connection. Dialing happens outside the lock so a slow or unreachable host
+// does not hold up Close or commands whose context is already done.
+func (r *SSHCommandRunner) newSession(ctx context.Context) (*ssh.Session, error) {
+	r.mu.Lock()
+	client := r.client
+	r.mu.Unlock()
+
+	if client != nil {
+		session, err := client.NewSession()
+		if err == nil {
+			return session, nil
+		}
+		// The connection went away; drop it and dial again
+		r.mu.Lock()
+		if r.client == client {
+			r.client = nil
+		}
+		r.mu.Unlock()
+		_ = client.Close()
+	}
+
+	client, err := r.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	if r.client != nil {
+		// Another command connected first; share its connection
+		existing := r.client
+		r.mu.Unlock()
+		_ = client.Close()
+		session, err := existing.NewSession()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ssh session on %s: %w", r.addr, err)
+		}
+		return session, nil
+	}
+	r.client = client
+	r.mu.Unlock()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ssh session on %s: %w", r.addr, err)
+	}
+	return session, nil
+}
+
+func (r *SSHCommandRunner) dial(ctx context.Context) (*ssh.Client, error) {
+	config := *r.config
+	if r.agentSocket != "" {
+		// The agent signs over its connection, so keep it open for the
+		// handshake only; dialing per handshake picks up a restarted agent
+		agentConn, err := net.Dial("unix", r.agentSocket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ssh agent: %w", err)
+		}
+		defer agentConn.Close()
+		config.Auth = append(append([]ssh.AuthMethod(nil), config.Auth...), ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+	}
+
+	dialer := net.Dialer{Timeout: config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", r.addr, err)
+	}
+	// The handshake has no timeout of its own, so bound it by the dial
+	// timeout and ctx, whichever comes first
+	var deadline time.Time
+	if config.Timeout > 0 {
+		deadline = time.Now().Add(config.Timeout)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (deadline.IsZero() || ctxDeadline.Before(deadline)) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, r.addr, &config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh handshake with %s failed: %w", r.addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// remoteCommand renders cmd as a shell command line for the remote host
+func remoteCommand(cmd Cmd) string {
+	parts := make([]string, 0, len(cmd.Args)+len(cmd.Env)+2)
+	if len(cmd.Env) > 0 {
+		parts = append(parts, "env")
+		for _, kv := range cmd.Env {
+			parts = append(parts, shellQuote(kv))
+		}
+	}
+	parts = append(parts, shellQuote(cmd.Name))
+	for _, arg := range cmd.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	line := strings.Join(parts, " ")
+	if cmd.Dir != "" {
+		line = "cd " + shellQuote(cmd.Dir) + " && " + line
+	}
+	return line
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,@+%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,379 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSSHServer is a minimal in-process SSH server that executes "exec"
+// requests with the local shell
+type testSSHServer struct {
+	addr        string
+	hostKey     ssh.Signer
+	connections atomic.Int32
+	killed      atomic.Int32
+	listener    net.Listener
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return signer
+}
+
+func newTestSSHServer(t *testing.T, authorized ssh.PublicKey) *testSSHServer {
+	t.Helper()
+	server := &testSSHServer{hostKey: newTestSigner(t)}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized key")
+		},
+	}
+	config.AddHostKey(server.hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server.listener = listener
+	server.addr = listener.Addr().String()
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handleConn(conn, config)
+		}
+	}()
+	return server
+}
+
+func (s *testSSHServer) handleConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	s.connections.Add(1)
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.handleSession(channel, requests)
+	}
+}
+
+func (s *testSSHServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	var (
+		mu   sync.Mutex
+		proc *os.Process
+		done bool
+	)
+	kill := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if proc != nil && !done {
+			_ = syscall.Kill(-proc.Pid, syscall.SIGKILL)
+			s.killed.Add(1)
+			done = true
+		}
+	}
+
+	for req := range requests {
+		switch req.Type {
+		case "exec":
+			var payload struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			cmd := exec.Command("sh", "-c", payload.Command)
+			cmd.Stdin = channel
+			cmd.Stdout = channel
+			cmd.Stderr = channel.Stderr()
+			cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+			if err := cmd.Start(); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			mu.Lock()
+			proc = cmd.Process
+			mu.Unlock()
+			_ = req.Reply(true, nil)
+
+			go func() {
+				err := cmd.Wait()
+				mu.Lock()
+				done = true
+				mu.Unlock()
+				status := 0
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					status = exitErr.ExitCode()
+				}
+				if status >= 0 {
+					_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+				} else {
+					_, _ = channel.SendRequest("exit-signal", false, ssh.Marshal(struct {
+						Signal     string
+						CoreDumped bool
+						Error      string
+						Lang       string
+					}{Signal: "KILL"}))
+				}
+				channel.Close()
+			}()
+		case "signal":
+			kill()
+		default:
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}
+	// The client closed the session
+	kill()
+}
+
+func writeKnownHosts(t *testing.T, addr string, key ssh.PublicKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, key)
+	require.NoError(t, os.WriteFile(path, []byte(line+"\n"), 0o600))
+	return path
+}
+
+func newTestSSHRunner(t *testing.T) (*SSHCommandRunner, *testSSHServer) {
+	t.Helper()
+	clientKey := newTestSigner(t)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+	runner, err := NewSSHCommandRunner(server.addr, "deploy",
+		WithSSHSigner(clientKey),
+		WithKnownHostsFile(writeKnownHosts(t, server.addr, server.hostKey.PublicKey())),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { runner.Close() })
+	return runner, server
+}
+
+func TestSSHRunnerRunsCommandsOverOneConnection(t *testing.T) {
+	runner, server := newTestSSHRunner(t)
+
+	output, err := runner.RunCommand("echo", "hello world")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", output)
+
+	dir := t.TempDir()
+	output, err = runner.RunCommandInDir(dir, "pwd")
+	require.NoError(t, err)
+	assert.Equal(t, dir, output)
+
+	output, err = runner.RunCommandWithContext(context.Background(), "sh", "-c", "echo oops 1>&2; exit 4")
+	assert.Error(t, err)
+	assert.Equal(t, "oops\n", output)
+
+	assert.Equal(t, int32(1), server.connections.Load(), "Commands should share one connection")
+
+	// A closed connection is re-established on the next command
+	require.NoError(t, runner.Close())
+	_, err = runner.RunCommand("true")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), server.connections.Load())
+}
+
+func TestSSHRunnerWithOptions(t *testing.T) {
+	runner, _ := newTestSSHRunner(t)
+
+	result, err := runner.RunCommandWithOptions(context.Background(), Cmd{
+		Name:  "sh",
+		Args:  []string{"-c", "echo $GREETING; cat; echo problem 1>&2; exit 3"},
+		Env:   []string{"GREETING=it's me"},
+		Stdin: strings.NewReader("from stdin\n"),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "it's me\nfrom stdin\n", result.Stdout)
+	assert.Equal(t, "problem\n", result.Stderr)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.False(t, result.Signaled)
+
+	var lines []string
+	output, err := runner.RunCommandStream(context.Background(), StreamOptions{
+		OnLine: func(stream OutputStream, line string) { lines = append(lines, string(stream)+":"+line) },
+	}, "echo", "streamed")
+	require.NoError(t, err)
+	assert.Equal(t, "streamed", output)
+	assert.Equal(t, []string{"stdout:streamed"}, lines)
+}
+
+func TestSSHRunnerCancellationKillsRemoteProcess(t *testing.T) {
+	runner, server := newTestSSHRunner(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := runner.RunCommandWithContext(ctx, "sleep", "5")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Eventually(t, func() bool { return server.killed.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The connection stays usable after a canceled command
+	output, err := runner.RunCommand("echo", "still here")
+	require.NoError(t, err)
+	assert.Equal(t, "still here", output)
+}
+
+func TestSSHRunnerRejectsUnknownHostKey(t *testing.T) {
+	clientKey := newTestSigner(t)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+
+	runner, err := NewSSHCommandRunner(server.addr, "deploy",
+		WithSSHSigner(clientKey),
+		WithKnownHostsFile(writeKnownHosts(t, server.addr, newTestSigner(t).PublicKey())),
+	)
+	require.NoError(t, err)
+
+	_, err = runner.RunCommand("true")
+	assert.ErrorContains(t, err, "handshake")
+	var keyErr *knownhosts.KeyError
+	assert.ErrorAs(t, err, &keyErr)
+}
+
+func TestSSHRunnerHandshakeTimeout(t *testing.T) {
+	// The host accepts connections and holds them open without speaking SSH
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	runner, err := NewSSHCommandRunner(listener.Addr().String(), "deploy",
+		WithSSHSigner(newTestSigner(t)),
+		WithHostKeyCallback(ssh.InsecureIgnoreHostKey()),
+		WithSSHDialTimeout(200*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	dialed := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		_, err := runner.RunCommand("true")
+		dialed <- err
+	}()
+
+	// The pending dial does not hold the runner's lock
+	time.Sleep(50 * time.Millisecond)
+	closed := make(chan struct{})
+	go func() {
+		_ = runner.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Close blocked on a pending dial")
+	}
+
+	select {
+	case err := <-dialed:
+		assert.ErrorContains(t, err, "handshake")
+		assert.Less(t, time.Since(start), 2*time.Second)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handshake was not bounded by the dial timeout")
+	}
+}
+
+func TestSSHRunnerAgentAuthentication(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientKey, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	server := newTestSSHServer(t, clientKey.PublicKey())
+
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: priv}))
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+
+	runner, err := NewSSHCommandRunner(server.addr, "deploy",
+		WithSSHAgent(socket),
+		WithKnownHostsFile(writeKnownHosts(t, server.addr, server.hostKey.PublicKey())),
+	)
+	require.NoError(t, err)
+	defer runner.Close()
+
+	output, err := runner.RunCommand("echo", "via agent")
+	require.NoError(t, err)
+	assert.Equal(t, "via agent", output)
+}
+
+func TestNewSSHCommandRunnerValidation(t *testing.T) {
+	_, err := NewSSHCommandRunner("", "deploy", WithSSHSigner(newTestSigner(t)))
+	assert.Error(t, err)
+	_, err = NewSSHCommandRunner("host", "", WithSSHSigner(newTestSigner(t)))
+	assert.Error(t, err)
+	_, err = NewSSHCommandRunner("host", "deploy")
+	assert.ErrorContains(t, err, "authentication")
+	_, err = NewSSHCommandRunner("host", "deploy", WithSSHKeyFile("/nonexistent/key"))
+	assert.Error(t, err)
+}
+
+func TestRemoteCommandQuoting(t *testing.T) {
+	assert.Equal(t, "echo hello", remoteCommand(Cmd{Name: "echo", Args: []string{"hello"}}))
+	assert.Equal(t, `echo 'it'\''s' '' '$HOME'`, remoteCommand(Cmd{Name: "echo", Args: []string{"it's", "", "$HOME"}}))
+	assert.Equal(t, "cd '/srv/my app' && env A=1 ls -la", remoteCommand(Cmd{Name: "ls", Args: []string{"-la"}, Dir: "/srv/my app", Env: []string{"A=1"}}))
+}
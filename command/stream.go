@@ -0,0 +1,199 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// OutputStream identifies the stream a line of output was read from
+type OutputStream string
+
+const (
+	Stdout OutputStream = "stdout"
+	Stderr OutputStream = "stderr"
+)
+
+// DefaultMaxOutputBytes is how much combined output a streaming run retains
+// when StreamOptions.MaxOutputBytes is zero
+const DefaultMaxOutputBytes = 1 << 20
+
+// MaxLineBytes is the longest line a LineHandler receives. Output that runs
+// longer without a newline, such as a progress bar redrawn with \r or binary
+// data, is delivered in pieces of this size.
+const MaxLineBytes = 64 << 10
+
+// LineHandler receives each line of output as the command produces it.
+// Calls are serialized, so handlers do not need to be safe for concurrent use.
+type LineHandler func(stream OutputStream, line string)
+
+// StreamOptions configures a streaming command run
+type StreamOptions struct {
+	// WorkingDir is the directory to run the command in (empty for the current one)
+	WorkingDir string
+	// OnLine is called for every complete line written to stdout or stderr
+	OnLine LineHandler
+	// Stdout and Stderr optionally receive the raw output as it arrives
+	Stdout io.Writer
+	Stderr io.Writer
+	// MaxOutputBytes caps the combined output returned once the command exits;
+	// only the most recent bytes are kept. Zero means DefaultMaxOutputBytes and
+	// a negative value disables the cap.
+	MaxOutputBytes int
+}
+
+// StreamingCommandRunner is implemented by runners that can deliver output
+// while a command is still running
+type StreamingCommandRunner interface {
+	CommandRunner
+	RunCommandStream(ctx context.Context, opts StreamOptions, command string, args ...string) (string, error)
+}
+
+// RunStreaming runs a command through runner, streaming its output when the
+// runner supports it. Other runners fall back to RunCommandWithContext or
+// RunCommandInDirWithContext and the output is delivered to OnLine once the
+// command has finished.
+func RunStreaming(ctx context.Context, runner CommandRunner, opts StreamOptions, command string, args ...string) (string, error) {
+	if streaming, ok := runner.(StreamingCommandRunner); ok {
+		return streaming.RunCommandStream(ctx, opts, command, args...)
+	}
+
+	var output string
+	var err error
+	if opts.WorkingDir != "" {
+		output, err = runner.RunCommandInDirWithContext(ctx, opts.WorkingDir, command, args...)
+	} else {
+		output, err = runner.RunCommandWithContext(ctx, command, args...)
+	}
+	if opts.OnLine != nil && output != "" {
+		for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+			opts.OnLine(Stdout, line)
+		}
+	}
+	if opts.Stdout != nil && output != "" {
+		_, _ = io.WriteString(opts.Stdout, output)
+	}
+	return output, err
+}
+
+// LogLines returns a LineHandler that writes each line to logger at debug level
+func LogLines(logger *slog.Logger, msg string, attrs ...any) LineHandler {
+	return func(stream OutputStream, line string) {
+		if logger == nil {
+			return
+		}
+		logger.Debug(msg, append([]any{"stream", string(stream), "line", line}, attrs...)...)
+	}
+}
+
+// RunCommandStream executes a command, delivering its output as it is produced
+// and returning the combined output (capped per opts) once it exits
+func (r *DefaultCommandRunner) RunCommandStream(ctx context.Context, opts StreamOptions, command string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+	stopGracefully(ctx, cmd)
+	if opts.WorkingDir != "" {
+		cmd.Dir = opts.WorkingDir
+	}
+
+	limit := opts.MaxOutputBytes
+	if limit == 0 {
+		limit = DefaultMaxOutputBytes
+	}
+	combined := &tailBuffer{limit: limit}
+	var handlerMu sync.Mutex
+
+	stdout := newLineWriter(Stdout, opts.OnLine, &handlerMu)
+	stderr := newLineWriter(Stderr, opts.OnLine, &handlerMu)
+	cmd.Stdout = streamWriter(combined, stdout, opts.Stdout)
+	cmd.Stderr = streamWriter(combined, stderr, opts.Stderr)
+
+	err := cmd.Run()
+	stdout.flush()
+	stderr.flush()
+
+	output := combined.String()
+	if err != nil {
+		return output, err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func streamWriter(combined *tailBuffer, lines *lineWriter, raw io.Writer) io.Writer {
+	if raw == nil {
+		return io.MultiWriter(combined, lines)
+	}
+	return io.MultiWriter(combined, lines, raw)
+}
+
+// tailBuffer keeps the most recent limit bytes written to it
+type tailBuffer struct {
+	mu    sync.Mutex
+	buf   []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if b.limit > 0 && len(b.buf) > b.limit {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.limit:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
+
+// lineWriter splits written bytes into lines for a LineHandler
+type lineWriter struct {
+	stream  OutputStream
+	handler LineHandler
+	mu      *sync.Mutex
+	partial []byte
+}
+
+func newLineWriter(stream OutputStream, handler LineHandler, mu *sync.Mutex) *lineWriter {
+	return &lineWriter{stream: stream, handler: handler, mu: mu}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if w.handler == nil {
+		return len(p), nil
+	}
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(bytes.TrimRight(w.partial[:i], "\r")))
+		w.partial = w.partial[i+1:]
+	}
+	for len(w.partial) >= MaxLineBytes {
+		w.emit(string(w.partial[:MaxLineBytes]))
+		w.partial = w.partial[MaxLineBytes:]
+	}
+	return len(p), nil
+}
+
+// flush delivers a trailing line that was not terminated by a newline
+func (w *lineWriter) flush() {
+	if w.handler != nil && len(w.partial) > 0 {
+		w.emit(string(w.partial))
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handler(w.stream, line)
+}
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedLine struct {
+	stream OutputStream
+	line   string
+}
+
+func TestRunCommandStreamDeliversLines(t *testing.T) {
+	runner := NewDefaultCommandRunner()
+
+	var mu sync.Mutex
+	var lines []recordedLine
+	var raw bytes.Buffer
+	output, err := runner.RunCommandStream(context.Background(), StreamOptions{
+		OnLine: func(stream OutputStream, line string) {
+			mu.Lock()
+			lines = append(lines, recordedLine{stream, line})
+			mu.Unlock()
+		},
+		Stdout: &raw,
+	}, "sh", "-c", "echo one; echo two 1>&2; printf three")
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "one")
+	assert.Contains(t, output, "two")
+	assert.Contains(t, output, "three")
+	assert.Equal(t, "one\nthree", raw.String())
+	assert.ElementsMatch(t, []recordedLine{{Stdout, "one"}, {Stderr, "two"}, {Stdout, "three"}}, lines)
+}
+
+func TestRunCommandStreamCapsOutput(t *testing.T) {
+	runner := NewDefaultCommandRunner()
+
+	var count int
+	output, err := runner.RunCommandStream(context.Background(), StreamOptions{
+		OnLine:         func(OutputStream, string) { count++ },
+		MaxOutputBytes: 6,
+	}, "sh", "-c", "for i in 1 2 3 4 5 6 7 8 9; do echo line$i; done")
+
+	require.NoError(t, err)
+	assert.Equal(t, 9, count, "Every line is delivered even though output is capped")
+	assert.Equal(t, "line9", output)
+}
+
+func TestLineWriterCapsUnterminatedLines(t *testing.T) {
+	var lines []string
+	w := newLineWriter(Stdout, func(_ OutputStream, line string) { lines = append(lines, line) }, &sync.Mutex{})
+
+	bar := bytes.Repeat([]byte("#\r"), MaxLineBytes)
+	for i := 0; i < len(bar); i += 4096 {
+		_, err := w.Write(bar[i:min(i+4096, len(bar))])
+		require.NoError(t, err)
+		assert.Less(t, len(w.partial), MaxLineBytes, "the buffer never holds more than one line")
+	}
+	w.flush()
+
+	require.Len(t, lines, 2)
+	assert.Len(t, lines[0], MaxLineBytes)
+	assert.Equal(t, string(bar), lines[0]+lines[1])
+}
+
+func TestRunCommandStreamFailure(t *testing.T) {
+	runner := NewDefaultCommandRunner()
+
+	output, err := runner.RunCommandStream(context.Background(), StreamOptions{WorkingDir: "/"}, "sh", "-c", "pwd; exit 3")
+	assert.Error(t, err)
+	assert.Equal(t, "/\n", output, "Output is returned untrimmed on failure like RunCommandWithContext")
+}
+
+// bufferedRunner only implements CommandRunner
+type bufferedRunner struct {
+	dir string
+}
+
+func (r *bufferedRunner) RunCommand(command string, args ...string) (string, error) {
+	return r.RunCommandWithContext(context.Background(), command, args...)
+}
+
+func (r *bufferedRunner) RunCommandInDir(workingDir string, command string, args ...string) (string, error) {
+	return r.RunCommandInDirWithContext(context.Background(), workingDir, command, args...)
+}
+
+func (r *bufferedRunner) RunCommandWithContext(ctx context.Context, command string, args ...string) (string, error) {
+	return "first\nsecond", errors.New("buffered")
+}
+
+func (r *bufferedRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, command string, args ...string) (string, error) {
+	r.dir = workingDir
+	return "", nil
+}
+
+func TestRunStreamingFallsBackForBufferedRunners(t *testing.T) {
+	runner := &bufferedRunner{}
+
+	var lines []string
+	output, err := RunStreaming(context.Background(), runner, StreamOptions{
+		OnLine: func(stream OutputStream, line string) { lines = append(lines, line) },
+	}, "anything")
+	assert.EqualError(t, err, "buffered")
+	assert.Equal(t, "first\nsecond", output)
+	assert.Equal(t, []string{"first", "second"}, lines)
+
+	_, err = RunStreaming(context.Background(), runner, StreamOptions{WorkingDir: "/tmp"}, "anything")
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp", runner.dir)
+}
+
+func TestRunStreamingUsesStreamingRunner(t *testing.T) {
+	var runner CommandRunner = NewDefaultCommandRunner()
+	_, ok := runner.(StreamingCommandRunner)
+	require.True(t, ok)
+
+	var lines []string
+	output, err := RunStreaming(context.Background(), runner, StreamOptions{
+		OnLine: func(stream OutputStream, line string) { lines = append(lines, line) },
+	}, "echo", "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", output)
+	assert.Equal(t, []string{"hello"}, lines)
+}
@@ -0,0 +1,190 @@
+// Package client talks to a task engine daemon over its Unix domain socket
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/ndizazzo/task-engine/daemon/protocol"
+)
+
+// maxMessageSize bounds a single response line
+const maxMessageSize = 16 << 20
+
+// Client sends requests to a daemon. Calls are serialized over one
+// connection; a Client is safe for concurrent use.
+type Client struct {
+	socketPath string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	scanner *bufio.Scanner
+	nextID  uint64
+}
+
+// Dial connects to the daemon listening on socketPath
+func Dial(ctx context.Context, socketPath string) (*Client, error) {
+	c := &Client{socketPath: socketPath}
+	conn, scanner, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.conn, c.scanner = conn, scanner
+	return c, nil
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, *bufio.Scanner, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", c.socketPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to daemon at %s: %w", c.socketPath, err)
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	return conn, scanner, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// AddTask defines a task on the daemon from a definition. With replace, an
+// idle task with the same ID is redefined.
+func (c *Client) AddTask(ctx context.Context, def protocol.TaskDefinition, replace bool) (protocol.TaskStatus, error) {
+	var status protocol.TaskStatus
+	err := c.call(ctx, protocol.MethodAddTask, protocol.AddTaskParams{Definition: def, Replace: replace}, &status)
+	return status, err
+}
+
+// RunTask starts a registered task with values for its declared inputs
+// (which may be nil)
+func (c *Client) RunTask(ctx context.Context, taskID string, inputs map[string]interface{}) (protocol.TaskStatus, error) {
+	var status protocol.TaskStatus
+	err := c.call(ctx, protocol.MethodRunTask, protocol.RunTaskParams{TaskID: taskID, Inputs: inputs}, &status)
+	return status, err
+}
+
+// StopTask cancels a running task
+func (c *Client) StopTask(ctx context.Context, taskID string) (protocol.TaskStatus, error) {
+	var status protocol.TaskStatus
+	err := c.call(ctx, protocol.MethodStopTask, protocol.TaskParams{TaskID: taskID}, &status)
+	return status, err
+}
+
+// Status reports one task, or every task when taskID is empty
+func (c *Client) Status(ctx context.Context, taskID string) ([]protocol.TaskStatus, error) {
+	var result protocol.StatusResult
+	err := c.call(ctx, protocol.MethodStatus, protocol.StatusParams{TaskID: taskID}, &result)
+	return result.Tasks, err
+}
+
+// Tail returns up to the last n log entries of a task; n <= 0 returns all
+// buffered entries
+func (c *Client) Tail(ctx context.Context, taskID string, n int) ([]protocol.LogEntry, error) {
+	var result protocol.TailResult
+	err := c.call(ctx, protocol.MethodTail, protocol.TailParams{TaskID: taskID, Lines: n}, &result)
+	return result.Entries, err
+}
+
+// Follow calls fn with up to the last n log entries of a task and then with
+// each new entry until ctx is canceled or the daemon goes away. It uses a
+// connection of its own, so the Client stays usable meanwhile. A canceled
+// ctx is not reported as an error.
+func (c *Client) Follow(ctx context.Context, taskID string, n int, fn func(protocol.LogEntry)) error {
+	conn, scanner, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var result protocol.TailResult
+	params := protocol.TailParams{TaskID: taskID, Lines: n, Follow: true}
+	if err := roundTrip(conn, scanner, 1, protocol.MethodTail, params, &result); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range result.Entries {
+		fn(entry)
+	}
+
+	for scanner.Scan() {
+		var msg protocol.Message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return fmt.Errorf("invalid message from daemon: %w", err)
+		}
+		if msg.Method != protocol.MethodLog {
+			continue
+		}
+		var entry protocol.LogEntry
+		if err := json.Unmarshal(msg.Params, &entry); err != nil {
+			return fmt.Errorf("invalid log entry from daemon: %w", err)
+		}
+		fn(entry)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("lost connection to daemon: %w", err)
+	}
+	return errors.New("daemon closed the connection")
+}
+
+// call sends one request and decodes its result. A canceled ctx closes the
+// connection, after which the Client can no longer be used.
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, func() { c.conn.Close() })
+	defer stop()
+
+	c.nextID++
+	err := roundTrip(c.conn, c.scanner, c.nextID, method, params, result)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// roundTrip writes a request and reads lines until the matching response
+func roundTrip(conn net.Conn, scanner *bufio.Scanner, id uint64, method string, params, result interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+	if err := json.NewEncoder(conn).Encode(protocol.Message{JSONRPC: protocol.Version, ID: id, Method: method, Params: data}); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	for scanner.Scan() {
+		var resp protocol.Message
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			return fmt.Errorf("invalid response to %s: %w", method, err)
+		}
+		if resp.ID != id {
+			continue
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("invalid result for %s: %w", method, err)
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	return fmt.Errorf("daemon closed the connection during %s", method)
+}
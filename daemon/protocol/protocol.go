@@ -0,0 +1,183 @@
+// Package protocol defines the JSON-RPC 2.0 messages exchanged between the
+// task engine daemon and its clients. Messages are newline-delimited JSON
+// objects sent over a Unix domain socket.
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the JSON-RPC version carried by every message
+const Version = "2.0"
+
+// DefaultSocketPath is where the daemon listens unless configured otherwise
+const DefaultSocketPath = "/run/task-engine/engine.sock"
+
+// Methods served by the daemon
+const (
+	MethodAddTask  = "AddTask"
+	MethodRunTask  = "RunTask"
+	MethodStopTask = "StopTask"
+	MethodStatus   = "Status"
+	MethodTail     = "Tail"
+	// MethodLog is the notification carrying a LogEntry to a following Tail
+	MethodLog = "log"
+)
+
+// Error codes; the negative range below -32000 is defined by JSON-RPC 2.0
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeTaskNotFound   = -32001
+	CodeConflict       = -32002
+)
+
+// Message is a request, response or notification. Requests carry an ID and a
+// Method; responses carry the request's ID and either Result or Error;
+// notifications carry a Method but no ID.
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// Errorf creates an Error with a formatted message
+func Errorf(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// IsCode reports whether err is an *Error with the given code
+func IsCode(err error, code int) bool {
+	var rpcErr *Error
+	return errors.As(err, &rpcErr) && rpcErr.Code == code
+}
+
+// TaskDefinition describes a task to build on the daemon. Type names a task
+// factory registered with the daemon; Params are passed to it unchanged.
+type TaskDefinition struct {
+	ID     string                 `yaml:"id" json:"id"`
+	Name   string                 `yaml:"name,omitempty" json:"name,omitempty"`
+	Type   string                 `yaml:"type" json:"type"`
+	Params map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"`
+}
+
+// Validate checks that the definition has an ID and a type
+func (d TaskDefinition) Validate() error {
+	if d.ID == "" {
+		return errors.New("task definition has no id")
+	}
+	if d.Type == "" {
+		return fmt.Errorf("task definition %q has no type", d.ID)
+	}
+	return nil
+}
+
+// LoadTaskDefinition reads a task definition from a YAML (or JSON) file
+func LoadTaskDefinition(path string) (TaskDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TaskDefinition{}, fmt.Errorf("failed to read task definition %s: %w", path, err)
+	}
+	def, err := ParseTaskDefinition(data)
+	if err != nil {
+		return TaskDefinition{}, fmt.Errorf("invalid task definition %s: %w", path, err)
+	}
+	return def, nil
+}
+
+// ParseTaskDefinition parses and validates a YAML (or JSON) task definition
+func ParseTaskDefinition(data []byte) (TaskDefinition, error) {
+	var def TaskDefinition
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&def); err != nil {
+		return TaskDefinition{}, err
+	}
+	return def, def.Validate()
+}
+
+// AddTaskParams are the parameters of MethodAddTask. Replace allows an idle
+// task with the same ID to be redefined.
+type AddTaskParams struct {
+	Definition TaskDefinition `json:"definition"`
+	Replace    bool           `json:"replace,omitempty"`
+}
+
+// RunTaskParams are the parameters of MethodRunTask; Inputs supply the task's
+// declared inputs
+type RunTaskParams struct {
+	TaskID string                 `json:"taskID"`
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+}
+
+// TaskParams identify the task for MethodStopTask
+type TaskParams struct {
+	TaskID string `json:"taskID"`
+}
+
+// StatusParams are the parameters of MethodStatus; an empty TaskID reports every task
+type StatusParams struct {
+	TaskID string `json:"taskID,omitempty"`
+}
+
+// TaskStatus is returned by MethodAddTask, MethodRunTask and MethodStopTask,
+// and a list of them by MethodStatus
+type TaskStatus struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	State string `json:"state"`
+	// Status is the outcome of the last run (success, degraded or failed)
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Progress describes the current step of a running task
+	Progress string `json:"progress,omitempty"`
+}
+
+// StatusResult is the result of MethodStatus
+type StatusResult struct {
+	Tasks []TaskStatus `json:"tasks"`
+}
+
+// TailParams are the parameters of MethodTail. Lines limits how many buffered
+// entries are returned (zero means all); Follow keeps the connection open and
+// streams new entries as MethodLog notifications until the client disconnects.
+type TailParams struct {
+	TaskID string `json:"taskID"`
+	Lines  int    `json:"lines,omitempty"`
+	Follow bool   `json:"follow,omitempty"`
+}
+
+// TailResult is the result of MethodTail
+type TailResult struct {
+	Entries []LogEntry `json:"entries"`
+}
+
+// LogEntry is one log line written while a daemon task ran
+type LogEntry struct {
+	TaskID string    `json:"taskID"`
+	Time   time.Time `json:"time"`
+	Line   string    `json:"line"`
+}
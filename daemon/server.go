@@ -0,0 +1,565 @@
+// Package daemon runs a TaskManager as a long-lived local service. Clients
+// talk to it with newline-delimited JSON-RPC 2.0 (see package protocol) over
+// a Unix domain socket; access is controlled by the socket file's owner,
+// group and mode.
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/daemon/protocol"
+)
+
+const (
+	// DefaultSocketMode lets only the daemon's user connect
+	DefaultSocketMode os.FileMode = 0o600
+	// DefaultLogLines is how many log lines are kept per task
+	DefaultLogLines = 1000
+	// maxMessageSize bounds a single request line
+	maxMessageSize = 1 << 20
+	// followBuffer is how many log entries a following client may lag behind
+	followBuffer = 256
+)
+
+// TaskFactory builds a task from a definition sent by a client. The logger
+// records into the task's log (see Tail) as well as the daemon's logger, and
+// should be given to the task's actions.
+type TaskFactory func(logger *slog.Logger, def protocol.TaskDefinition) (*task_engine.Task, error)
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithLogger sets the daemon's own logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithTaskFactory registers the factory used for definitions of the given type
+func WithTaskFactory(taskType string, factory TaskFactory) Option {
+	return func(s *Server) {
+		s.factories[taskType] = factory
+	}
+}
+
+// WithSocketMode sets the permissions of the socket file, e.g. 0660 to admit
+// members of the socket's group
+func WithSocketMode(mode os.FileMode) Option {
+	return func(s *Server) {
+		s.socketMode = mode
+	}
+}
+
+// WithSocketGroup sets the group owning the socket file
+func WithSocketGroup(gid int) Option {
+	return func(s *Server) {
+		s.socketGID = gid
+	}
+}
+
+// WithLogLines sets how many log lines are kept per task for Tail
+func WithLogLines(lines int) Option {
+	return func(s *Server) {
+		s.logLines = lines
+	}
+}
+
+// Server serves a TaskManager over a Unix domain socket
+type Server struct {
+	manager    *task_engine.TaskManager
+	logger     *slog.Logger
+	factories  map[string]TaskFactory
+	socketMode os.FileMode
+	socketGID  int
+	logLines   int
+
+	// addMu serializes AddTask so the existence check and the add are atomic
+	addMu sync.Mutex
+
+	mu          sync.Mutex
+	logs        map[string]*taskLog
+	listener    net.Listener
+	socketPath  string
+	conns       map[net.Conn]struct{}
+	closed      bool
+	wg          sync.WaitGroup
+	unsubscribe func()
+}
+
+// NewServer creates a daemon for the manager. Tasks can be registered up
+// front with manager.AddTask, preferably built with TaskLogger so their
+// output can be tailed, or defined by clients through registered factories.
+func NewServer(manager *task_engine.TaskManager, opts ...Option) *Server {
+	s := &Server{
+		manager:    manager,
+		logger:     slog.New(slog.DiscardHandler),
+		factories:  make(map[string]TaskFactory),
+		socketMode: DefaultSocketMode,
+		socketGID:  -1,
+		logLines:   DefaultLogLines,
+		logs:       make(map[string]*taskLog),
+		conns:      make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.unsubscribe = manager.Subscribe(s.recordEvent)
+	return s
+}
+
+// TaskLogger returns a logger that writes to the task's tailable log and to
+// the daemon's logger
+func (s *Server) TaskLogger(taskID string) *slog.Logger {
+	log := s.taskLog(taskID)
+	return slog.New(teeHandler{log.handler(), s.logger.Handler()}).With("taskID", taskID)
+}
+
+func (s *Server) taskLog(taskID string) *taskLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log, ok := s.logs[taskID]
+	if !ok {
+		log = newTaskLog(taskID, s.logLines)
+		s.logs[taskID] = log
+	}
+	return log
+}
+
+// recordEvent appends a managed task's lifecycle event to its log
+func (s *Server) recordEvent(event task_engine.TaskEvent) {
+	if event.Type == task_engine.EventActionProgress {
+		return
+	}
+	s.mu.Lock()
+	log := s.logs[event.TaskID]
+	s.mu.Unlock()
+	if log == nil {
+		return
+	}
+
+	level := slog.LevelInfo
+	attrs := []slog.Attr{slog.String("runID", event.RunID)}
+	if event.ActionID != "" {
+		attrs = append(attrs, slog.String("actionID", event.ActionID))
+	}
+	if event.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	}
+	if event.Error != "" {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", event.Error))
+	}
+	log.events.LogAttrs(context.Background(), level, string(event.Type), attrs...)
+}
+
+// Listen creates the socket. A stale socket left by a previous daemon is
+// replaced; a socket another daemon is still serving is not.
+func (s *Server) Listen(socketPath string) error {
+	if err := removeStaleSocket(socketPath); err != nil {
+		return err
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+	if err := os.Chmod(socketPath, s.socketMode); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to set permissions on %s: %w", socketPath, err)
+	}
+	if s.socketGID >= 0 {
+		if err := os.Chown(socketPath, -1, s.socketGID); err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to set group on %s: %w", socketPath, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.listener = listener
+	s.socketPath = socketPath
+	s.mu.Unlock()
+	s.logger.Info("Daemon listening", "socket", socketPath)
+	return nil
+}
+
+func removeStaleSocket(socketPath string) error {
+	info, err := os.Lstat(socketPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", socketPath)
+	}
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", socketPath)
+	}
+	return os.Remove(socketPath)
+}
+
+// Serve accepts connections until ctx is canceled or Close is called, then
+// waits for open connections to finish. Listen must be called first.
+func (s *Server) Serve(ctx context.Context) error {
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+	if listener == nil {
+		return errors.New("daemon is not listening")
+	}
+
+	stop := context.AfterFunc(ctx, func() { s.Close() })
+	defer stop()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				break
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			break
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+	s.wg.Wait()
+	return nil
+}
+
+// ListenAndServe calls Listen and then Serve
+func (s *Server) ListenAndServe(ctx context.Context, socketPath string) error {
+	if err := s.Listen(socketPath); err != nil {
+		return err
+	}
+	return s.Serve(ctx)
+}
+
+// Close stops accepting connections, closes open ones and removes the socket.
+// Running tasks are left to the TaskManager.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	listener, socketPath := s.listener, s.socketPath
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.unsubscribe()
+	if listener == nil {
+		return nil
+	}
+	err := listener.Close()
+	if removeErr := os.Remove(socketPath); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) && err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req protocol.Message
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			s.reply(encoder, 0, nil, protocol.Errorf(protocol.CodeParseError, "invalid JSON: %v", err))
+			continue
+		}
+		if req.JSONRPC != protocol.Version || req.Method == "" {
+			s.reply(encoder, req.ID, nil, protocol.Errorf(protocol.CodeInvalidRequest, "not a JSON-RPC %s request", protocol.Version))
+			continue
+		}
+
+		if req.Method == protocol.MethodTail {
+			var params protocol.TailParams
+			if rpcErr := decodeParams(req, &params); rpcErr != nil {
+				s.reply(encoder, req.ID, nil, rpcErr)
+				continue
+			}
+			if params.Follow {
+				// The connection now belongs to the stream
+				s.followTail(conn, scanner, encoder, req.ID, params)
+				return
+			}
+		}
+
+		result, rpcErr := s.dispatch(req)
+		if !s.reply(encoder, req.ID, result, rpcErr) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Debug("Connection read failed", "error", err)
+	}
+}
+
+// reply writes a response and reports whether the connection is still usable
+func (s *Server) reply(encoder *json.Encoder, id uint64, result interface{}, rpcErr *protocol.Error) bool {
+	resp := protocol.Message{JSONRPC: protocol.Version, ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = protocol.Errorf(protocol.CodeInternalError, "failed to encode result: %v", err)
+		} else {
+			resp.Result = data
+		}
+	}
+	if err := encoder.Encode(resp); err != nil {
+		s.logger.Debug("Failed to write response", "error", err)
+		return false
+	}
+	return true
+}
+
+func decodeParams(req protocol.Message, into interface{}) *protocol.Error {
+	if len(req.Params) == 0 {
+		return protocol.Errorf(protocol.CodeInvalidParams, "%s requires params", req.Method)
+	}
+	if err := json.Unmarshal(req.Params, into); err != nil {
+		return protocol.Errorf(protocol.CodeInvalidParams, "invalid params for %s: %v", req.Method, err)
+	}
+	return nil
+}
+
+func (s *Server) dispatch(req protocol.Message) (interface{}, *protocol.Error) {
+	switch req.Method {
+	case protocol.MethodAddTask:
+		var params protocol.AddTaskParams
+		if rpcErr := decodeParams(req, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return s.addTask(params)
+	case protocol.MethodRunTask:
+		var params protocol.RunTaskParams
+		if rpcErr := decodeParams(req, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return s.runTask(params.TaskID, params.Inputs)
+	case protocol.MethodStopTask:
+		var params protocol.TaskParams
+		if rpcErr := decodeParams(req, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		return s.stopTask(params.TaskID)
+	case protocol.MethodStatus:
+		var params protocol.StatusParams
+		if len(req.Params) > 0 {
+			if rpcErr := decodeParams(req, &params); rpcErr != nil {
+				return nil, rpcErr
+			}
+		}
+		return s.statusAll(params.TaskID)
+	case protocol.MethodTail:
+		var params protocol.TailParams
+		if rpcErr := decodeParams(req, &params); rpcErr != nil {
+			return nil, rpcErr
+		}
+		log, rpcErr := s.existingLog(params.TaskID)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		return protocol.TailResult{Entries: log.tail(params.Lines)}, nil
+	default:
+		return nil, protocol.Errorf(protocol.CodeMethodNotFound, "unknown method %q", req.Method)
+	}
+}
+
+func (s *Server) addTask(params protocol.AddTaskParams) (protocol.TaskStatus, *protocol.Error) {
+	def := params.Definition
+	if err := def.Validate(); err != nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeInvalidParams, "%v", err)
+	}
+	factory, ok := s.factories[def.Type]
+	if !ok {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeInvalidParams, "unknown task type %q", def.Type)
+	}
+
+	s.addMu.Lock()
+	defer s.addMu.Unlock()
+
+	if _, err := s.manager.GetTask(def.ID); err == nil {
+		if !params.Replace {
+			return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeConflict, "task %q already exists", def.ID)
+		}
+		if s.manager.IsTaskRunning(def.ID) {
+			return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeConflict, "task %q is running", def.ID)
+		}
+	}
+
+	task, err := factory(s.TaskLogger(def.ID), def)
+	if err != nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeInvalidParams, "failed to build task %q: %v", def.ID, err)
+	}
+	if task == nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeInternalError, "factory for type %q returned no task", def.Type)
+	}
+	task.ID = def.ID
+	if def.Name != "" {
+		task.Name = def.Name
+	}
+	if err := s.manager.AddTask(task); err != nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeInternalError, "%v", err)
+	}
+	s.logger.Info("Task defined", "taskID", def.ID, "type", def.Type)
+	return s.status(def.ID)
+}
+
+func (s *Server) runTask(taskID string, inputs map[string]interface{}) (protocol.TaskStatus, *protocol.Error) {
+	if _, rpcErr := s.status(taskID); rpcErr != nil {
+		return protocol.TaskStatus{}, rpcErr
+	}
+	if s.manager.IsTaskRunning(taskID) {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeConflict, "task %q is already running", taskID)
+	}
+	if err := s.manager.RunTaskWithInputs(taskID, inputs); err != nil {
+		if errors.Is(err, task_engine.ErrShuttingDown) {
+			return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeConflict, "%v", err)
+		}
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeInvalidParams, "%v", err)
+	}
+	return s.status(taskID)
+}
+
+func (s *Server) stopTask(taskID string) (protocol.TaskStatus, *protocol.Error) {
+	if _, rpcErr := s.status(taskID); rpcErr != nil {
+		return protocol.TaskStatus{}, rpcErr
+	}
+	if err := s.manager.StopTask(taskID); err != nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeConflict, "%v", err)
+	}
+	return s.status(taskID)
+}
+
+func (s *Server) statusAll(taskID string) (protocol.StatusResult, *protocol.Error) {
+	taskIDs := []string{taskID}
+	if taskID == "" {
+		taskIDs = s.manager.ListTasks()
+	}
+
+	result := protocol.StatusResult{Tasks: []protocol.TaskStatus{}}
+	for _, id := range taskIDs {
+		status, rpcErr := s.status(id)
+		if rpcErr != nil {
+			if taskID != "" {
+				return protocol.StatusResult{}, rpcErr
+			}
+			// Removed while listing
+			continue
+		}
+		result.Tasks = append(result.Tasks, status)
+	}
+	return result, nil
+}
+
+func (s *Server) status(taskID string) (protocol.TaskStatus, *protocol.Error) {
+	task, err := s.manager.GetTask(taskID)
+	if err != nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeTaskNotFound, "%v", err)
+	}
+	state, err := s.manager.GetTaskState(taskID)
+	if err != nil {
+		return protocol.TaskStatus{}, protocol.Errorf(protocol.CodeTaskNotFound, "%v", err)
+	}
+
+	status := protocol.TaskStatus{
+		ID:     taskID,
+		Name:   task.GetName(),
+		State:  string(state),
+		Status: string(task.GetStatus()),
+	}
+	if taskErr := task.GetError(); taskErr != nil {
+		status.Error = taskErr.Error()
+	}
+	if state != task_engine.TaskStateIdle {
+		status.Progress = task.GetProgress().String()
+	}
+	return status, nil
+}
+
+// existingLog returns the log of a known task, creating an empty one for
+// tasks registered without TaskLogger
+func (s *Server) existingLog(taskID string) (*taskLog, *protocol.Error) {
+	if _, err := s.manager.GetTask(taskID); err != nil {
+		return nil, protocol.Errorf(protocol.CodeTaskNotFound, "%v", err)
+	}
+	return s.taskLog(taskID), nil
+}
+
+// followTail answers a following Tail request with the buffered entries and
+// then streams new entries as notifications until the client disconnects or
+// the daemon closes
+func (s *Server) followTail(conn net.Conn, scanner *bufio.Scanner, encoder *json.Encoder, id uint64, params protocol.TailParams) {
+	log, rpcErr := s.existingLog(params.TaskID)
+	if rpcErr != nil {
+		s.reply(encoder, id, nil, rpcErr)
+		return
+	}
+	backlog, entries, stop := log.follow(params.Lines, followBuffer)
+	defer stop()
+	if !s.reply(encoder, id, protocol.TailResult{Entries: backlog}, nil) {
+		return
+	}
+
+	// The client ends the stream by closing its side of the connection
+	done := make(chan struct{})
+	go func() {
+		for scanner.Scan() {
+		}
+		close(done)
+	}()
+	defer conn.Close()
+
+	for {
+		select {
+		case <-done:
+			return
+		case entry := <-entries:
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			if err := encoder.Encode(protocol.Message{JSONRPC: protocol.Version, Method: protocol.MethodLog, Params: data}); err != nil {
+				return
+			}
+		}
+	}
+}
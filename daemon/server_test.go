@@ -0,0 +1,253 @@
+package daemon_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/daemon"
+	"github.com/ndizazzo/task-engine/daemon/client"
+	"github.com/ndizazzo/task-engine/daemon/protocol"
+	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// echoAction logs a message and optionally blocks until canceled
+type echoAction struct {
+	task_engine.BaseAction
+	message string
+	block   bool
+	fail    bool
+}
+
+func (a *echoAction) Execute(ctx context.Context) error {
+	a.Logger.Info(a.message)
+	if a.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if a.fail {
+		return errors.New("echo failed")
+	}
+	return nil
+}
+
+// echoFactory builds a one-action task from params {message, block, fail}
+func echoFactory(logger *slog.Logger, def protocol.TaskDefinition) (*task_engine.Task, error) {
+	message, _ := def.Params["message"].(string)
+	if message == "" {
+		return nil, errors.New("params.message is required")
+	}
+	block, _ := def.Params["block"].(bool)
+	fail, _ := def.Params["fail"].(bool)
+	action := task_engine.NewAction(&echoAction{
+		BaseAction: task_engine.NewBaseAction(logger),
+		message:    message,
+		block:      block,
+		fail:       fail,
+	}, "Echo", logger, "echo")
+	return &task_engine.Task{Actions: []task_engine.ActionWrapper{action}}, nil
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+	manager    *task_engine.TaskManager
+	server     *daemon.Server
+	socketPath string
+	client     *client.Client
+	cancel     context.CancelFunc
+	served     chan error
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (suite *ServerTestSuite) SetupTest() {
+	// Socket paths are limited to about 100 bytes, so avoid the long t.TempDir
+	dir, err := os.MkdirTemp("", "ted")
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { os.RemoveAll(dir) })
+	suite.socketPath = filepath.Join(dir, "engine.sock")
+
+	suite.manager = task_engine.NewTaskManager(mocks.NewDiscardLogger())
+	suite.server = daemon.NewServer(suite.manager, daemon.WithTaskFactory("echo", echoFactory))
+	suite.Require().NoError(suite.server.Listen(suite.socketPath))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.cancel = cancel
+	suite.served = make(chan error, 1)
+	go func() { suite.served <- suite.server.Serve(ctx) }()
+
+	suite.client, err = client.Dial(context.Background(), suite.socketPath)
+	suite.Require().NoError(err)
+}
+
+func (suite *ServerTestSuite) TearDownTest() {
+	suite.client.Close()
+	suite.manager.StopAllTasks()
+	suite.Require().NoError(suite.manager.WaitForAllTasksToComplete(time.Second))
+	suite.cancel()
+	suite.Require().NoError(<-suite.served)
+	_, err := os.Stat(suite.socketPath)
+	suite.True(os.IsNotExist(err), "socket should be removed on shutdown")
+}
+
+func (suite *ServerTestSuite) add(id string, params map[string]interface{}) protocol.TaskStatus {
+	status, err := suite.client.AddTask(context.Background(), protocol.TaskDefinition{ID: id, Type: "echo", Params: params}, false)
+	suite.Require().NoError(err)
+	return status
+}
+
+func (suite *ServerTestSuite) TestSocketPermissions() {
+	info, err := os.Stat(suite.socketPath)
+	suite.Require().NoError(err)
+	suite.Equal(daemon.DefaultSocketMode, info.Mode().Perm())
+}
+
+func (suite *ServerTestSuite) TestAddRunStatusAndTail() {
+	ctx := context.Background()
+	status := suite.add("hello", map[string]interface{}{"message": "hello from the daemon"})
+	suite.Equal("hello", status.ID)
+	suite.Equal("idle", status.State)
+
+	_, err := suite.client.RunTask(ctx, "hello", nil)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.manager.WaitForAllTasksToComplete(time.Second))
+
+	statuses, err := suite.client.Status(ctx, "hello")
+	suite.Require().NoError(err)
+	suite.Require().Len(statuses, 1)
+	suite.Equal("idle", statuses[0].State)
+	suite.Equal(string(task_engine.TaskStatusSuccess), statuses[0].Status)
+
+	entries, err := suite.client.Tail(ctx, "hello", 0)
+	suite.Require().NoError(err)
+	var lines []string
+	for _, entry := range entries {
+		suite.Equal("hello", entry.TaskID)
+		lines = append(lines, entry.Line)
+	}
+	joined := strings.Join(lines, "\n")
+	suite.Contains(joined, `msg="hello from the daemon"`)
+	suite.Contains(joined, "msg=task.started")
+	suite.Contains(joined, "msg=task.completed")
+
+	last, err := suite.client.Tail(ctx, "hello", 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(last, 1)
+	suite.Equal(entries[len(entries)-1], last[0])
+}
+
+func (suite *ServerTestSuite) TestFollowStreamsNewEntries() {
+	suite.add("stream", map[string]interface{}{"message": "streamed line"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var (
+		mu    sync.Mutex
+		lines []string
+	)
+	followed := make(chan error, 1)
+	go func() {
+		followed <- suite.client.Follow(ctx, "stream", 0, func(entry protocol.LogEntry) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines = append(lines, entry.Line)
+			if strings.Contains(entry.Line, "task.completed") {
+				cancel()
+			}
+		})
+	}()
+
+	// Entries written before the stream starts arrive in the backlog
+	_, err := suite.client.RunTask(context.Background(), "stream", nil)
+	suite.Require().NoError(err)
+
+	select {
+	case err := <-followed:
+		suite.NoError(err)
+	case <-time.After(2 * time.Second):
+		suite.Fail("follow did not see the run complete")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	suite.Contains(strings.Join(lines, "\n"), "streamed line")
+}
+
+func (suite *ServerTestSuite) TestErrors() {
+	ctx := context.Background()
+
+	_, err := suite.client.RunTask(ctx, "missing", nil)
+	suite.True(protocol.IsCode(err, protocol.CodeTaskNotFound), "got %v", err)
+
+	_, err = suite.client.AddTask(ctx, protocol.TaskDefinition{ID: "x", Type: "unknown"}, false)
+	suite.True(protocol.IsCode(err, protocol.CodeInvalidParams), "got %v", err)
+
+	_, err = suite.client.AddTask(ctx, protocol.TaskDefinition{ID: "x", Type: "echo"}, false)
+	suite.True(protocol.IsCode(err, protocol.CodeInvalidParams), "factory errors are reported: %v", err)
+
+	suite.add("busy", map[string]interface{}{"message": "waiting", "block": true})
+	_, err = suite.client.AddTask(ctx, protocol.TaskDefinition{ID: "busy", Type: "echo", Params: map[string]interface{}{"message": "again"}}, false)
+	suite.True(protocol.IsCode(err, protocol.CodeConflict), "got %v", err)
+
+	_, err = suite.client.RunTask(ctx, "busy", nil)
+	suite.Require().NoError(err)
+	_, err = suite.client.RunTask(ctx, "busy", nil)
+	suite.True(protocol.IsCode(err, protocol.CodeConflict), "got %v", err)
+	_, err = suite.client.AddTask(ctx, protocol.TaskDefinition{ID: "busy", Type: "echo", Params: map[string]interface{}{"message": "again"}}, true)
+	suite.True(protocol.IsCode(err, protocol.CodeConflict), "running tasks cannot be replaced: %v", err)
+
+	_, err = suite.client.StopTask(ctx, "busy")
+	suite.Require().NoError(err)
+	_, err = suite.client.StopTask(ctx, "busy")
+	suite.True(protocol.IsCode(err, protocol.CodeConflict), "got %v", err)
+}
+
+func (suite *ServerTestSuite) TestRefusesSocketInUse() {
+	other := daemon.NewServer(suite.manager)
+	err := other.Listen(suite.socketPath)
+	suite.ErrorContains(err, "already listening")
+}
+
+func (suite *ServerTestSuite) TestReplacesStaleSocket() {
+	dir, err := os.MkdirTemp("", "ted")
+	suite.Require().NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stale.sock")
+
+	listener, err := net.Listen("unix", path)
+	suite.Require().NoError(err)
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+
+	server := daemon.NewServer(suite.manager, daemon.WithSocketMode(0o660))
+	suite.Require().NoError(server.Listen(path))
+	info, err := os.Stat(path)
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0o660), info.Mode().Perm())
+	suite.NoError(server.Close())
+}
+
+func TestParseTaskDefinition(t *testing.T) {
+	def, err := protocol.ParseTaskDefinition([]byte("id: deploy\ntype: echo\nparams:\n  message: hi\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "deploy", def.ID)
+	assert.Equal(t, "hi", def.Params["message"])
+
+	_, err = protocol.ParseTaskDefinition([]byte("id: deploy\n"))
+	assert.ErrorContains(t, err, "has no type")
+
+	_, err = protocol.ParseTaskDefinition([]byte("id: deploy\ntype: echo\nextra: 1\n"))
+	assert.Error(t, err, "unknown fields are rejected")
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ndizazzo/task-engine/daemon/protocol"
+)
+
+// taskLog buffers the most recent log lines of one task and fans new lines
+// out to followers. It is the io.Writer behind a text slog.Handler, which
+// writes each record with a single Write call.
+type taskLog struct {
+	taskID string
+	limit  int
+	// events writes only to this log; used for lifecycle events the manager
+	// already logs elsewhere
+	events *slog.Logger
+
+	mu        sync.Mutex
+	entries   []protocol.LogEntry
+	nextID    int
+	followers map[int]chan protocol.LogEntry
+}
+
+func newTaskLog(taskID string, limit int) *taskLog {
+	l := &taskLog{taskID: taskID, limit: limit, followers: make(map[int]chan protocol.LogEntry)}
+	l.events = slog.New(l.handler())
+	return l
+}
+
+// handler formats records as logfmt lines into the log; the time is kept in
+// the entry rather than the line
+func (l *taskLog) handler() slog.Handler {
+	return slog.NewTextHandler(l, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+func (l *taskLog) Write(p []byte) (int, error) {
+	entry := protocol.LogEntry{TaskID: l.taskID, Time: time.Now(), Line: strings.TrimRight(string(p), "\n")}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > l.limit {
+		l.entries = l.entries[len(l.entries)-l.limit:]
+	}
+	for _, ch := range l.followers {
+		select {
+		case ch <- entry:
+		default:
+			// Slow follower: drop rather than block the task
+		}
+	}
+	return len(p), nil
+}
+
+// tail returns up to the last n entries; n <= 0 returns all of them
+func (l *taskLog) tail(n int) []protocol.LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tailLocked(n)
+}
+
+func (l *taskLog) tailLocked(n int) []protocol.LogEntry {
+	entries := l.entries
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return append([]protocol.LogEntry{}, entries...)
+}
+
+// follow returns the last n entries and a channel receiving every entry
+// written afterwards, with no gap between the two
+func (l *taskLog) follow(n, buffer int) ([]protocol.LogEntry, <-chan protocol.LogEntry, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := l.nextID
+	l.nextID++
+	ch := make(chan protocol.LogEntry, buffer)
+	l.followers[id] = ch
+
+	var once sync.Once
+	return l.tailLocked(n), ch, func() {
+		once.Do(func() {
+			l.mu.Lock()
+			delete(l.followers, id)
+			l.mu.Unlock()
+		})
+	}
+}
+
+// teeHandler sends each record to every handler that accepts its level
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
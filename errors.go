@@ -0,0 +1,212 @@
+package task_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTaskNotFound is returned by TaskManager methods given an unknown task ID
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrTaskRunning is returned when a task is started while a run of the same
+// Task is still in progress
+var ErrTaskRunning = errors.New("task is already running")
+
+// ErrorKind classifies a failure so callers can decide whether to retry,
+// alert or give up
+type ErrorKind string
+
+const (
+	// ErrorKindTransient failures may succeed when retried
+	ErrorKindTransient ErrorKind = "transient"
+	// ErrorKindPermanent failures will fail again until something changes (default)
+	ErrorKindPermanent ErrorKind = "permanent"
+	// ErrorKindPrecondition failures come from an unmet prerequisite, see ErrPrerequisiteNotMet
+	ErrorKindPrecondition ErrorKind = "precondition"
+	// ErrorKindTimeout failures hit a deadline or lock timeout
+	ErrorKindTimeout ErrorKind = "timeout"
+	// ErrorKindCanceled failures were caused by the run being canceled
+	ErrorKindCanceled ErrorKind = "canceled"
+	// ErrorKindPolicy failures were refused by a policy, e.g. a denied command
+	ErrorKindPolicy ErrorKind = "policy"
+)
+
+// Retryable reports whether failures of this kind may succeed when retried
+func (k ErrorKind) Retryable() bool {
+	return k == ErrorKindTransient || k == ErrorKindTimeout
+}
+
+// ActionPhase names the step of an action's lifecycle that failed
+type ActionPhase string
+
+const (
+	ActionPhaseBeforeExecute ActionPhase = "BeforeExecute"
+	ActionPhaseCheck         ActionPhase = "Check"
+	ActionPhaseExecute       ActionPhase = "Execute"
+	ActionPhaseAfterExecute  ActionPhase = "AfterExecute"
+)
+
+// KindedError is implemented by errors that know their own ErrorKind. An
+// empty kind leaves the classification to ClassifyError.
+type KindedError interface {
+	error
+	ErrorKind() ErrorKind
+}
+
+// CommandFailure is implemented by errors from commands that ran but did not
+// succeed, such as *command.CommandError
+type CommandFailure interface {
+	error
+	ExitCode() int
+	Stderr() string
+}
+
+// ActionError describes an action that failed during a task run. Tasks wrap
+// the action's error in one, so Action.Execute itself still returns the
+// action's own error. Its message is that of Err; the TaskError around it
+// names the task and action.
+type ActionError struct {
+	TaskID   string
+	ActionID string
+	RunID    string
+	// Phase is empty when the action wrapper has no FailedPhase method
+	Phase ActionPhase
+	Kind  ErrorKind
+	// ExitCode and Stderr describe a failed command; ExitCode is zero when
+	// the failure was not a command exiting unsuccessfully
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+func (e *ActionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// TaskError is returned by Task.Run and RunWithContext when a run fails. When
+// an action caused the failure, ActionID is set and Err is its *ActionError.
+type TaskError struct {
+	TaskID   string
+	RunID    string
+	ActionID string
+	Kind     ErrorKind
+	Err      error
+}
+
+func (e *TaskError) Error() string {
+	switch {
+	case e.ActionID != "" && e.Kind == ErrorKindPrecondition:
+		return fmt.Sprintf("task %s (run %s) aborted: prerequisite not met in action %s: %v", e.TaskID, e.RunID, e.ActionID, e.Err)
+	case e.ActionID != "":
+		return fmt.Sprintf("task %s (run %s) failed at action %s: %v", e.TaskID, e.RunID, e.ActionID, e.Err)
+	default:
+		return fmt.Sprintf("task %s (run %s): %v", e.TaskID, e.RunID, e.Err)
+	}
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// ActionPathError reports a failure together with the full path of nested
+// tasks and the action that failed, e.g. "deploy/docker-setup/pull-images".
+type ActionPathError struct {
+	Path []string
+	Err  error
+}
+
+func (e *ActionPathError) Error() string {
+	return fmt.Sprintf("action %s failed: %v", strings.Join(e.Path, "/"), e.Err)
+}
+
+func (e *ActionPathError) Unwrap() error {
+	return e.Err
+}
+
+// kindedError attaches an ErrorKind to an error, see WithErrorKind
+type kindedError struct {
+	err  error
+	kind ErrorKind
+}
+
+func (e *kindedError) Error() string        { return e.err.Error() }
+func (e *kindedError) Unwrap() error        { return e.err }
+func (e *kindedError) ErrorKind() ErrorKind { return e.kind }
+
+// WithErrorKind marks err as being of the given kind, e.g. so an action can
+// report a failure as transient. It returns nil when err is nil.
+func WithErrorKind(err error, kind ErrorKind) error {
+	if err == nil {
+		return nil
+	}
+	return &kindedError{err: err, kind: kind}
+}
+
+// ClassifyError returns the kind of err. Engine errors report their own Kind;
+// otherwise the first KindedError in the chain decides, then well-known
+// errors such as context.Canceled, ErrLockTimeout and ErrPrerequisiteNotMet.
+// Anything else is permanent.
+func ClassifyError(err error) ErrorKind {
+	return classifyError(context.Background(), err)
+}
+
+// classifyError is ClassifyError for a failure that happened while ctx was
+// in use; an unclassified error is attributed to ctx ending, if it has
+func classifyError(ctx context.Context, err error) ErrorKind {
+	var actionErr *ActionError
+	var taskErr *TaskError
+	var kinded KindedError
+	var timeout interface{ Timeout() bool }
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &actionErr) && actionErr.Kind != "":
+		return actionErr.Kind
+	case errors.As(err, &taskErr) && taskErr.Kind != "":
+		return taskErr.Kind
+	case errors.As(err, &kinded) && kinded.ErrorKind() != "":
+		return kinded.ErrorKind()
+	case errors.Is(err, ErrPrerequisiteNotMet):
+		return ErrorKindPrecondition
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrLockTimeout):
+		return ErrorKindTimeout
+	case errors.As(err, &timeout) && timeout.Timeout():
+		return ErrorKindTimeout
+	case errors.Is(ctx.Err(), context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return ErrorKindTimeout
+	}
+	return ErrorKindPermanent
+}
+
+// newActionError wraps the error action returned while running with ctx
+func newActionError(ctx context.Context, action ActionWrapper, err error) *ActionError {
+	actionErr := &ActionError{
+		ActionID: action.GetID(),
+		Kind:     classifyError(ctx, err),
+		Err:      err,
+	}
+	if info, ok := ExecutionFromContext(ctx); ok {
+		actionErr.TaskID, actionErr.RunID = info.TaskID, info.RunID
+	}
+	if p, ok := action.(interface{ FailedPhase() ActionPhase }); ok {
+		actionErr.Phase = p.FailedPhase()
+	}
+	var failure CommandFailure
+	var exitCoder interface{ ExitCode() int }
+	if errors.As(err, &failure) {
+		actionErr.ExitCode, actionErr.Stderr = failure.ExitCode(), failure.Stderr()
+	} else if errors.As(err, &exitCoder) {
+		actionErr.ExitCode = exitCoder.ExitCode()
+	}
+	return actionErr
+}
@@ -0,0 +1,113 @@
+package task_engine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commandFailure stands in for a *command.CommandError
+type commandFailure struct{}
+
+func (commandFailure) Error() string  { return "exit status 2" }
+func (commandFailure) ExitCode() int  { return 2 }
+func (commandFailure) Stderr() string { return "no such image" }
+
+// afterExecuteFailure fails after its work is done
+type afterExecuteFailure struct {
+	engine.BaseAction
+}
+
+func (a *afterExecuteFailure) Execute(ctx context.Context) error { return nil }
+func (a *afterExecuteFailure) AfterExecute(ctx context.Context) error {
+	return errors.New("verification failed")
+}
+
+func TestTaskError_CarriesActionFailure(t *testing.T) {
+	logger := mocks.NewDiscardLogger()
+	task := &engine.Task{
+		ID:     "pull",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			newMockAction(logger, "pull-image", fmt.Errorf("docker pull: %w", commandFailure{}), nil),
+		},
+	}
+
+	err := task.Run(context.Background())
+
+	var taskErr *engine.TaskError
+	require.ErrorAs(t, err, &taskErr)
+	assert.Equal(t, "pull", taskErr.TaskID)
+	assert.Equal(t, task.RunID, taskErr.RunID)
+	assert.Equal(t, "pull-image", taskErr.ActionID)
+	assert.Equal(t, engine.ErrorKindPermanent, taskErr.Kind)
+	assert.Contains(t, err.Error(), "failed at action pull-image: docker pull: exit status 2")
+
+	var actionErr *engine.ActionError
+	require.ErrorAs(t, err, &actionErr)
+	assert.Equal(t, "pull", actionErr.TaskID)
+	assert.Equal(t, task.RunID, actionErr.RunID)
+	assert.Equal(t, engine.ActionPhaseExecute, actionErr.Phase)
+	assert.Equal(t, 2, actionErr.ExitCode)
+	assert.Equal(t, "no such image", actionErr.Stderr)
+	assert.ErrorAs(t, err, new(commandFailure))
+}
+
+func TestTaskError_Phases(t *testing.T) {
+	task := &engine.Task{
+		ID:     "verify",
+		Logger: mocks.NewDiscardLogger(),
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*afterExecuteFailure]{ID: "verify", Wrapped: &afterExecuteFailure{}},
+		},
+	}
+
+	var actionErr *engine.ActionError
+	require.ErrorAs(t, task.Run(context.Background()), &actionErr)
+	assert.Equal(t, engine.ActionPhaseAfterExecute, actionErr.Phase)
+	assert.Zero(t, actionErr.ExitCode)
+}
+
+func TestTaskError_Kinds(t *testing.T) {
+	logger := mocks.NewDiscardLogger()
+	run := func(ctx context.Context, actionErr error, delay time.Duration) *engine.TaskError {
+		action := newMockAction(logger, "step", actionErr, nil).(*engine.Action[*mockAction])
+		action.Wrapped.ExecuteDelay = delay
+		task := &engine.Task{ID: "kinds", Logger: logger, Actions: []engine.ActionWrapper{action}}
+		var taskErr *engine.TaskError
+		require.ErrorAs(t, task.Run(ctx), &taskErr)
+		return taskErr
+	}
+
+	assert.Equal(t, engine.ErrorKindTransient, run(context.Background(), engine.WithErrorKind(errors.New("registry busy"), engine.ErrorKindTransient), 0).Kind)
+	assert.Equal(t, engine.ErrorKindPrecondition, run(context.Background(), fmt.Errorf("disk full: %w", engine.ErrPrerequisiteNotMet), 0).Kind)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	timedOut := run(ctx, nil, time.Second)
+	assert.Equal(t, engine.ErrorKindTimeout, timedOut.Kind)
+	assert.True(t, timedOut.Kind.Retryable())
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	taskErr := run(canceled, nil, 0)
+	assert.Equal(t, engine.ErrorKindCanceled, taskErr.Kind)
+	assert.Empty(t, taskErr.ActionID, "the run was canceled before its first action")
+	assert.False(t, taskErr.Kind.Retryable())
+}
+
+func TestClassifyError(t *testing.T) {
+	assert.Equal(t, engine.ErrorKind(""), engine.ClassifyError(nil))
+	assert.Equal(t, engine.ErrorKindPermanent, engine.ClassifyError(errors.New("boom")))
+	assert.Equal(t, engine.ErrorKindTimeout, engine.ClassifyError(fmt.Errorf("locks: %w", engine.ErrLockTimeout)))
+	assert.Equal(t, engine.ErrorKindCanceled, engine.ClassifyError(context.Canceled))
+	assert.Equal(t, engine.ErrorKindPolicy, engine.ClassifyError(engine.WithErrorKind(errors.New("denied"), engine.ErrorKindPolicy)))
+	assert.NoError(t, engine.WithErrorKind(nil, engine.ErrorKindTransient))
+}
@@ -0,0 +1,86 @@
+package task_engine
+
+import (
+	"sync"
+	"time"
+)
+
+// TaskEventType identifies a lifecycle event emitted while a task runs
+type TaskEventType string
+
+const (
+	EventTaskStarted   TaskEventType = "task.started"
+	EventTaskCompleted TaskEventType = "task.completed"
+	EventTaskFailed    TaskEventType = "task.failed"
+	EventTaskCanceled  TaskEventType = "task.canceled"
+	EventTaskPaused    TaskEventType = "task.paused"
+	EventTaskResumed   TaskEventType = "task.resumed"
+	// EventTaskStopped is published by a TaskManager once a task stopped with
+	// StopTask has exited; Data["killed"] reports whether its grace period ran out
+	EventTaskStopped     TaskEventType = "task.stopped"
+	EventActionStarted   TaskEventType = "action.started"
+	EventActionCompleted TaskEventType = "action.completed"
+	EventActionFailed    TaskEventType = "action.failed"
+	EventActionProgress  TaskEventType = "action.progress"
+)
+
+// TaskEvent describes something that happened during a task run.
+// Duration holds the action duration for action events, the total run time for
+// task completion events, and the time spent paused for EventTaskResumed.
+type TaskEvent struct {
+	Type     TaskEventType          `json:"type"`
+	TaskID   string                 `json:"taskID"`
+	RunID    string                 `json:"runID"`
+	ActionID string                 `json:"actionID,omitempty"`
+	Time     time.Time              `json:"time"`
+	Duration time.Duration          `json:"duration,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// TaskEventListener receives task events. Listeners are called synchronously
+// from the task's goroutine and should return quickly.
+type TaskEventListener func(event TaskEvent)
+
+// eventBus fans events out to a dynamic set of listeners
+type eventBus struct {
+	mu        sync.RWMutex
+	nextID    int
+	listeners map[int]TaskEventListener
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{listeners: make(map[int]TaskEventListener)}
+}
+
+// subscribe registers a listener and returns a function that removes it
+func (b *eventBus) subscribe(listener TaskEventListener) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.listeners[id] = listener
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.listeners, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// publish delivers the event to every registered listener
+func (b *eventBus) publish(event TaskEvent) {
+	b.mu.RLock()
+	listeners := make([]TaskEventListener, 0, len(b.listeners))
+	for _, l := range b.listeners {
+		listeners = append(listeners, l)
+	}
+	b.mu.RUnlock()
+
+	for _, l := range listeners {
+		l(event)
+	}
+}
@@ -0,0 +1,88 @@
+package task_engine
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHistoryLimit is how many runs per task a TaskManager remembers
+const DefaultHistoryLimit = 20
+
+// RunStatusRunning and RunStatusCanceled complement the TaskStatus values
+// (success, degraded, failed) reported in RunRecord.Status
+const (
+	RunStatusRunning  = "running"
+	RunStatusCanceled = "canceled"
+)
+
+// RunRecord summarizes one run of a managed task
+type RunRecord struct {
+	TaskID     string        `json:"taskID"`
+	RunID      string        `json:"runID"`
+	Status     string        `json:"status"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt time.Time     `json:"finishedAt,omitempty"`
+	Duration   time.Duration `json:"duration,omitempty"`
+}
+
+// runHistory keeps the most recent runs of each task, built from task events
+type runHistory struct {
+	mu   sync.Mutex
+	runs map[string][]RunRecord
+}
+
+func newRunHistory() *runHistory {
+	return &runHistory{runs: make(map[string][]RunRecord)}
+}
+
+// record updates the history from a task lifecycle event
+func (h *runHistory) record(event TaskEvent, limit int) {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	runs := h.runs[event.TaskID]
+	switch event.Type {
+	case EventTaskStarted:
+		runs = append(runs, RunRecord{TaskID: event.TaskID, RunID: event.RunID, Status: RunStatusRunning, StartedAt: event.Time})
+		if len(runs) > limit {
+			runs = append([]RunRecord(nil), runs[len(runs)-limit:]...)
+		}
+	case EventTaskCompleted, EventTaskFailed, EventTaskCanceled:
+		i := len(runs) - 1
+		if i < 0 || runs[i].RunID != event.RunID {
+			// The run ended before it started, e.g. its locks timed out
+			runs = append(runs, RunRecord{TaskID: event.TaskID, RunID: event.RunID, StartedAt: event.Time})
+			i = len(runs) - 1
+		}
+		run := &runs[i]
+		run.FinishedAt = event.Time
+		run.Duration = run.FinishedAt.Sub(run.StartedAt)
+		run.Error = event.Error
+		switch event.Type {
+		case EventTaskCompleted:
+			run.Status = string(TaskStatusSuccess)
+			if status, ok := event.Data["status"].(string); ok {
+				run.Status = status
+			}
+		case EventTaskFailed:
+			run.Status = string(TaskStatusFailed)
+		default:
+			run.Status = RunStatusCanceled
+		}
+	default:
+		return
+	}
+	h.runs[event.TaskID] = runs
+}
+
+// get returns a copy of a task's runs, oldest first
+func (h *runHistory) get(taskID string) []RunRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]RunRecord{}, h.runs[taskID]...)
+}
@@ -0,0 +1,42 @@
+package httpapi
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// TokenValidator reports whether a bearer token is allowed to use the API
+type TokenValidator func(token string) bool
+
+// BearerTokenAuth returns middleware that requires an "Authorization: Bearer
+// <token>" header accepted by validate. Other requests receive 401 Unauthorized.
+func BearerTokenAuth(validate TokenValidator) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || token == "" || !validate(token) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="task-engine"`)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"unauthorized"}` + "\n"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// StaticTokens returns a TokenValidator accepting any of the given tokens,
+// compared in constant time
+func StaticTokens(tokens ...string) TokenValidator {
+	return func(token string) bool {
+		ok := false
+		for _, t := range tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				ok = true
+			}
+		}
+		return ok
+	}
+}
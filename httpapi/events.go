@@ -0,0 +1,75 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+)
+
+// streamEvents writes task events as Server-Sent Events until the client
+// disconnects. Each event's SSE type is the TaskEventType and its data is the
+// JSON-encoded TaskEvent. The optional "task" query parameter filters by task ID.
+// Events are dropped for clients that fall too far behind.
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	source, ok := h.manager.(EventSource)
+	if !ok {
+		h.notImplemented(w, "event streaming")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported by this connection"))
+		return
+	}
+
+	taskID := r.URL.Query().Get("task")
+	events := make(chan task_engine.TaskEvent, h.eventBuffer)
+	unsubscribe := source.Subscribe(func(event task_engine.TaskEvent) {
+		if taskID != "" && event.TaskID != taskID {
+			return
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var heartbeat <-chan time.Time
+	if h.heartbeat > 0 {
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Error("Failed to encode event", "type", event.Type, "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,415 @@
+// Package httpapi exposes a TaskManager over HTTP: a small REST API for
+// listing, running and stopping tasks and reading their outputs, plus a
+// Server-Sent Events stream of task lifecycle events.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+)
+
+// DefaultHeartbeatInterval is how often an idle event stream sends a keep-alive comment
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// TaskLister is implemented by managers that can enumerate their tasks
+type TaskLister interface {
+	ListTasks() []string
+}
+
+// TaskGetter is implemented by managers that expose their tasks
+type TaskGetter interface {
+	GetTask(taskID string) (*task_engine.Task, error)
+}
+
+// RunHistoryProvider is implemented by managers that remember past runs
+type RunHistoryProvider interface {
+	GetRunHistory(taskID string) ([]task_engine.RunRecord, error)
+}
+
+// EventSource is implemented by managers that publish task lifecycle events
+type EventSource interface {
+	Subscribe(listener task_engine.TaskEventListener) (unsubscribe func())
+}
+
+// Middleware wraps the API handler, e.g. to authenticate requests
+type Middleware func(http.Handler) http.Handler
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithMiddleware wraps every route with the given middleware; the first one
+// listed is outermost
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(h *Handler) {
+		h.middleware = append(h.middleware, middleware...)
+	}
+}
+
+// WithLogger sets the logger used for request errors
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithHeartbeatInterval sets how often idle event streams send a keep-alive comment
+func WithHeartbeatInterval(interval time.Duration) Option {
+	return func(h *Handler) {
+		h.heartbeat = interval
+	}
+}
+
+// WithEventBuffer sets how many events a slow event stream client may lag
+// behind before further events are dropped for it
+func WithEventBuffer(size int) Option {
+	return func(h *Handler) {
+		h.eventBuffer = size
+	}
+}
+
+// Handler serves the control API for a TaskManagerInterface. Routes that
+// need more than the interface (listing, run inputs, pausing, history,
+// outputs, events) answer 501 Not Implemented unless the manager also implements
+// TaskLister, TaskGetter, task_engine.TaskInputRunner,
+// task_engine.TaskPauser, RunHistoryProvider or EventSource. Without
+// task_engine.TaskStateReporter states are only running or idle, and without
+// task_engine.TaskProgressReporter tasks have no progress.
+// *task_engine.TaskManager implements all of them.
+//
+//	GET  /tasks                                  list tasks with their state
+//	GET  /tasks/{id}                             task status and progress
+//	POST /tasks/{id}/run                         start a run, body {"inputs": {...}}
+//	POST /tasks/{id}/stop                        stop a running task
+//	POST /tasks/{id}/pause                       pause a running task
+//	POST /tasks/{id}/resume                      resume a paused task
+//	POST /tasks/stop-all                         stop every running task
+//	GET  /tasks/{id}/history                     recent runs
+//	GET  /tasks/{id}/outputs                     task and action outputs
+//	GET  /tasks/{id}/actions/{actionID}/output   one action's output
+//	GET  /events[?task=id]                       Server-Sent Events stream
+type Handler struct {
+	manager     task_engine.TaskManagerInterface
+	logger      *slog.Logger
+	middleware  []Middleware
+	heartbeat   time.Duration
+	eventBuffer int
+	handler     http.Handler
+}
+
+// NewHandler creates a Handler for the manager
+func NewHandler(manager task_engine.TaskManagerInterface, opts ...Option) *Handler {
+	h := &Handler{
+		manager:     manager,
+		logger:      slog.New(slog.DiscardHandler),
+		heartbeat:   DefaultHeartbeatInterval,
+		eventBuffer: 64,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", h.listTasks)
+	mux.HandleFunc("POST /tasks/stop-all", h.stopAll)
+	mux.HandleFunc("GET /tasks/{id}", h.getTask)
+	mux.HandleFunc("POST /tasks/{id}/run", h.runTask)
+	mux.HandleFunc("POST /tasks/{id}/stop", h.stopTask)
+	mux.HandleFunc("POST /tasks/{id}/pause", h.pauseTask)
+	mux.HandleFunc("POST /tasks/{id}/resume", h.resumeTask)
+	mux.HandleFunc("GET /tasks/{id}/history", h.getHistory)
+	mux.HandleFunc("GET /tasks/{id}/outputs", h.getOutputs)
+	mux.HandleFunc("GET /tasks/{id}/actions/{actionID}/output", h.getActionOutput)
+	mux.HandleFunc("GET /events", h.streamEvents)
+
+	var handler http.Handler = mux
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		handler = h.middleware[i](handler)
+	}
+	h.handler = handler
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// TaskStatus is the JSON body describing a task
+type TaskStatus struct {
+	ID       string                    `json:"id"`
+	Name     string                    `json:"name,omitempty"`
+	State    task_engine.TaskState     `json:"state"`
+	Status   task_engine.TaskStatus    `json:"status,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+	Progress *task_engine.TaskProgress `json:"progress,omitempty"`
+}
+
+// RunRequest is the optional JSON body of a run request
+type RunRequest struct {
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+}
+
+// ErrorResponse is the JSON body of every error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) listTasks(w http.ResponseWriter, r *http.Request) {
+	lister, ok := h.manager.(TaskLister)
+	if !ok {
+		h.notImplemented(w, "listing tasks")
+		return
+	}
+
+	tasks := []TaskStatus{}
+	for _, id := range lister.ListTasks() {
+		status, err := h.status(id, false)
+		if err != nil {
+			// Removed while listing
+			continue
+		}
+		tasks = append(tasks, status)
+	}
+	h.writeJSON(w, http.StatusOK, tasks)
+}
+
+func (h *Handler) getTask(w http.ResponseWriter, r *http.Request) {
+	status, err := h.status(r.PathValue("id"), true)
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, status)
+}
+
+// status describes a task; it fails only when the task is unknown
+func (h *Handler) status(taskID string, withProgress bool) (TaskStatus, error) {
+	state, err := h.state(taskID)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	status := TaskStatus{ID: taskID, State: state}
+
+	if getter, ok := h.manager.(TaskGetter); ok {
+		if task, err := getter.GetTask(taskID); err == nil {
+			status.Name = task.GetName()
+			status.Status = task.GetStatus()
+			if taskErr := task.GetError(); taskErr != nil {
+				status.Error = taskErr.Error()
+			}
+		}
+	}
+	if reporter, ok := h.manager.(task_engine.TaskProgressReporter); ok && withProgress {
+		if progress, err := reporter.GetTaskProgress(taskID); err == nil {
+			status.Progress = &progress
+		}
+	}
+	return status, nil
+}
+
+func (h *Handler) runTask(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	if !h.exists(w, taskID) {
+		return
+	}
+	if h.manager.IsTaskRunning(taskID) {
+		h.writeError(w, http.StatusConflict, errors.New("task is already running"))
+		return
+	}
+	var body RunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+	}
+	run := h.manager.RunTask
+	if runner, ok := h.manager.(task_engine.TaskInputRunner); ok {
+		run = func(taskID string) error { return runner.RunTaskWithInputs(taskID, body.Inputs) }
+	} else if len(body.Inputs) > 0 {
+		h.notImplemented(w, "run inputs")
+		return
+	}
+	if err := run(taskID); err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, task_engine.ErrShuttingDown):
+			status = http.StatusServiceUnavailable
+		case errors.Is(err, task_engine.ErrTaskRunning):
+			status = http.StatusConflict
+		case errors.Is(err, task_engine.ErrTaskNotFound):
+			status = http.StatusNotFound
+		}
+		h.writeError(w, status, err)
+		return
+	}
+	h.writeJSON(w, http.StatusAccepted, TaskStatus{ID: taskID, State: task_engine.TaskStateRunning})
+}
+
+func (h *Handler) stopTask(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, h.manager.StopTask)
+}
+
+func (h *Handler) pauseTask(w http.ResponseWriter, r *http.Request) {
+	pauser, ok := h.manager.(task_engine.TaskPauser)
+	if !ok {
+		h.notImplemented(w, "pausing tasks")
+		return
+	}
+	h.control(w, r, pauser.PauseTask)
+}
+
+func (h *Handler) resumeTask(w http.ResponseWriter, r *http.Request) {
+	pauser, ok := h.manager.(task_engine.TaskPauser)
+	if !ok {
+		h.notImplemented(w, "resuming tasks")
+		return
+	}
+	h.control(w, r, pauser.ResumeTask)
+}
+
+// control applies op to a running task and responds with its new state
+func (h *Handler) control(w http.ResponseWriter, r *http.Request, op func(taskID string) error) {
+	taskID := r.PathValue("id")
+	if !h.exists(w, taskID) {
+		return
+	}
+	if err := op(taskID); err != nil {
+		h.writeError(w, http.StatusConflict, err)
+		return
+	}
+	state, _ := h.state(taskID)
+	h.writeJSON(w, http.StatusAccepted, TaskStatus{ID: taskID, State: state})
+}
+
+func (h *Handler) stopAll(w http.ResponseWriter, r *http.Request) {
+	running := h.manager.GetRunningTasks()
+	h.manager.StopAllTasks()
+	h.writeJSON(w, http.StatusAccepted, map[string][]string{"stopped": running})
+}
+
+func (h *Handler) getHistory(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.manager.(RunHistoryProvider)
+	if !ok {
+		h.notImplemented(w, "run history")
+		return
+	}
+	runs, err := provider.GetRunHistory(r.PathValue("id"))
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, runs)
+}
+
+// TaskOutputs is the JSON body holding a task's output and its actions' outputs
+type TaskOutputs struct {
+	Task    interface{}            `json:"task"`
+	Actions map[string]interface{} `json:"actions"`
+}
+
+func (h *Handler) getOutputs(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	getter, ok := h.manager.(TaskGetter)
+	if !ok {
+		h.notImplemented(w, "task outputs")
+		return
+	}
+	task, err := getter.GetTask(taskID)
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	gc := h.manager.GetGlobalContext()
+	outputs := TaskOutputs{Actions: map[string]interface{}{}}
+	outputs.Task, _ = gc.GetTaskOutput(taskID)
+	for _, actions := range [][]task_engine.ActionWrapper{task.Actions, task.Finally} {
+		for _, action := range actions {
+			if output, ok := gc.GetActionOutput(action.GetID()); ok {
+				outputs.Actions[action.GetID()] = output
+			}
+		}
+	}
+	h.writeJSON(w, http.StatusOK, outputs)
+}
+
+func (h *Handler) getActionOutput(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	getter, ok := h.manager.(TaskGetter)
+	if !ok {
+		h.notImplemented(w, "action outputs")
+		return
+	}
+	task, err := getter.GetTask(taskID)
+	if err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return
+	}
+	// Only the task's own actions may be read through its URL
+	actionID := r.PathValue("actionID")
+	if !slices.ContainsFunc(slices.Concat(task.Actions, task.Finally), func(action task_engine.ActionWrapper) bool {
+		return action.GetID() == actionID
+	}) {
+		h.writeError(w, http.StatusNotFound, fmt.Errorf("task %s has no action %s", taskID, actionID))
+		return
+	}
+	output, ok := h.manager.GetGlobalContext().GetActionOutput(actionID)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, errors.New("no output recorded for action "+actionID))
+		return
+	}
+	h.writeJSON(w, http.StatusOK, output)
+}
+
+// state returns the task's state. Managers without TaskStateReporter only
+// tell running from idle, and unknown tasks apart only with TaskGetter.
+func (h *Handler) state(taskID string) (task_engine.TaskState, error) {
+	if reporter, ok := h.manager.(task_engine.TaskStateReporter); ok {
+		return reporter.GetTaskState(taskID)
+	}
+	if getter, ok := h.manager.(TaskGetter); ok {
+		if _, err := getter.GetTask(taskID); err != nil {
+			return "", err
+		}
+	}
+	if h.manager.IsTaskRunning(taskID) {
+		return task_engine.TaskStateRunning, nil
+	}
+	return task_engine.TaskStateIdle, nil
+}
+
+// exists responds with 404 and returns false when the task is unknown
+func (h *Handler) exists(w http.ResponseWriter, taskID string) bool {
+	if _, err := h.state(taskID); err != nil {
+		h.writeError(w, http.StatusNotFound, err)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) notImplemented(w http.ResponseWriter, feature string) {
+	h.writeError(w, http.StatusNotImplemented, errors.New(feature+" is not supported by this task manager"))
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		h.logger.Error("Request failed", "status", status, "error", err)
+	}
+	h.writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.Debug("Failed to write response", "error", err)
+	}
+}
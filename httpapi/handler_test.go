@@ -0,0 +1,312 @@
+package httpapi_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/httpapi"
+	"github.com/ndizazzo/task-engine/testing/mocks"
+	"github.com/stretchr/testify/suite"
+)
+
+// gateAction blocks until released (or canceled) and then outputs its ID
+type gateAction struct {
+	task_engine.BaseAction
+	id      string
+	release chan struct{}
+}
+
+func (a *gateAction) Execute(ctx context.Context) error {
+	if a.release == nil {
+		return nil
+	}
+	select {
+	case <-a.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *gateAction) GetOutput() interface{} {
+	return map[string]interface{}{"id": a.id}
+}
+
+type HandlerTestSuite struct {
+	suite.Suite
+	manager *task_engine.TaskManager
+	server  *httptest.Server
+	release chan struct{}
+}
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
+
+func (suite *HandlerTestSuite) SetupTest() {
+	logger := mocks.NewDiscardLogger()
+	suite.manager = task_engine.NewTaskManager(logger)
+	suite.release = make(chan struct{})
+
+	suite.Require().NoError(suite.manager.AddTask(&task_engine.Task{
+		ID:      "quick",
+		Name:    "Quick Task",
+		Actions: []task_engine.ActionWrapper{suite.action("step", nil)},
+		Logger:  logger,
+	}))
+	suite.Require().NoError(suite.manager.AddTask(&task_engine.Task{
+		ID:      "slow",
+		Name:    "Slow Task",
+		Actions: []task_engine.ActionWrapper{suite.action("wait", suite.release)},
+		Logger:  logger,
+	}))
+
+	suite.server = httptest.NewServer(httpapi.NewHandler(suite.manager,
+		httpapi.WithMiddleware(httpapi.BearerTokenAuth(httpapi.StaticTokens("secret"))),
+		httpapi.WithHeartbeatInterval(10*time.Millisecond),
+	))
+}
+
+func (suite *HandlerTestSuite) TearDownTest() {
+	suite.server.Close()
+	suite.manager.StopAllTasks()
+	suite.manager.WaitForAllTasksToComplete(time.Second)
+}
+
+func (suite *HandlerTestSuite) action(id string, release chan struct{}) task_engine.ActionWrapper {
+	logger := mocks.NewDiscardLogger()
+	return task_engine.NewAction(&gateAction{BaseAction: task_engine.NewBaseAction(logger), id: id, release: release}, id, logger, id)
+}
+
+func (suite *HandlerTestSuite) do(method, path, token string) *http.Response {
+	return suite.doBody(method, path, token, "")
+}
+
+func (suite *HandlerTestSuite) doBody(method, path, token, body string) *http.Response {
+	req, err := http.NewRequest(method, suite.server.URL+path, strings.NewReader(body))
+	suite.Require().NoError(err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *HandlerTestSuite) decode(resp *http.Response, wantStatus int, into interface{}) {
+	defer resp.Body.Close()
+	suite.Require().Equal(wantStatus, resp.StatusCode)
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(into))
+}
+
+func (suite *HandlerTestSuite) TestRejectsMissingOrWrongToken() {
+	for _, token := range []string{"", "wrong"} {
+		resp := suite.do(http.MethodGet, "/tasks", token)
+		resp.Body.Close()
+		suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+		suite.Contains(resp.Header.Get("WWW-Authenticate"), "Bearer")
+	}
+}
+
+func (suite *HandlerTestSuite) TestListTasks() {
+	var tasks []httpapi.TaskStatus
+	suite.decode(suite.do(http.MethodGet, "/tasks", "secret"), http.StatusOK, &tasks)
+
+	suite.Require().Len(tasks, 2)
+	suite.Equal("quick", tasks[0].ID)
+	suite.Equal("Quick Task", tasks[0].Name)
+	suite.Equal(task_engine.TaskStateIdle, tasks[0].State)
+	suite.Equal("slow", tasks[1].ID)
+}
+
+func (suite *HandlerTestSuite) TestRunTaskAndReadResults() {
+	var started httpapi.TaskStatus
+	suite.decode(suite.do(http.MethodPost, "/tasks/quick/run", "secret"), http.StatusAccepted, &started)
+	suite.Equal(task_engine.TaskStateRunning, started.State)
+	suite.manager.WaitForAllTasksToComplete(time.Second)
+
+	var status httpapi.TaskStatus
+	suite.decode(suite.do(http.MethodGet, "/tasks/quick", "secret"), http.StatusOK, &status)
+	suite.Equal(task_engine.TaskStatusSuccess, status.Status)
+	suite.Require().NotNil(status.Progress)
+
+	var history []task_engine.RunRecord
+	suite.decode(suite.do(http.MethodGet, "/tasks/quick/history", "secret"), http.StatusOK, &history)
+	suite.Require().Len(history, 1)
+	suite.Equal(string(task_engine.TaskStatusSuccess), history[0].Status)
+
+	var outputs httpapi.TaskOutputs
+	suite.decode(suite.do(http.MethodGet, "/tasks/quick/outputs", "secret"), http.StatusOK, &outputs)
+	suite.Equal(map[string]interface{}{"id": "step"}, outputs.Actions["step"])
+	suite.NotNil(outputs.Task)
+
+	var output map[string]interface{}
+	suite.decode(suite.do(http.MethodGet, "/tasks/quick/actions/step/output", "secret"), http.StatusOK, &output)
+	suite.Equal("step", output["id"])
+
+	var body httpapi.ErrorResponse
+	suite.decode(suite.do(http.MethodGet, "/tasks/slow/actions/step/output", "secret"), http.StatusNotFound, &body)
+	suite.Contains(body.Error, "task slow has no action step", "another task's outputs are not readable through this task")
+}
+
+func (suite *HandlerTestSuite) TestRunRejectsInvalidInputs() {
+	var body httpapi.ErrorResponse
+	suite.decode(suite.doBody(http.MethodPost, "/tasks/quick/run", "secret", `{"inputs": {"version": "1.3"}}`), http.StatusBadRequest, &body)
+	suite.Contains(body.Error, "no inputs named version")
+
+	suite.decode(suite.doBody(http.MethodPost, "/tasks/quick/run", "secret", `{"inputs":`), http.StatusBadRequest, &body)
+	suite.Contains(body.Error, "invalid request body")
+}
+
+func (suite *HandlerTestSuite) TestUnknownTaskIsNotFound() {
+	for _, req := range [][2]string{
+		{http.MethodGet, "/tasks/missing"},
+		{http.MethodPost, "/tasks/missing/run"},
+		{http.MethodPost, "/tasks/missing/stop"},
+		{http.MethodGet, "/tasks/missing/history"},
+		{http.MethodGet, "/tasks/missing/outputs"},
+		{http.MethodGet, "/tasks/missing/actions/step/output"},
+	} {
+		var body httpapi.ErrorResponse
+		suite.decode(suite.do(req[0], req[1], "secret"), http.StatusNotFound, &body)
+		suite.NotEmpty(body.Error, req[1])
+	}
+}
+
+func (suite *HandlerTestSuite) TestRunningTaskConflictsAndStops() {
+	resp := suite.do(http.MethodPost, "/tasks/slow/run", "secret")
+	resp.Body.Close()
+	suite.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	var body httpapi.ErrorResponse
+	suite.decode(suite.do(http.MethodPost, "/tasks/slow/run", "secret"), http.StatusConflict, &body)
+
+	var stopped map[string][]string
+	suite.decode(suite.do(http.MethodPost, "/tasks/stop-all", "secret"), http.StatusAccepted, &stopped)
+	suite.Equal([]string{"slow"}, stopped["stopped"])
+	suite.manager.WaitForAllTasksToComplete(time.Second)
+
+	suite.decode(suite.do(http.MethodPost, "/tasks/slow/stop", "secret"), http.StatusConflict, &body)
+
+	var history []task_engine.RunRecord
+	suite.decode(suite.do(http.MethodGet, "/tasks/slow/history", "secret"), http.StatusOK, &history)
+	suite.Require().Len(history, 1)
+	suite.Equal(task_engine.RunStatusCanceled, history[0].Status)
+}
+
+// racingManager fails every run as if another request got there first
+type racingManager struct {
+	*task_engine.TaskManager
+	err error
+}
+
+func (m racingManager) RunTaskWithInputs(taskID string, inputs map[string]interface{}) error {
+	return m.err
+}
+
+func (suite *HandlerTestSuite) TestRunErrorsMapToStatusCodes() {
+	for _, tt := range []struct {
+		err    error
+		status int
+	}{
+		{fmt.Errorf("task %q: %w", "quick", task_engine.ErrTaskRunning), http.StatusConflict},
+		{fmt.Errorf("task %q: %w", "quick", task_engine.ErrTaskNotFound), http.StatusNotFound},
+		{task_engine.ErrShuttingDown, http.StatusServiceUnavailable},
+		{errors.New("missing required input version"), http.StatusBadRequest},
+	} {
+		server := httptest.NewServer(httpapi.NewHandler(racingManager{suite.manager, tt.err}))
+		resp, err := http.Post(server.URL+"/tasks/quick/run", "application/json", nil)
+		suite.Require().NoError(err)
+		resp.Body.Close()
+		server.Close()
+		suite.Equal(tt.status, resp.StatusCode, tt.err.Error())
+	}
+}
+
+func (suite *HandlerTestSuite) TestEventStream() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, suite.server.URL+"/events?task=quick", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Events for other tasks are filtered out
+	suite.Require().NoError(suite.manager.RunTask("slow"))
+	suite.Require().NoError(suite.manager.RunTask("quick"))
+
+	var types []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && len(types) < 4 {
+		if eventType, ok := strings.CutPrefix(scanner.Text(), "event: "); ok {
+			types = append(types, eventType)
+			suite.Require().True(scanner.Scan())
+			var event task_engine.TaskEvent
+			suite.Require().NoError(json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data: ")), &event))
+			suite.Equal("quick", event.TaskID)
+			suite.Equal(eventType, string(event.Type))
+		}
+	}
+
+	suite.Equal([]string{
+		string(task_engine.EventTaskStarted),
+		string(task_engine.EventActionStarted),
+		string(task_engine.EventActionCompleted),
+		string(task_engine.EventTaskCompleted),
+	}, types)
+}
+
+// limitedManager implements only TaskManagerInterface
+type limitedManager struct {
+	task_engine.TaskManagerInterface
+}
+
+func (suite *HandlerTestSuite) TestOptionalFeaturesNotImplemented() {
+	server := httptest.NewServer(httpapi.NewHandler(limitedManager{suite.manager}))
+	defer server.Close()
+
+	for _, path := range []string{"/tasks", "/tasks/quick/history", "/events"} {
+		resp, err := http.Get(server.URL + path)
+		suite.Require().NoError(err)
+		resp.Body.Close()
+		suite.Equal(http.StatusNotImplemented, resp.StatusCode, path)
+	}
+}
+
+func (suite *HandlerTestSuite) TestCoreInterfaceOnly() {
+	suite.server.Close()
+	suite.server = httptest.NewServer(httpapi.NewHandler(limitedManager{suite.manager}))
+
+	for _, path := range []string{"/tasks/slow/pause", "/tasks/slow/resume"} {
+		resp := suite.do(http.MethodPost, path, "")
+		resp.Body.Close()
+		suite.Equal(http.StatusNotImplemented, resp.StatusCode, path)
+	}
+	resp := suite.doBody(http.MethodPost, "/tasks/slow/run", "", `{"inputs": {"version": "1.3"}}`)
+	resp.Body.Close()
+	suite.Equal(http.StatusNotImplemented, resp.StatusCode, "inputs need TaskInputRunner")
+
+	var status httpapi.TaskStatus
+	suite.decode(suite.do(http.MethodPost, "/tasks/slow/run", ""), http.StatusAccepted, &status)
+	suite.decode(suite.do(http.MethodGet, "/tasks/slow", ""), http.StatusOK, &status)
+	suite.Equal(task_engine.TaskStateRunning, status.State)
+	suite.Nil(status.Progress)
+
+	close(suite.release)
+	suite.Require().NoError(suite.manager.WaitForAllTasksToComplete(time.Second))
+	suite.decode(suite.do(http.MethodGet, "/tasks/slow", ""), http.StatusOK, &status)
+	suite.Equal(task_engine.TaskStateIdle, status.State)
+}
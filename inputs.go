@@ -0,0 +1,232 @@
+package task_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskInputsKey is the key used to store the current run's task inputs in the context
+const TaskInputsKey contextKey = "taskInputs"
+
+// InputType names the type a task input is converted to before the run starts
+type InputType string
+
+const (
+	InputAny         InputType = ""
+	InputString      InputType = "string"
+	InputInt         InputType = "int"
+	InputFloat       InputType = "float"
+	InputBool        InputType = "bool"
+	InputDuration    InputType = "duration"
+	InputStringSlice InputType = "[]string"
+)
+
+// TaskInput declares a named value supplied when a task is run, see
+// TaskManager.RunTaskWithInputs. Values are converted to Type: strings are
+// parsed (e.g. "3", "true", "5m", "a,b"), so inputs can come from JSON or a
+// command line.
+type TaskInput struct {
+	Name        string
+	Type        InputType
+	Description string
+	// Required inputs must be supplied; otherwise Default (if non-nil) is used
+	Required bool
+	Default  interface{}
+	// Validate optionally checks the converted value
+	Validate func(value interface{}) error
+}
+
+// WithTaskInputs returns a context carrying input values for the next task
+// run; Task.RunWithContext validates them against the task's declared Inputs
+func WithTaskInputs(ctx context.Context, inputs map[string]interface{}) context.Context {
+	return context.WithValue(ctx, TaskInputsKey, inputs)
+}
+
+// TaskInputsFromContext returns the task inputs of the current run, or nil
+func TaskInputsFromContext(ctx context.Context) map[string]interface{} {
+	inputs, _ := ctx.Value(TaskInputsKey).(map[string]interface{})
+	return inputs
+}
+
+// TaskInputParameter references an input of the running task
+type TaskInputParameter struct {
+	Name string // Required: name of the input
+}
+
+func (p TaskInputParameter) Resolve(ctx context.Context, globalContext *GlobalContext) (interface{}, error) {
+	if p.Name == "" {
+		return nil, fmt.Errorf("TaskInputParameter: Name cannot be empty")
+	}
+	value, exists := TaskInputsFromContext(ctx)[p.Name]
+	if !exists {
+		return nil, fmt.Errorf("TaskInputParameter: input '%s' not provided", p.Name)
+	}
+	return value, nil
+}
+
+// Input creates a parameter reference to a task input
+func Input(name string) TaskInputParameter {
+	return TaskInputParameter{Name: name}
+}
+
+// resolveInputs applies defaults, conversions and validation to the supplied
+// values. Only declared inputs are kept, so a sub-task receives just the
+// inputs of its parent that it declares itself.
+func (t *Task) resolveInputs(supplied map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(t.Inputs))
+
+	var errs []error
+	for _, input := range t.Inputs {
+		value, ok := supplied[input.Name]
+		if !ok || value == nil {
+			if input.Required {
+				errs = append(errs, fmt.Errorf("input %q is required", input.Name))
+				continue
+			}
+			if input.Default == nil {
+				continue
+			}
+			value = input.Default
+		}
+
+		converted, err := convertInput(input.Type, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("input %q: %w", input.Name, err))
+			continue
+		}
+		if input.Validate != nil {
+			if err := input.Validate(converted); err != nil {
+				errs = append(errs, fmt.Errorf("input %q: %w", input.Name, err))
+				continue
+			}
+		}
+		resolved[input.Name] = converted
+	}
+	return resolved, errors.Join(errs...)
+}
+
+// unknownInputs returns the supplied names the task does not declare, sorted
+func (t *Task) unknownInputs(supplied map[string]interface{}) []string {
+	declared := make(map[string]bool, len(t.Inputs))
+	for _, input := range t.Inputs {
+		declared[input.Name] = true
+	}
+	var unknown []string
+	for name := range supplied {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// convertInput converts a supplied value to the declared input type
+func convertInput(inputType InputType, value interface{}) (interface{}, error) {
+	switch inputType {
+	case InputAny:
+		return value, nil
+	case InputString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		case fmt.Stringer:
+			return v.String(), nil
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+			return fmt.Sprint(v), nil
+		}
+	case InputInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return strconv.Atoi(fmt.Sprint(v))
+		case float32:
+			return floatToInt(float64(v))
+		case float64:
+			return floatToInt(v)
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to int", v)
+			}
+			return n, nil
+		}
+	case InputFloat:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return strconv.ParseFloat(fmt.Sprint(v), 64)
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to float", v)
+			}
+			return f, nil
+		}
+	case InputBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to bool", v)
+			}
+			return b, nil
+		}
+	case InputDuration:
+		switch v := value.(type) {
+		case time.Duration:
+			return v, nil
+		case string:
+			d, err := time.ParseDuration(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to duration", v)
+			}
+			return d, nil
+		}
+	case InputStringSlice:
+		switch v := value.(type) {
+		case []string:
+			return v, nil
+		case []interface{}:
+			out := make([]string, len(v))
+			for i, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("element %d is %T, not a string", i, item)
+				}
+				out[i] = s
+			}
+			return out, nil
+		case string:
+			out := []string{}
+			for _, part := range strings.Split(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					out = append(out, part)
+				}
+			}
+			return out, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown input type %q", inputType)
+	}
+	return nil, fmt.Errorf("cannot convert %T to %s", value, inputType)
+}
+
+func floatToInt(f float64) (interface{}, error) {
+	if f != math.Trunc(f) || f > math.MaxInt || f < math.MinInt {
+		return nil, fmt.Errorf("%v is not an int", f)
+	}
+	return int(f), nil
+}
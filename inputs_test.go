@@ -0,0 +1,124 @@
+package task_engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inputReadingAction resolves its parameters when executed
+type inputReadingAction struct {
+	task_engine.BaseAction
+	Params   map[string]task_engine.ActionParameter
+	Resolved map[string]interface{}
+}
+
+func (a *inputReadingAction) Execute(ctx context.Context) error {
+	a.Resolved = map[string]interface{}{}
+	for name, p := range a.Params {
+		v, err := p.Resolve(ctx, nil)
+		if err != nil {
+			return err
+		}
+		a.Resolved[name] = v
+	}
+	return nil
+}
+
+func deployTask(action *inputReadingAction) *task_engine.Task {
+	return &task_engine.Task{
+		ID: "deploy",
+		Inputs: []task_engine.TaskInput{
+			{Name: "version", Type: task_engine.InputString, Required: true},
+			{Name: "replicas", Type: task_engine.InputInt, Default: 1, Validate: func(v interface{}) error {
+				if v.(int) < 1 {
+					return errors.New("must be at least 1")
+				}
+				return nil
+			}},
+			{Name: "timeout", Type: task_engine.InputDuration, Default: "30s"},
+			{Name: "hosts", Type: task_engine.InputStringSlice},
+		},
+		Actions: []task_engine.ActionWrapper{
+			&task_engine.Action[*inputReadingAction]{ID: "read", Wrapped: action},
+		},
+		Logger: NewDiscardLogger(),
+	}
+}
+
+func TestTaskInputs_DefaultsAndConversion(t *testing.T) {
+	action := &inputReadingAction{Params: map[string]task_engine.ActionParameter{
+		"version":  task_engine.Input("version"),
+		"replicas": task_engine.Input("replicas"),
+		"timeout":  task_engine.Input("timeout"),
+	}}
+	task := deployTask(action)
+
+	ctx := task_engine.WithTaskInputs(context.Background(), map[string]interface{}{"version": "1.3"})
+	require.NoError(t, task.Run(ctx))
+	assert.Equal(t, map[string]interface{}{"version": "1.3", "replicas": 1, "timeout": 30 * time.Second}, action.Resolved)
+
+	// JSON numbers and command-line strings are converted
+	ctx = task_engine.WithTaskInputs(context.Background(), map[string]interface{}{"version": 2, "replicas": float64(3), "timeout": "1m"})
+	require.NoError(t, task.Run(ctx))
+	assert.Equal(t, map[string]interface{}{"version": "2", "replicas": 3, "timeout": time.Minute}, action.Resolved)
+}
+
+func TestTaskInputs_UnsetOptionalInputIsNotProvided(t *testing.T) {
+	action := &inputReadingAction{Params: map[string]task_engine.ActionParameter{"hosts": task_engine.Input("hosts")}}
+	task := deployTask(action)
+
+	err := task.Run(task_engine.WithTaskInputs(context.Background(), map[string]interface{}{"version": "1"}))
+	assert.ErrorContains(t, err, "input 'hosts' not provided")
+}
+
+func TestTaskInputs_UndeclaredInputsAreNotForwarded(t *testing.T) {
+	// a sub-task runs with its parent's inputs in the context
+	action := &inputReadingAction{Params: map[string]task_engine.ActionParameter{"token": task_engine.Input("token")}}
+	task := deployTask(action)
+
+	err := task.Run(task_engine.WithTaskInputs(context.Background(), map[string]interface{}{"version": "1", "token": "secret"}))
+	assert.ErrorContains(t, err, "input 'token' not provided")
+}
+
+func TestTaskInputs_InvalidInputsFailBeforeActions(t *testing.T) {
+	for name, inputs := range map[string]map[string]interface{}{
+		"missing required": {},
+		"wrong type":       {"version": "1", "replicas": "many"},
+		"validation":       {"version": "1", "replicas": 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			action := &inputReadingAction{}
+			task := deployTask(action)
+			var events []task_engine.TaskEventType
+			task.EventListener = func(e task_engine.TaskEvent) { events = append(events, e.Type) }
+
+			err := task.Run(task_engine.WithTaskInputs(context.Background(), inputs))
+			assert.ErrorContains(t, err, "invalid inputs")
+			assert.Nil(t, action.Resolved, "no action should run")
+			assert.Equal(t, task_engine.TaskStatusFailed, task.GetStatus())
+			assert.Equal(t, []task_engine.TaskEventType{task_engine.EventTaskFailed}, events)
+		})
+	}
+}
+
+func TestRunTaskWithInputs(t *testing.T) {
+	manager := task_engine.NewTaskManager(NewDiscardLogger())
+	action := &inputReadingAction{Params: map[string]task_engine.ActionParameter{"version": task_engine.Input("version")}}
+	require.NoError(t, manager.AddTask(deployTask(action)))
+
+	assert.ErrorContains(t, manager.RunTaskWithInputs("deploy", map[string]interface{}{"version": "1", "colour": "blue"}), "no inputs named colour")
+	assert.ErrorContains(t, manager.RunTask("deploy"), `input "version" is required`)
+	assert.Empty(t, manager.GetRunningTasks(), "invalid inputs must not start the task")
+
+	for _, version := range []string{"1.2", "1.3"} {
+		require.NoError(t, manager.RunTaskWithInputs("deploy", map[string]interface{}{"version": version}))
+		require.NoError(t, manager.WaitForAllTasksToComplete(time.Second))
+		assert.Equal(t, version, action.Resolved["version"])
+	}
+}
@@ -17,6 +17,43 @@ type TaskManagerInterface interface {
 	ResetGlobalContext()
 }
 
+// TaskInputRunner is implemented by task managers that start runs with
+// inputs for the task's declared Inputs
+type TaskInputRunner interface {
+	RunTaskWithInputs(taskID string, inputs map[string]interface{}) error
+}
+
+// TaskStopper is implemented by task managers that can wait for a stopped
+// task to exit
+type TaskStopper interface {
+	StopTaskAndWait(taskID string, timeout time.Duration) error
+}
+
+// TaskShutdowner is implemented by task managers that can shut down for a
+// service restart, stopping the tasks that outlive ctx
+type TaskShutdowner interface {
+	Shutdown(ctx context.Context) (ShutdownSummary, error)
+}
+
+// TaskPauser is implemented by task managers that can pause running tasks
+type TaskPauser interface {
+	PauseTask(taskID string) error
+	ResumeTask(taskID string) error
+}
+
+// TaskStateReporter is implemented by task managers that report what each
+// task is doing beyond IsTaskRunning
+type TaskStateReporter interface {
+	GetTaskState(taskID string) (TaskState, error)
+	GetTasksInState(states ...TaskState) []string
+}
+
+// TaskProgressReporter is implemented by task managers that report the
+// progress of running tasks
+type TaskProgressReporter interface {
+	GetTaskProgress(taskID string) (TaskProgress, error)
+}
+
 // TaskInterface defines the contract for individual tasks
 type TaskInterface interface {
 	GetID() string
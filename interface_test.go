@@ -21,6 +21,12 @@ func TestInterfaceTestSuite(t *testing.T) {
 // TestTaskManagerImplementsInterface verifies that TaskManager implements TaskManagerInterface
 func (suite *InterfaceTestSuite) TestTaskManagerImplementsInterface() {
 	var _ TaskManagerInterface = (*TaskManager)(nil)
+	var _ TaskInputRunner = (*TaskManager)(nil)
+	var _ TaskStopper = (*TaskManager)(nil)
+	var _ TaskShutdowner = (*TaskManager)(nil)
+	var _ TaskPauser = (*TaskManager)(nil)
+	var _ TaskStateReporter = (*TaskManager)(nil)
+	var _ TaskProgressReporter = (*TaskManager)(nil)
 }
 
 // TestTaskImplementsInterface verifies that Task implements TaskInterface
@@ -0,0 +1,26 @@
+// Package stopsignal carries a task run's stop signals in a context. It is
+// shared by task_engine, which sets them, and command, which acts on them, so
+// neither has to import the other for it.
+package stopsignal
+
+import "context"
+
+type contextKey struct{}
+
+// Signals let code running inside a task started by a TaskManager tell a
+// requested stop apart from other cancellation
+type Signals struct {
+	Stopping <-chan struct{} // closed by StopTask (and when the run ends)
+	Kill     <-chan struct{} // closed when the stop grace period runs out
+}
+
+// NewContext returns a copy of ctx carrying signals
+func NewContext(ctx context.Context, signals Signals) context.Context {
+	return context.WithValue(ctx, contextKey{}, signals)
+}
+
+// FromContext returns the Signals stored in ctx, if any
+func FromContext(ctx context.Context) (Signals, bool) {
+	signals, ok := ctx.Value(contextKey{}).(Signals)
+	return signals, ok
+}
@@ -0,0 +1,144 @@
+package task_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLockTimeout bounds how long a TaskManager waits for a task's resource locks
+const DefaultLockTimeout = 5 * time.Minute
+
+// ErrLockTimeout is matched by lock errors caused by the lock timeout expiring
+var ErrLockTimeout = errors.New("timed out waiting for resource lock")
+
+// ResourceLocker grants exclusive use of named resources such as "apt" or
+// "compose:/srv/app"
+type ResourceLocker interface {
+	// Lock blocks until every named resource is held or ctx is done. Names are
+	// acquired in sorted order so concurrent callers cannot deadlock.
+	Lock(ctx context.Context, names ...string) (unlock func(), err error)
+}
+
+// ResourceLockDeclarer is implemented by tasks and actions that need exclusive
+// use of named resources while they run
+type ResourceLockDeclarer interface {
+	ResourceLocks() []string
+}
+
+// LockError reports a resource lock that could not be acquired
+type LockError struct {
+	Resource string
+	Err      error
+}
+
+func (e *LockError) Error() string {
+	return fmt.Sprintf("failed to acquire resource lock %q: %v", e.Resource, e.Err)
+}
+
+func (e *LockError) Unwrap() error {
+	return e.Err
+}
+
+// localResourceLocker coordinates goroutines within one process
+type localResourceLocker struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// NewResourceLocker creates a ResourceLocker for tasks within this process
+func NewResourceLocker() ResourceLocker {
+	return newLocalResourceLocker()
+}
+
+func newLocalResourceLocker() *localResourceLocker {
+	return &localResourceLocker{locks: make(map[string]chan struct{})}
+}
+
+func (l *localResourceLocker) Lock(ctx context.Context, names ...string) (func(), error) {
+	return lockAll(ctx, names, l.acquire, l.release)
+}
+
+func (l *localResourceLocker) slot(name string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ch, ok := l.locks[name]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		l.locks[name] = ch
+	}
+	return ch
+}
+
+func (l *localResourceLocker) acquire(ctx context.Context, name string) error {
+	select {
+	case l.slot(name) <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *localResourceLocker) release(name string) {
+	<-l.slot(name)
+}
+
+// lockAll acquires the sorted, de-duplicated names one at a time, releasing
+// the ones already held if any acquisition fails
+func lockAll(ctx context.Context, names []string, acquire func(context.Context, string) error, release func(string)) (func(), error) {
+	names = uniqueSorted(names)
+	held := make([]string, 0, len(names))
+	unlock := func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			release(held[i])
+		}
+	}
+
+	for _, name := range names {
+		if err := acquire(ctx, name); err != nil {
+			unlock()
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("%w: %w", ErrLockTimeout, err)
+			}
+			return nil, &LockError{Resource: name, Err: err}
+		}
+		held = append(held, name)
+	}
+
+	var once sync.Once
+	return func() { once.Do(unlock) }, nil
+}
+
+// collectResourceLocks returns the locks declared by v, if it declares any
+func collectResourceLocks(v interface{}) []string {
+	if d, ok := v.(ResourceLockDeclarer); ok {
+		return d.ResourceLocks()
+	}
+	return nil
+}
+
+// ResourceLocksOf returns the locks declared by actions, sorted and without
+// duplicates, e.g. for actions that run other actions
+func ResourceLocksOf(actions ...ActionWrapper) []string {
+	var names []string
+	for _, action := range actions {
+		names = append(names, collectResourceLocks(action)...)
+	}
+	return uniqueSorted(names)
+}
+
+func uniqueSorted(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,103 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package task_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// fileLockPollInterval is how often a contended file lock is retried
+const fileLockPollInterval = 50 * time.Millisecond
+
+// FileResourceLocker coordinates resource locks between engine processes on
+// the same host using flock(2) on one file per resource. Locks held by a
+// process are released by the kernel if it exits.
+type FileResourceLocker struct {
+	dir   string
+	local *localResourceLocker
+
+	mu    sync.Mutex
+	files map[string]*os.File
+}
+
+// NewFileResourceLocker creates a locker keeping its lock files in dir
+func NewFileResourceLocker(dir string) (*FileResourceLocker, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory %s: %w", dir, err)
+	}
+	return &FileResourceLocker{
+		dir:   dir,
+		local: newLocalResourceLocker(),
+		files: make(map[string]*os.File),
+	}, nil
+}
+
+// Lock acquires every named resource across processes
+func (l *FileResourceLocker) Lock(ctx context.Context, names ...string) (func(), error) {
+	return lockAll(ctx, names, l.acquire, l.release)
+}
+
+// path maps a resource name to its lock file
+func (l *FileResourceLocker) path(name string) string {
+	return filepath.Join(l.dir, url.PathEscape(name)+".lock")
+}
+
+func (l *FileResourceLocker) acquire(ctx context.Context, name string) error {
+	// Serialize within the process first so only one goroutine polls the file
+	if err := l.local.acquire(ctx, name); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(l.path(name), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		l.local.release(name)
+		return err
+	}
+
+	ticker := time.NewTicker(fileLockPollInterval)
+	defer ticker.Stop()
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			f.Close()
+			l.local.release(name)
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			f.Close()
+			l.local.release(name)
+			return ctx.Err()
+		}
+	}
+
+	l.mu.Lock()
+	l.files[name] = f
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *FileResourceLocker) release(name string) {
+	l.mu.Lock()
+	f := l.files[name]
+	delete(l.files, name)
+	l.mu.Unlock()
+
+	if f != nil {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}
+	l.local.release(name)
+}
@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package task_engine
+
+import (
+	"context"
+	"fmt"
+)
+
+// FileResourceLocker is not available on this platform
+type FileResourceLocker struct{}
+
+// NewFileResourceLocker reports that file locks are unsupported on this platform
+func NewFileResourceLocker(dir string) (*FileResourceLocker, error) {
+	return nil, fmt.Errorf("file resource locks are not supported on this platform")
+}
+
+// Lock always fails on this platform
+func (l *FileResourceLocker) Lock(ctx context.Context, names ...string) (func(), error) {
+	return nil, fmt.Errorf("file resource locks are not supported on this platform")
+}
@@ -0,0 +1,91 @@
+package task_engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedAction declares its own resource locks
+type lockedAction struct {
+	TestAction
+}
+
+func (a *lockedAction) ResourceLocks() []string { return []string{"compose:/srv/app", "apt"} }
+
+func TestTaskResourceLocksCollectsDeclarations(t *testing.T) {
+	task := &engine.Task{
+		ID:    "locks",
+		Locks: []string{"network", "apt"},
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*lockedAction]{ID: "a", Wrapped: &lockedAction{}, Locks: []string{"docker"}},
+			&engine.Action[*TestAction]{ID: "b", Wrapped: &TestAction{}},
+		},
+		Finally: []engine.ActionWrapper{
+			&engine.Action[*TestAction]{ID: "c", Wrapped: &TestAction{}, Locks: []string{"cleanup"}},
+		},
+	}
+	assert.Equal(t, []string{"apt", "cleanup", "compose:/srv/app", "docker", "network"}, task.ResourceLocks())
+}
+
+func TestResourceLockerExclusion(t *testing.T) {
+	locker := engine.NewResourceLocker()
+
+	unlock, err := locker.Lock(context.Background(), "b", "a", "a")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = locker.Lock(ctx, "c", "b")
+	assert.ErrorIs(t, err, engine.ErrLockTimeout)
+
+	// "c" was released again when "b" could not be acquired
+	unlockC, err := locker.Lock(context.Background(), "c")
+	require.NoError(t, err)
+	unlockC()
+
+	unlock()
+	unlock() // releasing twice is harmless
+	unlock, err = locker.Lock(context.Background(), "a", "b")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestFileResourceLockerAcrossLockers(t *testing.T) {
+	dir := t.TempDir()
+	// Separate lockers stand in for separate processes: they share nothing but the lock files
+	first, err := engine.NewFileResourceLocker(dir)
+	require.NoError(t, err)
+	second, err := engine.NewFileResourceLocker(dir)
+	require.NoError(t, err)
+
+	unlock, err := first.Lock(context.Background(), "compose:/srv/app")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = second.Lock(ctx, "compose:/srv/app")
+	assert.ErrorIs(t, err, engine.ErrLockTimeout)
+
+	acquired := make(chan struct{})
+	go func() {
+		unlockSecond, err := second.Lock(context.Background(), "compose:/srv/app")
+		if err == nil {
+			unlockSecond()
+		}
+		close(acquired)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	unlock()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second locker never acquired the released lock")
+	}
+	assert.FileExists(t, dir+"/compose:%2Fsrv%2Fapp.lock")
+}
@@ -0,0 +1,269 @@
+package task_engine
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+)
+
+// DefaultNotifyTimeout bounds one delivery to a Notifier, retries included
+const DefaultNotifyTimeout = time.Minute
+
+// Notification describes a finished run of a managed task
+type Notification struct {
+	TaskID string `json:"taskID"`
+	RunID  string `json:"runID"`
+	// Status is a TaskStatus value (success, degraded, failed) or RunStatusCanceled
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+	Time     time.Time     `json:"time"`
+	// Outputs holds the selected entries of the task output map
+	Outputs map[string]interface{} `json:"outputs,omitempty"`
+	// ActionOutputs holds the outputs of the selected actions, keyed by action ID
+	ActionOutputs map[string]interface{} `json:"actionOutputs,omitempty"`
+}
+
+// Notifier delivers notifications about finished task runs to an external system
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(ctx context.Context, notification Notification) error
+
+// Notify calls f
+func (f NotifierFunc) Notify(ctx context.Context, notification Notification) error {
+	return f(ctx, notification)
+}
+
+// NotificationFromOutput builds a Notification from a task output map, as
+// stored in the GlobalContext after every run. The entries named by keys are
+// copied into Outputs.
+func NotificationFromOutput(output map[string]interface{}, keys ...string) Notification {
+	var n Notification
+	n.TaskID, _ = output["taskID"].(string)
+	n.RunID, _ = output["runID"].(string)
+	n.Status, _ = output["status"].(string)
+	n.Error, _ = output["error"].(string)
+	n.Duration, _ = output["totalTime"].(time.Duration)
+	for _, key := range keys {
+		if value, ok := output[key]; ok {
+			if n.Outputs == nil {
+				n.Outputs = make(map[string]interface{}, len(keys))
+			}
+			n.Outputs[key] = value
+		}
+	}
+	return n
+}
+
+// NotifierOption configures a notifier registered with AddNotifier
+type NotifierOption func(*notifierRegistration)
+
+// NotifyTasks limits notifications to tasks whose ID matches one of the
+// patterns (path.Match syntax, e.g. "deploy-*")
+func NotifyTasks(patterns ...string) NotifierOption {
+	return func(r *notifierRegistration) {
+		r.tasks = append(r.tasks, patterns...)
+	}
+}
+
+// NotifyStatuses limits notifications to runs ending with one of the statuses
+func NotifyStatuses(statuses ...string) NotifierOption {
+	return func(r *notifierRegistration) {
+		r.statuses = append(r.statuses, statuses...)
+	}
+}
+
+// NotifyFailuresOnly limits notifications to failed runs
+func NotifyFailuresOnly() NotifierOption {
+	return NotifyStatuses(string(TaskStatusFailed))
+}
+
+// NotifyOutputs includes the named entries of the task output map, such as
+// "changedActions" or "actionErrors"
+func NotifyOutputs(keys ...string) NotifierOption {
+	return func(r *notifierRegistration) {
+		r.outputs = append(r.outputs, keys...)
+	}
+}
+
+// NotifyActionOutputs includes the outputs of the given actions
+func NotifyActionOutputs(actionIDs ...string) NotifierOption {
+	return func(r *notifierRegistration) {
+		r.actionOutputs = append(r.actionOutputs, actionIDs...)
+	}
+}
+
+// NotifyTimeout bounds each delivery; zero means DefaultNotifyTimeout
+func NotifyTimeout(timeout time.Duration) NotifierOption {
+	return func(r *notifierRegistration) {
+		r.timeout = timeout
+	}
+}
+
+type notifierRegistration struct {
+	notifier      Notifier
+	tasks         []string
+	statuses      []string
+	outputs       []string
+	actionOutputs []string
+	timeout       time.Duration
+}
+
+func (r *notifierRegistration) matches(n Notification) bool {
+	if len(r.tasks) > 0 && !matchesAny(r.tasks, n.TaskID) {
+		return false
+	}
+	if len(r.statuses) > 0 {
+		for _, status := range r.statuses {
+			if status == n.Status {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// notifications delivers notifications in the background
+type notifications struct {
+	mu            sync.Mutex
+	registrations []*notifierRegistration
+	wg            sync.WaitGroup
+	// closed is set by Shutdown before it waits for wg; later deliveries are
+	// dropped so no wg.Add races that Wait
+	closed bool
+}
+
+// close stops new deliveries from being dispatched
+func (ns *notifications) close() {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.closed = true
+}
+
+func (ns *notifications) add(r *notifierRegistration) func() {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.registrations = append(ns.registrations, r)
+
+	return func() {
+		ns.mu.Lock()
+		defer ns.mu.Unlock()
+		for i, registered := range ns.registrations {
+			if registered == r {
+				ns.registrations = append(ns.registrations[:i:i], ns.registrations[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// AddNotifier registers a notifier for finished runs of managed tasks.
+// Deliveries run in the background so a slow endpoint never delays a task;
+// failures are logged. Call the returned function to remove the notifier.
+func (tm *TaskManager) AddNotifier(notifier Notifier, opts ...NotifierOption) (remove func()) {
+	registration := &notifierRegistration{notifier: notifier}
+	for _, opt := range opts {
+		opt(registration)
+	}
+	return tm.notifications.add(registration)
+}
+
+// WaitForNotifications waits until every pending notification has been
+// delivered or has failed
+func (tm *TaskManager) WaitForNotifications(timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		tm.notifications.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timeout waiting for notifications to be delivered")
+	}
+}
+
+// notify sends a notification for a finished run to every matching notifier
+func (tm *TaskManager) notify(event TaskEvent, globalContext *GlobalContext) {
+	tm.notifications.mu.Lock()
+	registrations := tm.notifications.registrations
+	tm.notifications.mu.Unlock()
+	if len(registrations) == 0 {
+		return
+	}
+
+	// Runs that never started (e.g. lock timeouts) have no stored output
+	var output map[string]interface{}
+	if stored, ok := globalContext.GetTaskOutput(event.TaskID); ok {
+		if m, ok := stored.(map[string]interface{}); ok && m["runID"] == event.RunID {
+			output = m
+		}
+	}
+
+	for _, r := range registrations {
+		n := NotificationFromOutput(output, r.outputs...)
+		n.TaskID, n.RunID, n.Time = event.TaskID, event.RunID, event.Time
+		n.Duration = event.Duration
+		if event.Error != "" {
+			n.Error = event.Error
+		}
+		switch event.Type {
+		case EventTaskCompleted:
+			if status, ok := event.Data["status"].(string); ok {
+				n.Status = status
+			}
+		case EventTaskFailed:
+			n.Status = string(TaskStatusFailed)
+		case EventTaskCanceled:
+			n.Status = RunStatusCanceled
+		}
+		if !r.matches(n) {
+			continue
+		}
+		for _, actionID := range r.actionOutputs {
+			if actionOutput, ok := globalContext.GetActionOutput(actionID); ok {
+				if n.ActionOutputs == nil {
+					n.ActionOutputs = make(map[string]interface{})
+				}
+				n.ActionOutputs[actionID] = actionOutput
+			}
+		}
+
+		tm.notifications.mu.Lock()
+		if tm.notifications.closed {
+			tm.notifications.mu.Unlock()
+			tm.Logger.Warn("Dropping task notification after shutdown", "taskID", n.TaskID, "runID", n.RunID)
+			return
+		}
+		tm.notifications.wg.Add(1)
+		tm.notifications.mu.Unlock()
+		go func(r *notifierRegistration, n Notification) {
+			defer tm.notifications.wg.Done()
+			timeout := r.timeout
+			if timeout <= 0 {
+				timeout = DefaultNotifyTimeout
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if err := r.notifier.Notify(ctx, n); err != nil {
+				tm.Logger.Error("Task notification failed", "taskID", n.TaskID, "runID", n.RunID, "error", err)
+			}
+		}(r, n)
+	}
+}
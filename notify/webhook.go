@@ -0,0 +1,198 @@
+// Package notify provides Notifier implementations for TaskManager.AddNotifier
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 of "<timestamp>.<body>" as "sha256=<hex>"
+	SignatureHeader = "X-Task-Engine-Signature"
+	// TimestampHeader carries the signing time in Unix seconds
+	TimestampHeader = "X-Task-Engine-Timestamp"
+	// DefaultSignatureMaxAge is the suggested bound on a signature's age for Verify
+	DefaultSignatureMaxAge = 5 * time.Minute
+	// DefaultMaxAttempts is how many times a webhook delivery is tried
+	DefaultMaxAttempts = 3
+	// DefaultBackoff is the wait before the first retry; it doubles after each attempt
+	DefaultBackoff = time.Second
+	// maxBackoff caps the wait between retries
+	maxBackoff = 30 * time.Second
+)
+
+var _ task_engine.Notifier = (*WebhookNotifier)(nil)
+
+// WebhookOption configures a WebhookNotifier
+type WebhookOption func(*WebhookNotifier)
+
+// WithHTTPClient sets the client used to send requests
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.client = client
+	}
+}
+
+// WithSecret signs every request with HMAC-SHA256; see Sign and Verify
+func WithSecret(secret []byte) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.secret = secret
+	}
+}
+
+// WithRetries sets how many attempts are made and the wait before the first
+// retry; later waits double, up to 30s
+func WithRetries(maxAttempts int, backoff time.Duration) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.maxAttempts = maxAttempts
+		w.backoff = backoff
+	}
+}
+
+// WithHeader adds a header to every request, e.g. for an API key
+func WithHeader(key, value string) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.headers.Add(key, value)
+	}
+}
+
+// WebhookNotifier POSTs each Notification as JSON to a URL. Network errors,
+// 408, 429 and 5xx responses are retried with exponential backoff; other
+// non-2xx responses fail immediately.
+type WebhookNotifier struct {
+	url         string
+	client      *http.Client
+	secret      []byte
+	maxAttempts int
+	backoff     time.Duration
+	headers     http.Header
+}
+
+// NewWebhookNotifier creates a notifier posting to url
+func NewWebhookNotifier(url string, opts ...WebhookOption) *WebhookNotifier {
+	w := &WebhookNotifier{
+		url:         url,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+		headers:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Notify delivers the notification, retrying until it succeeds, the attempts
+// run out or ctx is done
+func (w *WebhookNotifier) Notify(ctx context.Context, notification task_engine.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	backoff := w.backoff
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt >= w.maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook %s: %w (last error: %v)", w.url, ctx.Err(), lastErr)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+	return fmt.Errorf("webhook %s failed: %w", w.url, lastErr)
+}
+
+// post sends one request and reports whether a failure is worth retrying
+func (w *WebhookNotifier) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for key, values := range w.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(w.secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+// Sign returns the signature header value for a request sent at timestamp
+// (Unix seconds, as in TimestampHeader): "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" under secret. Signing the timestamp
+// keeps a captured request from being replayed later.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	return "sha256=" + hex.EncodeToString(signatureOf(secret, timestamp, body))
+}
+
+// Verify reports whether signature is a valid signature of body sent at
+// timestamp, and whether timestamp is within maxAge of the current time.
+// Receivers pass the TimestampHeader and SignatureHeader values and the raw
+// request body, e.g. with DefaultSignatureMaxAge. A receiver that must reject
+// every replay also remembers the signatures it accepted within maxAge.
+func Verify(secret, body []byte, timestamp, signature string, maxAge time.Duration) bool {
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := time.Since(time.Unix(sent, 0)); age > maxAge || age < -maxAge {
+		return false
+	}
+	encoded, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, signatureOf(secret, timestamp, body))
+}
+
+func signatureOf(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,125 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/notify"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookNotifierTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhookNotifierTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookNotifierTestSuite))
+}
+
+var notification = task_engine.Notification{
+	TaskID:   "deploy",
+	RunID:    "run-1",
+	Status:   "failed",
+	Duration: 2 * time.Second,
+	Error:    "boom",
+	Outputs:  map[string]interface{}{"changed": true},
+}
+
+func (suite *WebhookNotifierTestSuite) TestPostsSignedJSON() {
+	secret := []byte("s3cret")
+	var received task_engine.Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		suite.Require().NoError(err)
+		suite.Equal(http.MethodPost, r.Method)
+		suite.Equal("application/json", r.Header.Get("Content-Type"))
+		suite.Equal("key", r.Header.Get("X-Api-Key"))
+		timestamp, signature := r.Header.Get(notify.TimestampHeader), r.Header.Get(notify.SignatureHeader)
+		suite.True(notify.Verify(secret, body, timestamp, signature, notify.DefaultSignatureMaxAge))
+		suite.False(notify.Verify([]byte("other"), body, timestamp, signature, notify.DefaultSignatureMaxAge))
+		suite.Require().NoError(json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(server.URL, notify.WithSecret(secret), notify.WithHeader("X-Api-Key", "key"))
+	suite.Require().NoError(notifier.Notify(context.Background(), notification))
+	suite.Equal(notification, received)
+}
+
+func (suite *WebhookNotifierTestSuite) TestVerifyRejectsReplays() {
+	secret, body := []byte("s3cret"), []byte(`{"taskID":"deploy"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	suite.True(notify.Verify(secret, body, now, notify.Sign(secret, now, body), time.Minute))
+	suite.False(notify.Verify(secret, body, old, notify.Sign(secret, old, body), time.Minute), "stale timestamp")
+	suite.False(notify.Verify(secret, body, now, notify.Sign(secret, old, body), time.Minute), "timestamp not covered by the signature")
+	suite.False(notify.Verify(secret, []byte(`{"taskID":"other"}`), now, notify.Sign(secret, now, body), time.Minute))
+	suite.False(notify.Verify(secret, body, "", notify.Sign(secret, "", body), time.Minute))
+}
+
+func (suite *WebhookNotifierTestSuite) TestRetriesServerErrors() {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(server.URL, notify.WithRetries(3, time.Millisecond))
+	suite.NoError(notifier.Notify(context.Background(), notification))
+	suite.Equal(int32(3), atomic.LoadInt32(&attempts))
+}
+
+func (suite *WebhookNotifierTestSuite) TestGivesUp() {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(server.URL, notify.WithRetries(2, time.Millisecond))
+	err := notifier.Notify(context.Background(), notification)
+	suite.ErrorContains(err, "500")
+	suite.Equal(int32(2), atomic.LoadInt32(&attempts))
+}
+
+func (suite *WebhookNotifierTestSuite) TestClientErrorsAreNotRetried() {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := notify.NewWebhookNotifier(server.URL, notify.WithRetries(5, time.Millisecond))
+	suite.Error(notifier.Notify(context.Background(), notification))
+	suite.Equal(int32(1), atomic.LoadInt32(&attempts))
+}
+
+func (suite *WebhookNotifierTestSuite) TestStopsWhenContextEnds() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	notifier := notify.NewWebhookNotifier(server.URL, notify.WithRetries(10, time.Hour))
+	start := time.Now()
+	suite.ErrorIs(notifier.Notify(ctx, notification), context.DeadlineExceeded)
+	suite.Less(time.Since(start), time.Second)
+}
@@ -0,0 +1,232 @@
+package task_engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ProgressKey is the context key under which a running task installs the
+// ProgressReporter for the action being executed
+const ProgressKey contextKey = "progress"
+
+// progressEventInterval limits how often action.progress events are emitted
+// for a single action; the latest value is always visible via GetProgress
+const progressEventInterval = 250 * time.Millisecond
+
+// ActionProgress is fine-grained progress reported by a running action,
+// e.g. bytes copied or images pulled
+type ActionProgress struct {
+	Current int64  `json:"current"`
+	Total   int64  `json:"total,omitempty"` // zero when the total is unknown
+	Unit    string `json:"unit,omitempty"`
+	Message string `json:"message,omitempty"` // e.g. the latest line of command output
+	// ActionID is set when a child of the executing action reported the
+	// progress, see WithChildAction
+	ActionID string `json:"actionID,omitempty"`
+}
+
+// ProgressReporter receives progress updates from the executing action
+type ProgressReporter func(progress ActionProgress)
+
+// ReportProgress reports progress for the action executing with ctx. It is a
+// no-op when the action is not run by a task.
+func ReportProgress(ctx context.Context, current, total int64, unit string) {
+	ReportActionProgress(ctx, ActionProgress{Current: current, Total: total, Unit: unit})
+}
+
+// ReportActionProgress is like ReportProgress but also carries a message
+func ReportActionProgress(ctx context.Context, progress ActionProgress) {
+	if reporter, ok := ProgressReporterFromContext(ctx); ok {
+		reporter(progress)
+	}
+}
+
+// ProgressReporterFromContext returns the reporter installed for the action
+// executing with ctx, e.g. so an action can skip progress bookkeeping when
+// nobody is listening
+func ProgressReporterFromContext(ctx context.Context) (ProgressReporter, bool) {
+	reporter, ok := ctx.Value(ProgressKey).(ProgressReporter)
+	return reporter, ok && reporter != nil
+}
+
+// TaskProgress is a snapshot of a task's current run
+type TaskProgress struct {
+	TaskID            string          `json:"taskID"`
+	RunID             string          `json:"runID"`
+	Running           bool            `json:"running"`
+	Paused            bool            `json:"paused"`
+	CurrentActionID   string          `json:"currentActionID,omitempty"`
+	CurrentActionName string          `json:"currentActionName,omitempty"`
+	CurrentIndex      int             `json:"currentIndex"` // 1-based; zero between actions
+	TotalActions      int             `json:"totalActions"`
+	CompletedActions  int             `json:"completedActions"`
+	Elapsed           time.Duration   `json:"elapsed"`
+	ActionElapsed     time.Duration   `json:"actionElapsed"`
+	ETA               time.Duration   `json:"eta"`
+	ETAKnown          bool            `json:"etaKnown"`
+	Action            *ActionProgress `json:"action,omitempty"`
+}
+
+// String renders the progress for operators, e.g. "step 3 of 7: Docker Pull (42s)"
+func (p TaskProgress) String() string {
+	if !p.Running {
+		return fmt.Sprintf("idle (%d of %d completed)", p.CompletedActions, p.TotalActions)
+	}
+	if p.CurrentIndex == 0 {
+		state := "between steps"
+		if p.Paused {
+			state = "paused"
+		}
+		return fmt.Sprintf("%s (%d of %d completed)", state, p.CompletedActions, p.TotalActions)
+	}
+	name := p.CurrentActionName
+	if name == "" {
+		name = p.CurrentActionID
+	}
+	return fmt.Sprintf("step %d of %d: %s (%s)", p.CurrentIndex, p.TotalActions, name, p.ActionElapsed.Round(time.Second))
+}
+
+// actionHistory accumulates successful durations of an action across runs
+type actionHistory struct {
+	total time.Duration
+	runs  int
+}
+
+func (h actionHistory) average() time.Duration {
+	if h.runs == 0 {
+		return 0
+	}
+	return h.total / time.Duration(h.runs)
+}
+
+// GetProgress returns a snapshot of the task's current run. The ETA is derived
+// from the durations of earlier successful runs of the remaining actions and
+// from the current action's own progress reports; ETAKnown is false when any
+// remaining action has no history.
+func (t *Task) GetProgress() TaskProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := TaskProgress{
+		TaskID:           t.ID,
+		RunID:            t.RunID,
+		Running:          t.running,
+		Paused:           t.paused,
+		TotalActions:     len(t.Actions),
+		CompletedActions: t.runCompleted,
+	}
+	if !t.running {
+		return p
+	}
+
+	now := time.Now()
+	p.Elapsed = now.Sub(t.runStart)
+	remainingFrom := t.nextIndex
+	eta := time.Duration(0)
+	known := true
+
+	if t.currentAction != nil {
+		p.CurrentActionID = t.currentAction.GetID()
+		p.CurrentActionName = t.currentAction.GetName()
+		p.CurrentIndex = t.nextIndex
+		p.ActionElapsed = now.Sub(t.actionStart)
+		if t.actionProgress != nil {
+			progress := *t.actionProgress
+			p.Action = &progress
+		}
+
+		switch history, ok := t.history[p.CurrentActionID]; {
+		case p.Action != nil && p.Action.ActionID == "" && p.Action.Total > 0 && p.Action.Current > 0:
+			left := p.Action.Total - p.Action.Current
+			eta += time.Duration(float64(p.ActionElapsed) * float64(left) / float64(p.Action.Current))
+		case ok:
+			if left := history.average() - p.ActionElapsed; left > 0 {
+				eta += left
+			}
+		default:
+			known = false
+		}
+	}
+
+	for _, action := range t.Actions[min(remainingFrom, len(t.Actions)):] {
+		history, ok := t.history[action.GetID()]
+		if !ok {
+			known = false
+			break
+		}
+		eta += history.average()
+	}
+	if known {
+		p.ETA = eta
+		p.ETAKnown = true
+	}
+	return p
+}
+
+// beginAction records that the action at index (0-based) is now executing
+func (t *Task) beginAction(index int, action ActionWrapper) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.currentAction = action
+	t.nextIndex = index + 1
+	t.actionStart = time.Now()
+	t.actionProgress = nil
+	t.lastProgressEvent = time.Time{}
+}
+
+// endAction clears the current action and, on success, folds its duration
+// into the history used for ETA estimates
+func (t *Task) endAction(action ActionWrapper, succeeded bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.currentAction = nil
+	t.actionProgress = nil
+	if !succeeded {
+		return
+	}
+	if t.history == nil {
+		t.history = make(map[string]actionHistory)
+	}
+	h := t.history[action.GetID()]
+	h.total += action.GetDuration()
+	h.runs++
+	t.history[action.GetID()] = h
+}
+
+// progressReporter returns the reporter installed in the context of the action
+// at index; reports arriving after that action finished are dropped
+func (t *Task) progressReporter(runID string, index int, action ActionWrapper) ProgressReporter {
+	return func(progress ActionProgress) {
+		t.mu.Lock()
+		if t.currentAction == nil || t.nextIndex != index+1 {
+			t.mu.Unlock()
+			return
+		}
+		t.actionProgress = &progress
+		now := time.Now()
+		done := progress.Total > 0 && progress.Current >= progress.Total
+		if !done && now.Sub(t.lastProgressEvent) < progressEventInterval {
+			t.mu.Unlock()
+			return
+		}
+		t.lastProgressEvent = now
+		t.mu.Unlock()
+
+		actionID := action.GetID()
+		if progress.ActionID != "" {
+			actionID = progress.ActionID
+		}
+		t.emit(TaskEvent{
+			Type:     EventActionProgress,
+			RunID:    runID,
+			ActionID: actionID,
+			Data: map[string]interface{}{
+				"current": progress.Current,
+				"total":   progress.Total,
+				"unit":    progress.Unit,
+				"message": progress.Message,
+			},
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package task_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrShuttingDown is returned by RunTask and RunTaskWithInputs once Shutdown
+// has been called
+var ErrShuttingDown = errors.New("task manager is shutting down")
+
+// DefaultShutdownFlushTimeout bounds the flush step of a Shutdown whose
+// context has already expired, and how long interrupted runs may take to exit
+// after their grace period
+const DefaultShutdownFlushTimeout = 5 * time.Second
+
+// ShutdownSummary describes what Shutdown did with the tasks that were running
+type ShutdownSummary struct {
+	// Drained lists the tasks that finished on their own, sorted
+	Drained []string
+	// Interrupted lists the runs that were stopped when the deadline passed
+	Interrupted []InterruptedRun
+	Duration    time.Duration
+}
+
+// InterruptedRun is a run that Shutdown stopped before it finished
+type InterruptedRun struct {
+	TaskID string
+	RunID  string
+	// Progress is where the run was when it was stopped
+	Progress TaskProgress
+	// Exited is false when the run was still running when Shutdown returned
+	Exited bool
+}
+
+// ShutdownHook is run by Shutdown once no task is running any more, e.g. to
+// write checkpoints or persist the run history
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown registers a hook for Shutdown to run after the tasks have exited
+// and notifications were delivered. Hooks run in registration order.
+func (tm *TaskManager) OnShutdown(hook ShutdownHook) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.shutdownHooks = append(tm.shutdownHooks, hook)
+}
+
+// Shutdown stops the manager for a service restart. New runs are refused with
+// ErrShuttingDown and running tasks may finish until ctx is done; the rest
+// are stopped like StopTask and get StopGracePeriod to exit. Pending
+// notifications are then delivered and the OnShutdown hooks run, bounded by
+// ctx or, once it has expired, by DefaultShutdownFlushTimeout. Runs that
+// finish after that point are not notified.
+//
+// The error reports runs that did not exit, undelivered notifications and
+// failed hooks; the summary is valid either way.
+func (tm *TaskManager) Shutdown(ctx context.Context) (ShutdownSummary, error) {
+	start := time.Now()
+	tm.mu.Lock()
+	if tm.shuttingDown {
+		tm.mu.Unlock()
+		return ShutdownSummary{}, ErrShuttingDown
+	}
+	tm.shuttingDown = true
+	runs := make(map[string]*taskRun, len(tm.runningTasks))
+	for taskID, run := range tm.runningTasks {
+		runs[taskID] = run
+	}
+	grace := tm.StopGracePeriod
+	tm.mu.Unlock()
+	if grace <= 0 {
+		grace = DefaultStopGracePeriod
+	}
+	tm.Logger.Info("Shutting down task manager", "runningTasks", len(runs))
+
+	taskIDs := make([]string, 0, len(runs))
+	for taskID := range runs {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	var summary ShutdownSummary
+	var errs []error
+	for _, taskID := range taskIDs {
+		select {
+		case <-runs[taskID].done:
+		case <-ctx.Done():
+		}
+	}
+	for _, taskID := range taskIDs {
+		select {
+		case <-runs[taskID].done:
+			summary.Drained = append(summary.Drained, taskID)
+		default:
+			summary.Interrupted = append(summary.Interrupted, tm.interrupt(taskID, runs[taskID]))
+		}
+	}
+
+	if len(summary.Interrupted) > 0 {
+		timer := time.NewTimer(grace + DefaultShutdownFlushTimeout)
+		for i := range summary.Interrupted {
+			interrupted := &summary.Interrupted[i]
+			select {
+			case <-runs[interrupted.TaskID].done:
+				interrupted.Exited = true
+			case <-timer.C:
+				errs = append(errs, fmt.Errorf("task %q did not exit", interrupted.TaskID))
+			}
+		}
+		timer.Stop()
+	}
+
+	flushCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		flushCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), DefaultShutdownFlushTimeout)
+		defer cancel()
+	}
+	if err := tm.waitForNotifications(flushCtx); err != nil {
+		errs = append(errs, err)
+	}
+	tm.mu.Lock()
+	hooks := tm.shutdownHooks
+	tm.mu.Unlock()
+	for _, hook := range hooks {
+		if err := hook(flushCtx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook failed: %w", err))
+		}
+	}
+
+	summary.Duration = time.Since(start)
+	tm.Logger.Info("Task manager shut down", "drained", len(summary.Drained), "interrupted", len(summary.Interrupted), "duration", summary.Duration)
+	return summary, errors.Join(errs...)
+}
+
+// interrupt stops a run that outlived the Shutdown deadline
+func (tm *TaskManager) interrupt(taskID string, run *taskRun) InterruptedRun {
+	tm.mu.Lock()
+	task := tm.Tasks[taskID]
+	tm.stopLocked(taskID, run)
+	tm.mu.Unlock()
+
+	progress := task.GetProgress()
+	tm.Logger.Warn("Interrupting task at shutdown", "taskID", taskID, "runID", progress.RunID, "currentAction", progress.CurrentActionID)
+	return InterruptedRun{TaskID: taskID, RunID: progress.RunID, Progress: progress}
+}
+
+// waitForNotifications stops further deliveries and waits for the pending
+// ones, bounded by ctx
+func (tm *TaskManager) waitForNotifications(ctx context.Context) error {
+	tm.notifications.close()
+	done := make(chan struct{})
+	go func() {
+		tm.notifications.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("notifications not delivered before shutdown: %w", ctx.Err())
+	}
+}
+
+// ShutdownResult is delivered by ShutdownOnSignal once the manager is shut down
+type ShutdownResult struct {
+	Signal  os.Signal
+	Summary ShutdownSummary
+	Err     error
+}
+
+// ShutdownOnSignal shuts the manager down when the process receives SIGINT
+// or SIGTERM, or one of signals if given. Running tasks get timeout to finish
+// (zero waits for them indefinitely); a second signal interrupts them right
+// away. The result is sent on the returned channel, which is closed
+// afterwards or when stop is called first. Signals are handled by the Go
+// runtime again once shutdown is complete.
+//
+//	done, stop := manager.ShutdownOnSignal(30 * time.Second)
+//	defer stop()
+//	result := <-done
+func (tm *TaskManager) ShutdownOnSignal(timeout time.Duration, signals ...os.Signal) (done <-chan ShutdownResult, stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 2)
+	signal.Notify(received, signals...)
+	results := make(chan ShutdownResult, 1)
+	quit := make(chan struct{})
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(quit)
+		})
+	}
+
+	go func() {
+		defer close(results)
+		var sig os.Signal
+		select {
+		case sig = <-received:
+		case <-quit:
+			return
+		}
+		tm.Logger.Info("Received signal, shutting down", "signal", sig, "timeout", timeout)
+
+		var ctx context.Context
+		var cancel context.CancelFunc
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		} else {
+			ctx, cancel = context.WithCancel(context.Background())
+		}
+		defer cancel()
+		go func() {
+			select {
+			case again := <-received:
+				tm.Logger.Warn("Received second signal, interrupting running tasks", "signal", again)
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		summary, err := tm.Shutdown(ctx)
+		stop()
+		results <- ShutdownResult{Signal: sig, Summary: summary, Err: err}
+	}()
+	return results, stop
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,18 +16,100 @@ import (
 // is not met, signaling that the task should be gracefully aborted.
 var ErrPrerequisiteNotMet = errors.New("task prerequisite not met")
 
+// ErrorPolicy controls how a task reacts when an action returns an error
+type ErrorPolicy string
+
+const (
+	// ErrorPolicyFail aborts the task (default)
+	ErrorPolicyFail ErrorPolicy = "fail"
+	// ErrorPolicyContinue records the error and moves on to the next action
+	ErrorPolicyContinue ErrorPolicy = "continue"
+	// ErrorPolicyContinueDegraded records the error, moves on, and marks the task degraded
+	ErrorPolicyContinueDegraded ErrorPolicy = "continue-degraded"
+)
+
+// TaskStatus is the overall outcome of a task run
+type TaskStatus string
+
+const (
+	TaskStatusSuccess  TaskStatus = "success"
+	TaskStatusDegraded TaskStatus = "degraded"
+	TaskStatusFailed   TaskStatus = "failed"
+)
+
+// ActionFailure records an error returned by an action during a task run,
+// together with the policy that was applied to it
+type ActionFailure struct {
+	ActionID string
+	Policy   ErrorPolicy
+	Err      error
+}
+
+func (f ActionFailure) Error() string {
+	return fmt.Sprintf("action %s: %v", f.ActionID, f.Err)
+}
+
+func (f ActionFailure) Unwrap() error {
+	return f.Err
+}
+
+// DefaultFinallyTimeout bounds how long Finally actions may run once the task's
+// own context has been canceled
+const DefaultFinallyTimeout = 30 * time.Second
+
 // Task represents a collection of actions to execute in sequential order
 type Task struct {
-	ID             string
-	RunID          string
-	Name           string
-	Actions        []ActionWrapper
+	ID      string
+	RunID   string
+	Name    string
+	Actions []ActionWrapper
+	// Finally actions run after Actions on every exit path (success, failure or
+	// cancellation). Their errors are recorded separately from the primary error.
+	Finally []ActionWrapper
+	// FinallyTimeout bounds the Finally actions when the task was canceled;
+	// zero means DefaultFinallyTimeout
+	FinallyTimeout time.Duration
 	Logger         *slog.Logger
 	TotalTime      time.Duration
 	CompletedTasks int
-	mu             sync.Mutex // protects concurrent access to TotalTime and CompletedTasks
+	// Locks names resources held exclusively for the whole run when the task is
+	// started by a TaskManager; see ResourceLocks
+	Locks []string
+	// Inputs declares the values supplied when the task is run; actions read
+	// them through TaskInputParameter
+	Inputs []TaskInput
+	// Outputs declares values resolved after a successful run and merged into
+	// the task output, e.g. {"imageTag": ActionOutputField("build", "tag")}
+	Outputs map[string]ActionParameter
+	// EventListener optionally receives lifecycle events for every run of this task
+	EventListener TaskEventListener
+	mu            sync.Mutex // protects concurrent access to TotalTime and CompletedTasks
+	// managerListener is installed by the TaskManager that owns this task
+	managerListener TaskEventListener
+	// Pause support: while paused, the task blocks before its next action
+	paused     bool
+	resumeCh   chan struct{}
+	pausedTime time.Duration
+	// Progress tracking for the current run; history persists across runs
+	running           bool
+	runStart          time.Time
+	currentAction     ActionWrapper
+	nextIndex         int
+	runCompleted      int
+	actionStart       time.Time
+	actionProgress    *ActionProgress
+	lastProgressEvent time.Time
+	history           map[string]actionHistory
+	// changedActions lists the actions of the current run that reported "changed": true
+	changedActions []string
+	// outputs holds the declared Outputs resolved by the last successful run
+	outputs map[string]interface{}
 	// ResultProvider support
 	executionError error
+	failedActionID string
+	actionErrors   []ActionFailure
+	cleanupErrors  []ActionFailure
+	status         TaskStatus
 	customResult   interface{}
 	// Optional: build a custom task result from accumulated action outputs
 	ResultBuilder func(ctx *TaskContext) (interface{}, error)
@@ -56,12 +139,46 @@ func (t *Task) Run(ctx context.Context) error {
 // This enables cross-task and cross-action parameter passing by sharing context
 // between different task executions.
 func (t *Task) RunWithContext(ctx context.Context, globalContext *GlobalContext) error {
+	// A Task holds the state of one run at a time
+	t.mu.Lock()
+	if t.running {
+		t.mu.Unlock()
+		return fmt.Errorf("task %s: %w", t.ID, ErrTaskRunning)
+	}
+	t.running = true
+	t.mu.Unlock()
+
+	// Inputs are checked before anything runs; see WithTaskInputs
+	inputs, err := t.resolveInputs(TaskInputsFromContext(ctx))
+	if err != nil {
+		t.mu.Lock()
+		t.running = false
+		t.mu.Unlock()
+		return t.abortRun(ctx, fmt.Errorf("invalid inputs: %w", err))
+	}
+	ctx = WithTaskInputs(ctx, inputs)
+
 	t.mu.Lock()
 	t.RunID = uuid.New().String()
 	runID := t.RunID // Store locally to avoid race conditions in logging
+	t.failedActionID = ""
+	t.executionError = nil
+	t.customResult = nil
+	t.actionErrors = nil
+	t.cleanupErrors = nil
+	t.changedActions = nil
+	t.outputs = nil
+	t.status = ""
+	t.pausedTime = 0
+	t.runCompleted = 0
+	t.runStart = time.Now()
+	t.currentAction = nil
+	t.nextIndex = 0
+	startTime := t.runStart
 	t.mu.Unlock()
 
 	t.log("Starting task", "taskID", t.ID, "runID", runID)
+	t.emit(TaskEvent{Type: EventTaskStarted, RunID: runID})
 
 	// Create global context if not provided
 	if globalContext == nil {
@@ -69,127 +186,513 @@ func (t *Task) RunWithContext(ctx context.Context, globalContext *GlobalContext)
 	}
 
 	// Create task context
-	taskContext := NewTaskContext(t.ID, globalContext, t.Logger)
+	taskContext := NewTaskContext(t.ID, globalContext, t.logger())
+
+	// Record this task in the chain of enclosing tasks so nested sub-tasks can
+	// report the full path of a failing action
+	ctx = context.WithValue(ctx, TaskPathKey, append(TaskPathFromContext(ctx), t.ID))
+	ctx = context.WithValue(ctx, ExecutionKey, ExecutionInfo{TaskID: t.ID, RunID: runID})
 
 	// Validate parameters before execution
 	if err := t.validateParameters(taskContext); err != nil {
 		t.log("Task parameter validation failed", "taskID", t.ID, "runID", runID, "error", err)
-		return fmt.Errorf("task %s (run %s) parameter validation failed: %w", t.ID, runID, err)
+		t.runFinally(ctx, globalContext, runID)
+		err = &TaskError{TaskID: t.ID, RunID: runID, Kind: ErrorKindPermanent, Err: fmt.Errorf("parameter validation failed: %w", err)}
+		t.mu.Lock()
+		t.executionError = err
+		t.status = TaskStatusFailed
+		t.running = false
+		t.mu.Unlock()
+		return err
 	}
 
+	runErr := t.runActions(ctx, globalContext, runID)
+	if runErr == nil {
+		runErr = t.resolveOutputs(ctx, globalContext, runID)
+	}
+
+	// Build custom result if a ResultBuilder is provided
+	if runErr == nil && t.ResultBuilder != nil {
+		res, buildErr := t.ResultBuilder(taskContext)
+		if buildErr != nil {
+			t.SetError(buildErr)
+		} else if res != nil {
+			t.SetResult(res)
+		}
+	}
+
+	t.runFinally(ctx, globalContext, runID)
+
+	t.mu.Lock()
+	t.status = t.computeStatusLocked()
+	status := t.status
+	t.paused = false
+	t.resumeCh = nil
+	t.running = false
+	t.currentAction = nil
+	t.mu.Unlock()
+
+	// Store task output and result provider in global context on every exit path
+	t.storeTaskOutput(globalContext)
+	t.storeTaskResultIfAbsent(globalContext)
+
+	if runErr != nil {
+		eventType := EventTaskFailed
+		if ctx.Err() != nil {
+			eventType = EventTaskCanceled
+		}
+		t.emit(TaskEvent{Type: eventType, RunID: runID, Duration: time.Since(startTime), Error: runErr.Error()})
+		return runErr
+	}
+	t.log("Task completed", "taskID", t.ID, "runID", runID, "status", status, "totalDuration", t.GetTotalTime())
+	t.emit(TaskEvent{Type: EventTaskCompleted, RunID: runID, Duration: time.Since(startTime), Data: map[string]interface{}{"status": string(status)}})
+	return nil
+}
+
+// ResourceLocks returns the task's Locks together with the locks declared by
+// its actions and Finally actions, sorted and without duplicates
+func (t *Task) ResourceLocks() []string {
+	names := append([]string(nil), t.Locks...)
 	for _, action := range t.Actions {
-		select {
-		case <-ctx.Done():
+		names = append(names, collectResourceLocks(action)...)
+	}
+	for _, action := range t.Finally {
+		names = append(names, collectResourceLocks(action)...)
+	}
+	return uniqueSorted(names)
+}
+
+// abortRun records a run that failed before it could start, e.g. because its
+// resource locks could not be acquired or its inputs are invalid, and returns
+// err as a *TaskError
+func (t *Task) abortRun(ctx context.Context, err error) error {
+	t.mu.Lock()
+	t.RunID = uuid.New().String()
+	runID := t.RunID
+	err = &TaskError{TaskID: t.ID, RunID: runID, Kind: classifyError(ctx, err), Err: err}
+	t.failedActionID = ""
+	t.customResult = nil
+	t.actionErrors = nil
+	t.cleanupErrors = nil
+	t.changedActions = nil
+	t.executionError = err
+	t.status = TaskStatusFailed
+	// A pause requested for this run must not block the next one
+	t.paused = false
+	t.resumeCh = nil
+	t.mu.Unlock()
+
+	t.log("Task could not start", "taskID", t.ID, "runID", runID, "error", err)
+	eventType := EventTaskFailed
+	if ctx.Err() != nil {
+		eventType = EventTaskCanceled
+	}
+	t.emit(TaskEvent{Type: eventType, RunID: runID, Error: err.Error()})
+	return err
+}
+
+// runActions executes the task's actions in order, applying each action's
+// ErrorPolicy, and returns the error that aborted the run (if any).
+func (t *Task) runActions(ctx context.Context, globalContext *GlobalContext, runID string) error {
+	for i, action := range t.Actions {
+		t.waitWhilePaused(ctx, runID)
+		if ctx.Err() != nil {
 			t.log("Task canceled", "taskID", t.ID, "runID", runID, "reason", ctx.Err())
 			t.SetError(ctx.Err())
-			// Ensure task output and result provider are stored even on cancellation
-			t.storeTaskOutput(globalContext)
-			t.storeTaskResultIfAbsent(globalContext)
-			return ctx.Err()
-		default:
-			// Execute action
-			t.log("Executing action", "taskID", t.ID, "actionID", action.GetID())
-
-			// Create a new context with the global context embedded
-			actionCtx := context.WithValue(ctx, GlobalContextKey, globalContext)
-
-			execErr := action.Execute(actionCtx)
-			if execErr != nil {
-				if errors.Is(execErr, ErrPrerequisiteNotMet) {
-					t.log("Task aborted: prerequisite not met", "taskID", t.ID, "runID", runID, "actionID", action.GetID(), "error", execErr)
-					t.SetError(execErr)
-					// Store task output and result provider on failure
-					t.storeTaskOutput(globalContext)
-					t.storeTaskResultIfAbsent(globalContext)
-					return fmt.Errorf("task %s (run %s) aborted: prerequisite not met in action %s: %w", t.ID, runID, action.GetID(), execErr)
-				} else {
-					t.log("Task failed: action execution error", "taskID", t.ID, "runID", runID, "actionID", action.GetID(), "error", execErr)
-					t.SetError(execErr)
-					// Store task output and result provider on failure
-					t.storeTaskOutput(globalContext)
-					t.storeTaskResultIfAbsent(globalContext)
-					return fmt.Errorf("task %s (run %s) failed at action %s: %w", t.ID, runID, action.GetID(), execErr)
-				}
+			return &TaskError{TaskID: t.ID, RunID: runID, Kind: classifyError(ctx, ctx.Err()), Err: ctx.Err()}
+		}
+
+		// Execute action
+		t.log("Executing action", "taskID", t.ID, "actionID", action.GetID())
+		t.emit(TaskEvent{Type: EventActionStarted, RunID: runID, ActionID: action.GetID()})
+
+		// Create a new context with the global context and progress reporter embedded
+		actionCtx := context.WithValue(ctx, GlobalContextKey, globalContext)
+		actionCtx = context.WithValue(actionCtx, ProgressKey, t.progressReporter(runID, i, action))
+		actionCtx = withActionExecution(actionCtx, action.GetID())
+
+		t.beginAction(i, action)
+		execErr := action.Execute(actionCtx)
+		t.endAction(action, execErr == nil)
+		if execErr != nil {
+			actionErr := newActionError(actionCtx, action, execErr)
+			t.emit(TaskEvent{Type: EventActionFailed, RunID: runID, ActionID: action.GetID(), Error: execErr.Error()})
+			policy := actionErrorPolicy(action)
+			t.mu.Lock()
+			t.actionErrors = append(t.actionErrors, ActionFailure{ActionID: action.GetID(), Policy: policy, Err: actionErr})
+			t.mu.Unlock()
+
+			// Prerequisite failures and cancellation always abort the task
+			aborting := errors.Is(execErr, ErrPrerequisiteNotMet) || ctx.Err() != nil
+			if !aborting && policy != ErrorPolicyFail {
+				t.log("Action failed, continuing per error policy", "taskID", t.ID, "runID", runID, "actionID", action.GetID(), "policy", policy, "error", execErr)
+				continue
 			}
 
-			t.log("Action executed successfully", "taskID", t.ID, "actionID", action.GetID())
+			t.mu.Lock()
+			t.failedActionID = action.GetID()
+			t.mu.Unlock()
+			t.SetError(execErr)
+			if errors.Is(execErr, ErrPrerequisiteNotMet) {
+				t.log("Task aborted: prerequisite not met", "taskID", t.ID, "runID", runID, "actionID", action.GetID(), "error", execErr)
+			} else {
+				t.log("Task failed: action execution error", "taskID", t.ID, "runID", runID, "actionID", action.GetID(), "error", execErr)
+			}
+			return &TaskError{TaskID: t.ID, RunID: runID, ActionID: action.GetID(), Kind: actionErr.Kind, Err: actionErr}
+		}
 
-			// Store action output in global context
-			t.log("Storing action output", "taskID", t.ID, "actionID", action.GetID())
-			t.storeActionOutput(action, globalContext)
+		t.log("Action executed successfully", "taskID", t.ID, "actionID", action.GetID())
+		completed := TaskEvent{Type: EventActionCompleted, RunID: runID, ActionID: action.GetID(), Duration: action.GetDuration()}
+		if changed, ok := actionChanged(action); ok {
+			completed.Data = map[string]interface{}{"changed": changed}
+			if changed {
+				t.mu.Lock()
+				t.changedActions = append(t.changedActions, action.GetID())
+				t.mu.Unlock()
+			}
 		}
+		t.emit(completed)
+
+		// Store action output in global context
+		t.log("Storing action output", "taskID", t.ID, "actionID", action.GetID())
+		t.storeActionOutput(action, globalContext)
 
 		t.mu.Lock()
 		t.TotalTime += action.GetDuration()
 		t.CompletedTasks += 1
+		t.runCompleted++
 		t.mu.Unlock()
 	}
+	return nil
+}
 
-	// Build custom result if a ResultBuilder is provided
-	if t.ResultBuilder != nil {
-		res, buildErr := t.ResultBuilder(taskContext)
-		if buildErr != nil {
-			t.SetError(buildErr)
-		} else if res != nil {
-			t.SetResult(res)
-		}
+// waitWhilePaused blocks while the task is paused, returning once it is resumed
+// or its context is canceled. The time spent blocked is added to the paused
+// time; task.resumed is only emitted when the task was actually resumed.
+func (t *Task) waitWhilePaused(ctx context.Context, runID string) {
+	t.mu.Lock()
+	if !t.paused {
+		t.mu.Unlock()
+		return
 	}
+	resumeCh := t.resumeCh
+	t.mu.Unlock()
 
-	// Store task output and result provider in global context on success
-	t.storeTaskOutput(globalContext)
-	t.storeTaskResultIfAbsent(globalContext)
+	t.log("Task paused", "taskID", t.ID, "runID", runID)
+	t.emit(TaskEvent{Type: EventTaskPaused, RunID: runID})
+	start := time.Now()
+
+	resumed := false
+	select {
+	case <-resumeCh:
+		resumed = true
+	case <-ctx.Done():
+	}
+
+	pausedFor := time.Since(start)
+	t.mu.Lock()
+	t.pausedTime += pausedFor
+	t.mu.Unlock()
+	if !resumed {
+		// The run ends here and reports task.canceled rather than task.resumed
+		t.log("Paused task canceled", "taskID", t.ID, "runID", runID, "pausedFor", pausedFor)
+		return
+	}
+	t.log("Task resumed", "taskID", t.ID, "runID", runID, "pausedFor", pausedFor)
+	t.emit(TaskEvent{Type: EventTaskResumed, RunID: runID, Duration: pausedFor})
+}
+
+// Pause asks the task to block before its next action until Resume is called.
+// The action currently executing is allowed to finish. Pausing a task that is
+// not running is an error.
+func (t *Task) Pause() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.running {
+		return fmt.Errorf("task %q is not running", t.ID)
+	}
+	return t.pauseLocked()
+}
+
+// requestPause is Pause for a TaskManager run that may still be waiting for
+// its resource locks; the pause then applies once the run starts
+func (t *Task) requestPause() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pauseLocked()
+}
+
+func (t *Task) pauseLocked() error {
+	if t.paused {
+		return fmt.Errorf("task %q is already paused", t.ID)
+	}
+	t.paused = true
+	t.resumeCh = make(chan struct{})
+	return nil
+}
 
-	t.log("Task completed", "taskID", t.ID, "runID", runID, "totalDuration", t.GetTotalTime())
+// Resume releases a paused task
+func (t *Task) Resume() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.paused {
+		return fmt.Errorf("task %q is not paused", t.ID)
+	}
+	t.paused = false
+	close(t.resumeCh)
+	t.resumeCh = nil
 	return nil
 }
 
+// IsPaused reports whether a pause has been requested and not yet resumed
+func (t *Task) IsPaused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paused
+}
+
+// GetPausedTime returns how long the last run spent blocked while paused
+func (t *Task) GetPausedTime() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pausedTime
+}
+
+// emit stamps the event with the task ID and time and delivers it to the
+// task's listener and the owning TaskManager, if any
+func (t *Task) emit(event TaskEvent) {
+	event.TaskID = t.ID
+	event.Time = time.Now()
+
+	t.mu.Lock()
+	listener := t.EventListener
+	managerListener := t.managerListener
+	t.mu.Unlock()
+
+	if listener != nil {
+		listener(event)
+	}
+	if managerListener != nil {
+		managerListener(event)
+	}
+}
+
+// cleanupContext returns a fresh context for cleanup after ctx was canceled.
+// It keeps ctx's values and ends after timeout or, for a task stopped by a
+// TaskManager, with the hard stop of its grace period.
+func cleanupContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	signals, stoppable := StopSignalsFromContext(ctx)
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	if stoppable {
+		// A stopped task's cleanup ends with its grace period
+		go func() {
+			select {
+			case <-signals.Kill:
+				cancel()
+			case <-cleanupCtx.Done():
+			}
+		}()
+	}
+	return cleanupCtx, cancel
+}
+
+// runFinally executes every Finally action, even if earlier ones fail. When the
+// task's context is already canceled, the cleanup runs on a fresh context
+// (keeping its values) bounded by FinallyTimeout and the hard stop of a
+// TaskManager's stop grace period.
+func (t *Task) runFinally(ctx context.Context, globalContext *GlobalContext, runID string) {
+	if len(t.Finally) == 0 {
+		return
+	}
+
+	if ctx.Err() != nil {
+		timeout := t.FinallyTimeout
+		if timeout <= 0 {
+			timeout = DefaultFinallyTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = cleanupContext(ctx, timeout)
+		defer cancel()
+	}
+	actionCtx := context.WithValue(ctx, GlobalContextKey, globalContext)
+
+	t.log("Running finally actions", "taskID", t.ID, "runID", runID, "count", len(t.Finally))
+	for _, action := range t.Finally {
+		if err := action.Execute(withActionExecution(actionCtx, action.GetID())); err != nil {
+			t.log("Finally action failed", "taskID", t.ID, "runID", runID, "actionID", action.GetID(), "error", err)
+			t.mu.Lock()
+			t.cleanupErrors = append(t.cleanupErrors, ActionFailure{ActionID: action.GetID(), Policy: ErrorPolicyContinue, Err: err})
+			t.mu.Unlock()
+			continue
+		}
+		t.storeActionOutput(action, globalContext)
+	}
+}
+
+// actionChanged reads the uniform "changed" flag from an action's output
+func actionChanged(action ActionWrapper) (changed bool, reported bool) {
+	output, ok := action.GetOutput().(map[string]interface{})
+	if !ok {
+		return false, false
+	}
+	changed, reported = output["changed"].(bool)
+	return changed, reported
+}
+
+// actionErrorPolicy returns the ErrorPolicy declared by an action, defaulting to ErrorPolicyFail
+func actionErrorPolicy(action ActionWrapper) ErrorPolicy {
+	if p, ok := action.(interface{ GetErrorPolicy() ErrorPolicy }); ok {
+		switch policy := p.GetErrorPolicy(); policy {
+		case ErrorPolicyContinue, ErrorPolicyContinueDegraded:
+			return policy
+		}
+	}
+	return ErrorPolicyFail
+}
+
+// computeStatusLocked derives the overall status of the last run. Caller must hold t.mu.
+func (t *Task) computeStatusLocked() TaskStatus {
+	if t.executionError != nil {
+		return TaskStatusFailed
+	}
+	for _, failure := range t.actionErrors {
+		if failure.Policy == ErrorPolicyContinueDegraded {
+			return TaskStatusDegraded
+		}
+	}
+	if len(t.cleanupErrors) > 0 {
+		return TaskStatusDegraded
+	}
+	return TaskStatusSuccess
+}
+
 // storeActionOutput stores the output from an action in the global context.
 // This enables parameter passing between actions by making action outputs
 // available to subsequent actions in the same or different tasks.
 func (t *Task) storeActionOutput(action ActionWrapper, globalContext *GlobalContext) {
 	actionID := action.GetID()
-	t.Logger.Info("Storing action output", "actionID", actionID)
+	t.logger().Info("Storing action output", "actionID", actionID)
 
 	// Store basic output if action implements ActionInterface
 	if actionWithOutput, ok := action.(interface{ GetOutput() interface{} }); ok {
 		output := actionWithOutput.GetOutput()
-		t.Logger.Info("Action implements GetOutput", "actionID", actionID, "output", output)
+		t.logger().Info("Action implements GetOutput", "actionID", actionID, "output", output)
 		if output != nil {
 			globalContext.StoreActionOutput(actionID, output)
-			t.Logger.Info("Stored action output", "actionID", actionID, "output", output)
+			t.logger().Info("Stored action output", "actionID", actionID, "output", output)
 		} else {
-			t.Logger.Info("Action output is nil, not storing", "actionID", actionID)
+			t.logger().Info("Action output is nil, not storing", "actionID", actionID)
 		}
 	} else {
-		t.Logger.Info("Action does not implement GetOutput", "actionID", actionID)
+		t.logger().Info("Action does not implement GetOutput", "actionID", actionID)
+	}
+
+	// Store typed output if the wrapped action implements ActionWithTypedOutput
+	if provider, ok := action.(TypedOutputProvider); ok {
+		if typed, ok := provider.TypedOutputValue(); ok {
+			globalContext.StoreTypedActionOutput(actionID, typed)
+		}
 	}
 
 	// Store result provider if action implements ResultProvider
 	if resultProvider, ok := action.(ResultProvider); ok {
 		globalContext.StoreActionResult(actionID, resultProvider)
-		t.Logger.Info("Stored action result provider", "actionID", actionID)
+		t.logger().Info("Stored action result provider", "actionID", actionID)
 	}
 }
 
+// reservedOutputNames are the standard task output keys, which declared
+// Outputs may not replace
+var reservedOutputNames = map[string]bool{
+	"taskID": true, "runID": true, "name": true, "totalTime": true, "completedTasks": true,
+	"success": true, "status": true, "pausedTime": true, "changed": true, "changedActions": true,
+	"error": true, "actionErrors": true, "cleanupErrors": true,
+}
+
+// resolveOutputs resolves the declared Outputs against the run's context. A
+// reserved or unresolvable output fails the run.
+func (t *Task) resolveOutputs(ctx context.Context, globalContext *GlobalContext, runID string) error {
+	if len(t.Outputs) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(t.Outputs))
+	for name := range t.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ctx = context.WithValue(ctx, GlobalContextKey, globalContext)
+	outputs := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		var err error
+		if reservedOutputNames[name] {
+			err = fmt.Errorf("name is reserved")
+		} else if t.Outputs[name] == nil {
+			err = fmt.Errorf("parameter is nil")
+		} else {
+			outputs[name], err = t.Outputs[name].Resolve(ctx, globalContext)
+		}
+		if err != nil {
+			err = &TaskError{TaskID: t.ID, RunID: runID, Kind: classifyError(ctx, err), Err: fmt.Errorf("failed to resolve output %q: %w", name, err)}
+			t.log("Task output resolution failed", "taskID", t.ID, "runID", runID, "output", name, "error", err)
+			t.SetError(err)
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	t.outputs = outputs
+	t.mu.Unlock()
+	return nil
+}
+
 // storeTaskOutput stores the task output in the global context.
 // This enables cross-task parameter passing by making task outputs
 // available to actions in other tasks.
 func (t *Task) storeTaskOutput(globalContext *GlobalContext) {
-	// Create task output with basic information
-	taskOutput := map[string]interface{}{
+	taskOutput := t.summary()
+	globalContext.StoreTaskOutput(t.ID, taskOutput)
+	t.logger().Debug("Stored task output", "taskID", t.ID, "output", taskOutput)
+}
+
+// summary builds the standard task output map describing the last run
+func (t *Task) summary() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := map[string]interface{}{
 		"taskID":         t.ID,
 		"runID":          t.RunID,
 		"name":           t.Name,
 		"totalTime":      t.TotalTime,
 		"completedTasks": t.CompletedTasks,
-		"success":        t.GetError() == nil,
+		"success":        t.executionError == nil,
+		"status":         string(t.computeStatusLocked()),
+		"pausedTime":     t.pausedTime,
+		"changed":        len(t.changedActions) > 0,
+		"changedActions": append([]string{}, t.changedActions...),
 	}
-	if err := t.GetError(); err != nil {
-		taskOutput["error"] = err.Error()
+	for name, value := range t.outputs {
+		out[name] = value
 	}
-
-	globalContext.StoreTaskOutput(t.ID, taskOutput)
-	t.Logger.Debug("Stored task output", "taskID", t.ID, "output", taskOutput)
+	if t.executionError != nil {
+		out["error"] = t.executionError.Error()
+	}
+	if len(t.actionErrors) > 0 {
+		actionErrors := make([]string, len(t.actionErrors))
+		for i, failure := range t.actionErrors {
+			actionErrors[i] = failure.Error()
+		}
+		out["actionErrors"] = actionErrors
+	}
+	if len(t.cleanupErrors) > 0 {
+		cleanupErrors := make([]string, len(t.cleanupErrors))
+		for i, failure := range t.cleanupErrors {
+			cleanupErrors[i] = failure.Error()
+		}
+		out["cleanupErrors"] = cleanupErrors
+	}
+	return out
 }
 
 // validateParameters validates that all action parameters can be resolved.
@@ -218,6 +721,15 @@ func (t *Task) log(message string, keyvals ...interface{}) {
 	}
 }
 
+// logger returns the task's Logger, or one that discards everything when the
+// task was built without one
+func (t *Task) logger() *slog.Logger {
+	if t.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return t.Logger
+}
+
 // GetTotalTime returns the total time in a thread-safe manner
 func (t *Task) GetTotalTime() time.Duration {
 	t.mu.Lock()
@@ -266,30 +778,35 @@ func (t *Task) SetResult(result interface{}) {
 func (t *Task) GetResult() interface{} {
 	t.mu.Lock()
 	result := t.customResult
-	runID := t.RunID
-	id := t.ID
-	name := t.Name
-	total := t.TotalTime
-	completed := t.CompletedTasks
-	err := t.executionError
 	t.mu.Unlock()
 
 	if result != nil {
 		return result
 	}
+	return t.summary()
+}
 
-	out := map[string]interface{}{
-		"taskID":         id,
-		"runID":          runID,
-		"name":           name,
-		"totalTime":      total,
-		"completedTasks": completed,
-		"success":        err == nil,
-	}
-	if err != nil {
-		out["error"] = err.Error()
-	}
-	return out
+// GetStatus returns the overall status of the last run: success, degraded or failed.
+// It returns an empty status before the task has run.
+func (t *Task) GetStatus() TaskStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// GetActionErrors returns every action error recorded during the last run,
+// including those that were tolerated by a continue policy
+func (t *Task) GetActionErrors() []ActionFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ActionFailure(nil), t.actionErrors...)
+}
+
+// GetCleanupErrors returns the errors returned by Finally actions during the last run
+func (t *Task) GetCleanupErrors() []ActionFailure {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ActionFailure(nil), t.cleanupErrors...)
 }
 
 // SetError stores an execution error for the task
@@ -299,6 +816,14 @@ func (t *Task) SetError(err error) {
 	t.executionError = err
 }
 
+// GetFailedActionID returns the ID of the action that failed during the last run,
+// or an empty string if no action failed
+func (t *Task) GetFailedActionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failedActionID
+}
+
 // GetError returns the stored execution error for the task
 func (t *Task) GetError() error {
 	t.mu.Lock()
@@ -4,29 +4,93 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/ndizazzo/task-engine/internal/stopsignal"
+)
+
+var (
+	_ TaskManagerInterface = (*TaskManager)(nil)
+	_ TaskInputRunner      = (*TaskManager)(nil)
+	_ TaskStopper          = (*TaskManager)(nil)
+	_ TaskShutdowner       = (*TaskManager)(nil)
+	_ TaskPauser           = (*TaskManager)(nil)
+	_ TaskStateReporter    = (*TaskManager)(nil)
+	_ TaskProgressReporter = (*TaskManager)(nil)
+)
+
+// TaskState describes what a managed task is currently doing
+type TaskState string
+
+const (
+	TaskStateIdle    TaskState = "idle"
+	TaskStateRunning TaskState = "running"
+	TaskStatePaused  TaskState = "paused"
+	// TaskStateStopping is a task that was stopped but has not exited yet
+	TaskStateStopping TaskState = "stopping"
 )
 
-var _ TaskManagerInterface = (*TaskManager)(nil)
+// DefaultStopGracePeriod is how long a stopped task may take to wind down
+// before the processes it started are killed
+const DefaultStopGracePeriod = 10 * time.Second
+
+// taskRun tracks one run of a managed task until its goroutine exits
+type taskRun struct {
+	cancel context.CancelFunc // cancels the task's context
+	kill   context.CancelFunc // hard stop: kills the processes the run started
+	done   chan struct{}      // closed once the run has exited
+	// stopping is set by StopTask; stopRequested and killed describe the stop
+	stopping      bool
+	stopRequested time.Time
+	killed        bool
+	killTimer     *time.Timer
+}
 
 // TaskManager implements TaskManagerInterface for managing task execution
 type TaskManager struct {
 	Tasks        map[string]*Task
-	runningTasks map[string]context.CancelFunc
+	runningTasks map[string]*taskRun
 	Logger       *slog.Logger
 	mu           sync.Mutex
 	// Global context for cross-task parameter passing. This enables actions
 	// in different tasks to reference outputs from other tasks.
 	globalContext *GlobalContext
+	// events fans task lifecycle events out to subscribers
+	events *eventBus
+	// Locker grants the resource locks declared by tasks and their actions
+	// (in-process by default; use a FileResourceLocker to coordinate with
+	// other processes). Set it before running tasks.
+	Locker ResourceLocker
+	// LockTimeout bounds the wait for a task's locks; zero means DefaultLockTimeout
+	LockTimeout time.Duration
+	// HistoryLimit is how many runs per task GetRunHistory keeps; zero means
+	// DefaultHistoryLimit
+	HistoryLimit int
+	// StopGracePeriod is how long a stopped task may keep running, e.g. to let
+	// its commands exit on SIGTERM and its Finally actions run, before the
+	// processes it started are killed; zero means DefaultStopGracePeriod
+	StopGracePeriod time.Duration
+	history         *runHistory
+	// notifications delivers finished runs to the registered Notifiers
+	notifications notifications
+	// shuttingDown is set by Shutdown, which then runs shutdownHooks
+	shuttingDown  bool
+	shutdownHooks []ShutdownHook
 }
 
 func NewTaskManager(logger *slog.Logger) *TaskManager {
 	return &TaskManager{
 		Tasks:         make(map[string]*Task),
-		runningTasks:  make(map[string]context.CancelFunc),
+		runningTasks:  make(map[string]*taskRun),
 		Logger:        logger,
 		globalContext: NewGlobalContext(),
+		events:        newEventBus(),
+		history:       newRunHistory(),
+		Locker:        NewResourceLocker(),
 	}
 }
 
@@ -39,6 +103,9 @@ func (tm *TaskManager) AddTask(task *Task) error {
 	defer tm.mu.Unlock()
 
 	task.Logger = tm.Logger.With("taskID", task.ID)
+	task.mu.Lock()
+	task.managerListener = tm.handleEvent
+	task.mu.Unlock()
 	tm.Tasks[task.ID] = task
 	tm.Logger.Info("Task added", "taskID", task.ID)
 
@@ -46,39 +113,70 @@ func (tm *TaskManager) AddTask(task *Task) error {
 }
 
 func (tm *TaskManager) RunTask(taskID string) error {
+	return tm.RunTaskWithInputs(taskID, nil)
+}
+
+// RunTaskWithInputs starts a task with values for its declared Inputs.
+// Unknown, missing or invalid inputs are reported here and the task is not
+// started. Once Shutdown has been called it returns ErrShuttingDown.
+func (tm *TaskManager) RunTaskWithInputs(taskID string, inputs map[string]interface{}) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
+	if tm.shuttingDown {
+		return fmt.Errorf("cannot run task %q: %w", taskID, ErrShuttingDown)
+	}
+
 	task, exists := tm.Tasks[taskID]
 	if !exists {
 		tm.Logger.Error("Task not found", "taskID", taskID)
-		return fmt.Errorf("task %q not found", taskID)
+		return fmt.Errorf("task %q: %w", taskID, ErrTaskNotFound)
+	}
+	if _, running := tm.runningTasks[taskID]; running {
+		return fmt.Errorf("task %q: %w", taskID, ErrTaskRunning)
+	}
+	if unknown := task.unknownInputs(inputs); len(unknown) > 0 {
+		return fmt.Errorf("task %q has no inputs named %s", taskID, strings.Join(unknown, ", "))
+	}
+	if _, err := task.resolveInputs(inputs); err != nil {
+		return fmt.Errorf("task %q has invalid inputs: %w", taskID, err)
 	}
 
-	// Create a context for every task
-	ctx, cancel := context.WithCancel(context.Background())
-	tm.runningTasks[taskID] = cancel
+	// Create a context for every task. Canceling it asks the task to stop;
+	// the hard stop context outlives it by the grace period, see StopTask.
+	hardCtx, kill := context.WithCancel(WithTaskInputs(context.Background(), inputs))
+	ctx, cancel := context.WithCancel(hardCtx)
+	ctx = stopsignal.NewContext(ctx, StopSignals{Stopping: ctx.Done(), Kill: hardCtx.Done()})
+	run := &taskRun{cancel: cancel, kill: kill, done: make(chan struct{})}
+	tm.runningTasks[taskID] = run
 
 	// Capture the current global context under lock to avoid races with ResetGlobalContext.
 	// Tasks will run against this snapshot even if the manager's global context is reset later.
 	gc := tm.globalContext
+	locker, lockTimeout := tm.Locker, tm.LockTimeout
 
 	// Start every task in a goroutine
 	go func(gcSnapshot *GlobalContext) {
-		defer func() {
-			tm.mu.Lock()
-			delete(tm.runningTasks, taskID)
-			tm.mu.Unlock()
-		}()
+		defer tm.finishRun(taskID, task, run)
+
+		release, err := tm.lockResources(ctx, task, locker, lockTimeout)
+		if err != nil {
+			tm.Logger.Error("Task resource locks unavailable", "taskID", taskID, "error", err)
+			task.abortRun(ctx, err)
+			return
+		}
+		defer release()
 
 		// Run task with the captured global context for parameter resolution
-		err := task.RunWithContext(ctx, gcSnapshot)
+		err = task.RunWithContext(ctx, gcSnapshot)
 		if err != nil {
 			if ctx.Err() != nil {
 				tm.Logger.Info("Task canceled", "taskID", taskID, "error", err)
 			} else {
 				tm.Logger.Error("Task execution failed", "taskID", taskID, "error", err)
 			}
+		} else if task.GetStatus() == TaskStatusDegraded {
+			tm.Logger.Warn("Task completed in degraded state", "taskID", taskID, "actionErrors", len(task.GetActionErrors()))
 		} else {
 			tm.Logger.Info("Task completed", "taskID", taskID)
 		}
@@ -87,34 +185,257 @@ func (tm *TaskManager) RunTask(taskID string) error {
 	return nil
 }
 
+// lockResources acquires every lock the task declares, waiting at most timeout
+func (tm *TaskManager) lockResources(ctx context.Context, task *Task, locker ResourceLocker, timeout time.Duration) (func(), error) {
+	names := task.ResourceLocks()
+	if len(names) == 0 || locker == nil {
+		return func() {}, nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tm.Logger.Debug("Acquiring resource locks", "taskID", task.ID, "locks", names)
+	return locker.Lock(lockCtx, names...)
+}
+
+// finishRun is deferred by a run's goroutine: it forgets the run, releases
+// its contexts and reports a requested stop as EventTaskStopped
+func (tm *TaskManager) finishRun(taskID string, task *Task, run *taskRun) {
+	tm.mu.Lock()
+	if tm.runningTasks[taskID] == run {
+		delete(tm.runningTasks, taskID)
+	}
+	if run.killTimer != nil {
+		run.killTimer.Stop()
+	}
+	stopping, killed, stopRequested := run.stopping, run.killed, run.stopRequested
+	tm.mu.Unlock()
+
+	run.cancel()
+	run.kill()
+	if stopping {
+		task.mu.Lock()
+		runID := task.RunID
+		task.mu.Unlock()
+		tm.Logger.Info("Task stopped", "taskID", taskID, "killed", killed)
+		tm.handleEvent(TaskEvent{
+			Type:     EventTaskStopped,
+			TaskID:   taskID,
+			RunID:    runID,
+			Time:     time.Now(),
+			Duration: time.Since(stopRequested),
+			Data:     map[string]interface{}{"killed": killed},
+		})
+	}
+	close(run.done)
+}
+
+// StopTask asks a running task to stop and returns without waiting. The
+// task's context is canceled so the action in progress can return and its
+// Finally actions run; commands it started get SIGTERM. Processes still
+// running after StopGracePeriod are killed. The task is reported as
+// TaskStateStopping until it exits, when EventTaskStopped is published.
 func (tm *TaskManager) StopTask(taskID string) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	cancel, exists := tm.runningTasks[taskID]
+	run, exists := tm.runningTasks[taskID]
 	if !exists {
 		return fmt.Errorf("task %q is not running", taskID)
 	}
-
-	// Cancel the task's context
-	cancel()
-	tm.Logger.Info("Task stopped", "taskID", taskID)
-	delete(tm.runningTasks, taskID)
+	tm.stopLocked(taskID, run)
 	return nil
 }
 
+// StopTaskAndWait stops a task like StopTask and blocks until it has exited.
+// A timeout of zero waits indefinitely.
+func (tm *TaskManager) StopTaskAndWait(taskID string, timeout time.Duration) error {
+	tm.mu.Lock()
+	run, exists := tm.runningTasks[taskID]
+	if !exists {
+		tm.mu.Unlock()
+		return fmt.Errorf("task %q is not running", taskID)
+	}
+	tm.stopLocked(taskID, run)
+	tm.mu.Unlock()
+
+	if timeout <= 0 {
+		<-run.done
+		return nil
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-run.done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("task %q did not stop within %s", taskID, timeout)
+	}
+}
+
 func (tm *TaskManager) StopAllTasks() {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	for taskID, cancel := range tm.runningTasks {
-		cancel()
-		tm.Logger.Info("Task stopped", "taskID", taskID)
-		delete(tm.runningTasks, taskID)
+	for taskID, run := range tm.runningTasks {
+		tm.stopLocked(taskID, run)
+	}
+}
+
+// stopLocked cancels a run and schedules its hard stop; tm.mu must be held
+func (tm *TaskManager) stopLocked(taskID string, run *taskRun) {
+	if run.stopping {
+		return
+	}
+	grace := tm.StopGracePeriod
+	if grace <= 0 {
+		grace = DefaultStopGracePeriod
+	}
+	run.stopping = true
+	run.stopRequested = time.Now()
+	run.cancel()
+	run.killTimer = time.AfterFunc(grace, func() {
+		tm.mu.Lock()
+		run.killed = true
+		tm.mu.Unlock()
+		tm.Logger.Warn("Task did not stop within its grace period, killing its processes", "taskID", taskID, "gracePeriod", grace)
+		run.kill()
+	})
+	tm.Logger.Info("Task stop requested", "taskID", taskID, "gracePeriod", grace)
+}
+
+// PauseTask asks a running task to block before its next action. The action in
+// progress finishes first; the task's GlobalContext state is left untouched.
+func (tm *TaskManager) PauseTask(taskID string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if _, running := tm.runningTasks[taskID]; !running {
+		return fmt.Errorf("task %q is not running", taskID)
+	}
+	if err := tm.Tasks[taskID].requestPause(); err != nil {
+		return err
+	}
+	tm.Logger.Info("Task pause requested", "taskID", taskID)
+	return nil
+}
+
+// ResumeTask releases a paused task so it continues with its next action
+func (tm *TaskManager) ResumeTask(taskID string) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if _, running := tm.runningTasks[taskID]; !running {
+		return fmt.Errorf("task %q is not running", taskID)
+	}
+	if err := tm.Tasks[taskID].Resume(); err != nil {
+		return err
+	}
+	tm.Logger.Info("Task resume requested", "taskID", taskID)
+	return nil
+}
+
+// GetTaskState reports whether a task is idle, running, paused or stopping
+func (tm *TaskManager) GetTaskState(taskID string) (TaskState, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if _, exists := tm.Tasks[taskID]; !exists {
+		return "", fmt.Errorf("task %q: %w", taskID, ErrTaskNotFound)
+	}
+	run, running := tm.runningTasks[taskID]
+	if !running {
+		return TaskStateIdle, nil
+	}
+	return tm.runStateLocked(taskID, run), nil
+}
+
+// runStateLocked reports whether an in-flight run is running, paused or
+// stopping; tm.mu must be held
+func (tm *TaskManager) runStateLocked(taskID string, run *taskRun) TaskState {
+	if run.stopping {
+		return TaskStateStopping
+	}
+	if task, exists := tm.Tasks[taskID]; exists && task.IsPaused() {
+		return TaskStatePaused
+	}
+	return TaskStateRunning
+}
+
+// GetTaskProgress returns a progress snapshot for the task's current run
+func (tm *TaskManager) GetTaskProgress(taskID string) (TaskProgress, error) {
+	tm.mu.Lock()
+	task, exists := tm.Tasks[taskID]
+	tm.mu.Unlock()
+
+	if !exists {
+		return TaskProgress{}, fmt.Errorf("task %q: %w", taskID, ErrTaskNotFound)
+	}
+	return task.GetProgress(), nil
+}
+
+// handleEvent records a managed task's event in the run history, forwards it
+// to subscribers and sends notifications for finished runs
+func (tm *TaskManager) handleEvent(event TaskEvent) {
+	tm.mu.Lock()
+	limit := tm.HistoryLimit
+	globalContext := tm.globalContext
+	tm.mu.Unlock()
+
+	tm.history.record(event, limit)
+	tm.events.publish(event)
+	switch event.Type {
+	case EventTaskCompleted, EventTaskFailed, EventTaskCanceled:
+		tm.notify(event, globalContext)
 	}
 }
 
-// GetRunningTasks returns a list of currently running task IDs
+// ListTasks returns the IDs of all managed tasks, sorted
+func (tm *TaskManager) ListTasks() []string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	taskIDs := make([]string, 0, len(tm.Tasks))
+	for taskID := range tm.Tasks {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+// GetTask returns a managed task by ID
+func (tm *TaskManager) GetTask(taskID string) (*Task, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	task, exists := tm.Tasks[taskID]
+	if !exists {
+		return nil, fmt.Errorf("task %q: %w", taskID, ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+// GetRunHistory returns the task's most recent runs, oldest first. A run that
+// is still in progress has status "running".
+func (tm *TaskManager) GetRunHistory(taskID string) ([]RunRecord, error) {
+	if _, err := tm.GetTask(taskID); err != nil {
+		return nil, err
+	}
+	return tm.history.get(taskID), nil
+}
+
+// Subscribe registers a listener for lifecycle events of every task managed
+// by this TaskManager. Call the returned function to unsubscribe.
+func (tm *TaskManager) Subscribe(listener TaskEventListener) (unsubscribe func()) {
+	return tm.events.subscribe(listener)
+}
+
+// GetRunningTasks returns the IDs of the tasks in flight, sorted. Paused and
+// stopping tasks are still in flight and are included; GetTasksInState tells
+// them apart.
 func (tm *TaskManager) GetRunningTasks() []string {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
@@ -123,6 +444,27 @@ func (tm *TaskManager) GetRunningTasks() []string {
 	for taskID := range tm.runningTasks {
 		taskIDs = append(taskIDs, taskID)
 	}
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+// GetTasksInState returns the IDs of the tasks in any of the given states,
+// sorted, e.g. GetTasksInState(TaskStatePaused)
+func (tm *TaskManager) GetTasksInState(states ...TaskState) []string {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	taskIDs := []string{}
+	for taskID := range tm.Tasks {
+		state := TaskStateIdle
+		if run, running := tm.runningTasks[taskID]; running {
+			state = tm.runStateLocked(taskID, run)
+		}
+		if slices.Contains(states, state) {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	sort.Strings(taskIDs)
 	return taskIDs
 }
 
@@ -1,10 +1,15 @@
 package task_engine_test
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/command"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -209,8 +214,8 @@ func (suite *TaskManagerTestSuite) TestIsTaskRunning() {
 	// Task should be running now
 	assert.True(suite.T(), taskManager.IsTaskRunning("test-task"), "Task should be running after start")
 
-	// Stop the task
-	err = taskManager.StopTask("test-task")
+	// Stop the task and wait for it to exit
+	err = taskManager.StopTaskAndWait("test-task", time.Second)
 	require.NoError(suite.T(), err)
 
 	// Task should not be running after stop
@@ -268,3 +273,500 @@ func (suite *TaskManagerTestSuite) TestGetRunningTasksMultiple() {
 
 	taskManager.StopAllTasks()
 }
+
+func (suite *TaskManagerTestSuite) TestPauseAndResumeTask() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	second := &TestAction{}
+
+	task := &engine.Task{
+		ID:   "pausable-task",
+		Name: "Pausable Task",
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*CancelAwareAction]{ID: "first", Wrapped: &CancelAwareAction{Delay: 30 * time.Millisecond}},
+			&engine.Action[*TestAction]{ID: "second", Wrapped: second},
+		},
+	}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+
+	var mu sync.Mutex
+	var events []engine.TaskEvent
+	firstStarted := make(chan struct{})
+	unsubscribe := taskManager.Subscribe(func(e engine.TaskEvent) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+		if e.Type == engine.EventActionStarted && e.ActionID == "first" {
+			close(firstStarted)
+		}
+	})
+	defer unsubscribe()
+
+	require.NoError(suite.T(), taskManager.RunTask("pausable-task"))
+	<-firstStarted
+	require.NoError(suite.T(), taskManager.PauseTask("pausable-task"))
+	assert.Error(suite.T(), taskManager.PauseTask("pausable-task"), "Pausing twice should fail")
+
+	// The first action finishes, then the task blocks before the second one
+	time.Sleep(80 * time.Millisecond)
+	state, err := taskManager.GetTaskState("pausable-task")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), engine.TaskStatePaused, state)
+	assert.Contains(suite.T(), taskManager.GetRunningTasks(), "pausable-task", "Paused tasks are still in flight")
+	assert.Equal(suite.T(), []string{"pausable-task"}, taskManager.GetTasksInState(engine.TaskStatePaused))
+	assert.Empty(suite.T(), taskManager.GetTasksInState(engine.TaskStateRunning))
+	assert.False(suite.T(), second.Called, "Second action should not run while paused")
+	assert.Equal(suite.T(), 1, task.GetCompletedTasks())
+
+	require.NoError(suite.T(), taskManager.ResumeTask("pausable-task"))
+	require.NoError(suite.T(), taskManager.WaitForAllTasksToComplete(time.Second))
+
+	assert.True(suite.T(), second.Called)
+	assert.GreaterOrEqual(suite.T(), task.GetPausedTime(), 20*time.Millisecond)
+	state, err = taskManager.GetTaskState("pausable-task")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), engine.TaskStateIdle, state)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var resumed *engine.TaskEvent
+	types := make([]engine.TaskEventType, 0, len(events))
+	for i := range events {
+		types = append(types, events[i].Type)
+		if events[i].Type == engine.EventTaskResumed {
+			resumed = &events[i]
+		}
+	}
+	assert.Contains(suite.T(), types, engine.EventTaskPaused)
+	if assert.NotNil(suite.T(), resumed) {
+		assert.GreaterOrEqual(suite.T(), resumed.Duration, 20*time.Millisecond, "Resumed event should record the paused duration")
+	}
+	assert.Equal(suite.T(), engine.EventTaskCompleted, types[len(types)-1])
+}
+
+func (suite *TaskManagerTestSuite) TestStopPausedTask() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	second := &TestAction{}
+
+	task := &engine.Task{
+		ID: "paused-stop-task",
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*CancelAwareAction]{ID: "first", Wrapped: &CancelAwareAction{Delay: 10 * time.Millisecond}},
+			&engine.Action[*TestAction]{ID: "second", Wrapped: second},
+		},
+	}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+
+	var mu sync.Mutex
+	var types []engine.TaskEventType
+	defer taskManager.Subscribe(func(e engine.TaskEvent) {
+		mu.Lock()
+		types = append(types, e.Type)
+		mu.Unlock()
+	})()
+
+	require.NoError(suite.T(), taskManager.RunTask("paused-stop-task"))
+	require.NoError(suite.T(), taskManager.PauseTask("paused-stop-task"))
+	time.Sleep(30 * time.Millisecond)
+
+	require.NoError(suite.T(), taskManager.StopTaskAndWait("paused-stop-task", time.Second))
+	assert.False(suite.T(), second.Called)
+	assert.ErrorIs(suite.T(), task.GetError(), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(suite.T(), types, engine.EventTaskPaused)
+	assert.NotContains(suite.T(), types, engine.EventTaskResumed, "a canceled pause is not a resume")
+	assert.Contains(suite.T(), types, engine.EventTaskCanceled)
+}
+
+// stubbornAction runs a command that ignores SIGTERM, regardless of ctx
+type stubbornAction struct {
+	engine.BaseAction
+}
+
+func (a *stubbornAction) Execute(ctx context.Context) error {
+	_, err := command.NewDefaultCommandRunner().RunCommandWithContext(ctx, "sh", "-c", `trap "" TERM; exec sleep 10`)
+	return err
+}
+
+func (suite *TaskManagerTestSuite) TestStopTaskAndWait() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	task := &engine.Task{
+		ID:      "graceful-task",
+		Actions: []engine.ActionWrapper{&engine.Action[*CancelAwareAction]{ID: "wait", Wrapped: &CancelAwareAction{Delay: time.Minute}}},
+	}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+
+	stopped := make(chan engine.TaskEvent, 1)
+	defer taskManager.Subscribe(func(event engine.TaskEvent) {
+		if event.Type == engine.EventTaskStopped {
+			stopped <- event
+		}
+	})()
+
+	require.NoError(suite.T(), taskManager.RunTask("graceful-task"))
+	require.NoError(suite.T(), taskManager.StopTaskAndWait("graceful-task", time.Second))
+
+	assert.False(suite.T(), taskManager.IsTaskRunning("graceful-task"))
+	assert.ErrorIs(suite.T(), task.GetError(), context.Canceled)
+	event := <-stopped
+	assert.Equal(suite.T(), false, event.Data["killed"])
+	assert.Error(suite.T(), taskManager.StopTaskAndWait("graceful-task", time.Second), "a stopped task is no longer running")
+}
+
+func (suite *TaskManagerTestSuite) TestStopTaskKillsProcessesAfterGracePeriod() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	taskManager.StopGracePeriod = 100 * time.Millisecond
+	task := &engine.Task{
+		ID:      "stubborn-task",
+		Actions: []engine.ActionWrapper{&engine.Action[*stubbornAction]{ID: "sleep", Wrapped: &stubbornAction{}}},
+	}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+
+	stopped := make(chan engine.TaskEvent, 1)
+	defer taskManager.Subscribe(func(event engine.TaskEvent) {
+		if event.Type == engine.EventTaskStopped {
+			stopped <- event
+		}
+	})()
+
+	require.NoError(suite.T(), taskManager.RunTask("stubborn-task"))
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	require.NoError(suite.T(), taskManager.StopTask("stubborn-task"))
+
+	state, err := taskManager.GetTaskState("stubborn-task")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), engine.TaskStateStopping, state)
+	assert.True(suite.T(), taskManager.IsTaskRunning("stubborn-task"), "the task is running until its process exits")
+
+	require.NoError(suite.T(), taskManager.StopTaskAndWait("stubborn-task", 5*time.Second))
+	assert.GreaterOrEqual(suite.T(), time.Since(start), 100*time.Millisecond, "the process ignores SIGTERM until the grace period ends")
+	event := <-stopped
+	assert.Equal(suite.T(), true, event.Data["killed"])
+}
+
+// stubbornShellAction runs a shell whose background child ignores SIGTERM and
+// keeps the command's output open until it is killed
+type stubbornShellAction struct {
+	engine.BaseAction
+}
+
+func (a *stubbornShellAction) Execute(ctx context.Context) error {
+	_, err := command.NewDefaultCommandRunner().RunCommandWithContext(ctx, "sh", "-c", `trap "" TERM; sleep 10 & wait`)
+	return err
+}
+
+func (suite *TaskManagerTestSuite) TestShutdown() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	for id, delay := range map[string]time.Duration{"quick": 20 * time.Millisecond, "slow": time.Minute} {
+		require.NoError(suite.T(), taskManager.AddTask(&engine.Task{
+			ID:      id,
+			Actions: []engine.ActionWrapper{&engine.Action[*CancelAwareAction]{ID: "wait", Wrapped: &CancelAwareAction{Delay: delay}}},
+		}))
+	}
+
+	var flushed [][]engine.RunRecord
+	taskManager.OnShutdown(func(ctx context.Context) error {
+		for _, taskID := range []string{"quick", "slow"} {
+			history, err := taskManager.GetRunHistory(taskID)
+			require.NoError(suite.T(), err)
+			flushed = append(flushed, history)
+		}
+		return nil
+	})
+	taskManager.OnShutdown(func(ctx context.Context) error {
+		return errors.New("checkpoint store unavailable")
+	})
+
+	require.NoError(suite.T(), taskManager.RunTask("quick"))
+	require.NoError(suite.T(), taskManager.RunTask("slow"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	summary, err := taskManager.Shutdown(ctx)
+	assert.ErrorContains(suite.T(), err, "checkpoint store unavailable")
+
+	assert.Equal(suite.T(), []string{"quick"}, summary.Drained)
+	require.Len(suite.T(), summary.Interrupted, 1)
+	interrupted := summary.Interrupted[0]
+	assert.Equal(suite.T(), "slow", interrupted.TaskID)
+	assert.NotEmpty(suite.T(), interrupted.RunID)
+	assert.Equal(suite.T(), "wait", interrupted.Progress.CurrentActionID)
+	assert.True(suite.T(), interrupted.Exited)
+	assert.Empty(suite.T(), taskManager.GetRunningTasks())
+
+	// The hooks ran after both runs had finished
+	require.Len(suite.T(), flushed, 2)
+	assert.Equal(suite.T(), string(engine.TaskStatusSuccess), flushed[0][0].Status)
+	assert.Equal(suite.T(), engine.RunStatusCanceled, flushed[1][0].Status)
+
+	assert.ErrorIs(suite.T(), taskManager.RunTask("quick"), engine.ErrShuttingDown)
+	_, err = taskManager.Shutdown(context.Background())
+	assert.ErrorIs(suite.T(), err, engine.ErrShuttingDown)
+}
+
+func (suite *TaskManagerTestSuite) TestShutdownKillsRunningProcesses() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	taskManager.StopGracePeriod = 100 * time.Millisecond
+	require.NoError(suite.T(), taskManager.AddTask(&engine.Task{
+		ID:      "stubborn-shell",
+		Actions: []engine.ActionWrapper{&engine.Action[*stubbornShellAction]{ID: "sleep", Wrapped: &stubbornShellAction{}}},
+	}))
+	require.NoError(suite.T(), taskManager.RunTask("stubborn-shell"))
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	summary, err := taskManager.Shutdown(ctx)
+	require.NoError(suite.T(), err)
+
+	require.Len(suite.T(), summary.Interrupted, 1)
+	assert.True(suite.T(), summary.Interrupted[0].Exited, "the shell and its child are killed when the grace period ends")
+	assert.Less(suite.T(), time.Since(start), 5*time.Second)
+	assert.Empty(suite.T(), taskManager.GetRunningTasks())
+}
+
+func (suite *TaskManagerTestSuite) TestPauseResumeErrors() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	require.NoError(suite.T(), taskManager.AddTask(&engine.Task{ID: "idle-task", Actions: SingleAction}))
+
+	assert.Error(suite.T(), taskManager.PauseTask("idle-task"))
+	assert.Error(suite.T(), taskManager.ResumeTask("idle-task"))
+	assert.Error(suite.T(), taskManager.PauseTask("missing-task"))
+
+	state, err := taskManager.GetTaskState("idle-task")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), engine.TaskStateIdle, state)
+	_, err = taskManager.GetTaskState("missing-task")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TaskManagerTestSuite) TestGetTaskProgress() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	task := &engine.Task{ID: "progress-task", Actions: SingleAction}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+
+	progress, err := taskManager.GetTaskProgress("progress-task")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "progress-task", progress.TaskID)
+	assert.False(suite.T(), progress.Running)
+	assert.Equal(suite.T(), len(SingleAction), progress.TotalActions)
+
+	_, err = taskManager.GetTaskProgress("missing-task")
+	assert.Error(suite.T(), err)
+}
+
+// exclusiveProbeAction records the highest number of probes running at once
+type exclusiveProbeAction struct {
+	engine.BaseAction
+	running *atomic.Int32
+	maxSeen *atomic.Int32
+}
+
+func (a *exclusiveProbeAction) Execute(ctx context.Context) error {
+	n := a.running.Add(1)
+	defer a.running.Add(-1)
+	for {
+		seen := a.maxSeen.Load()
+		if n <= seen || a.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	return nil
+}
+
+func (suite *TaskManagerTestSuite) TestResourceLocksSerializeTasks() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	var running, maxSeen atomic.Int32
+
+	// One task declares the lock itself, the other through its action
+	first := &engine.Task{
+		ID:    "apt-task-1",
+		Locks: []string{"apt"},
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*exclusiveProbeAction]{ID: "probe", Wrapped: &exclusiveProbeAction{running: &running, maxSeen: &maxSeen}},
+		},
+	}
+	second := &engine.Task{
+		ID: "apt-task-2",
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*exclusiveProbeAction]{ID: "probe", Locks: []string{"apt"}, Wrapped: &exclusiveProbeAction{running: &running, maxSeen: &maxSeen}},
+		},
+	}
+	assert.Equal(suite.T(), []string{"apt"}, second.ResourceLocks())
+
+	require.NoError(suite.T(), taskManager.AddTask(first))
+	require.NoError(suite.T(), taskManager.AddTask(second))
+	require.NoError(suite.T(), taskManager.RunTask("apt-task-1"))
+	require.NoError(suite.T(), taskManager.RunTask("apt-task-2"))
+	require.NoError(suite.T(), taskManager.WaitForAllTasksToComplete(time.Second))
+
+	assert.Equal(suite.T(), int32(1), maxSeen.Load(), "Tasks sharing a lock must not overlap")
+	assert.Equal(suite.T(), engine.TaskStatusSuccess, first.GetStatus())
+	assert.Equal(suite.T(), engine.TaskStatusSuccess, second.GetStatus())
+}
+
+func (suite *TaskManagerTestSuite) TestResourceLockTimeout() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	taskManager.LockTimeout = 20 * time.Millisecond
+
+	unlock, err := taskManager.Locker.Lock(context.Background(), "compose:/srv/app")
+	require.NoError(suite.T(), err)
+	defer unlock()
+
+	action := &TestAction{}
+	task := &engine.Task{
+		ID:      "compose-task",
+		Locks:   []string{"compose:/srv/app"},
+		Actions: []engine.ActionWrapper{&engine.Action[*TestAction]{ID: "compose", Wrapped: action}},
+	}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+
+	failed := make(chan engine.TaskEvent, 1)
+	unsubscribe := taskManager.Subscribe(func(e engine.TaskEvent) {
+		if e.Type == engine.EventTaskFailed {
+			failed <- e
+		}
+	})
+	defer unsubscribe()
+
+	require.NoError(suite.T(), taskManager.RunTask("compose-task"))
+	require.NoError(suite.T(), taskManager.PauseTask("compose-task"), "a run waiting for its locks can be paused")
+	select {
+	case e := <-failed:
+		assert.Contains(suite.T(), e.Error, `resource lock "compose:/srv/app"`)
+	case <-time.After(time.Second):
+		suite.FailNow("task did not fail")
+	}
+
+	assert.False(suite.T(), action.Called)
+	assert.False(suite.T(), task.IsPaused(), "the pause ends with the run that never started")
+	assert.ErrorIs(suite.T(), task.GetError(), engine.ErrLockTimeout)
+	var lockErr *engine.LockError
+	require.ErrorAs(suite.T(), task.GetError(), &lockErr)
+	assert.Equal(suite.T(), "compose:/srv/app", lockErr.Resource)
+	assert.Equal(suite.T(), engine.TaskStatusFailed, task.GetStatus())
+}
+
+func (suite *TaskManagerTestSuite) TestRunHistory() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+	taskManager.HistoryLimit = 2
+
+	action := &TestAction{}
+	task := &engine.Task{
+		ID:      "history-task",
+		Actions: []engine.ActionWrapper{&engine.Action[*TestAction]{ID: "step", Wrapped: action}},
+	}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+	assert.Equal(suite.T(), []string{"history-task"}, taskManager.ListTasks())
+
+	for i := 0; i < 3; i++ {
+		action.ShouldFail = i == 2
+		require.NoError(suite.T(), taskManager.RunTask("history-task"))
+		require.NoError(suite.T(), taskManager.WaitForAllTasksToComplete(time.Second))
+	}
+
+	runs, err := taskManager.GetRunHistory("history-task")
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), runs, 2)
+	assert.Equal(suite.T(), string(engine.TaskStatusSuccess), runs[0].Status)
+	assert.Equal(suite.T(), string(engine.TaskStatusFailed), runs[1].Status)
+	assert.Contains(suite.T(), runs[1].Error, "simulated failure")
+	assert.NotEqual(suite.T(), runs[0].RunID, runs[1].RunID)
+	assert.False(suite.T(), runs[1].FinishedAt.Before(runs[1].StartedAt))
+
+	_, err = taskManager.GetRunHistory("missing")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TaskManagerTestSuite) TestNotifiers() {
+	taskManager := engine.NewTaskManager(noOpLogger)
+
+	var mu sync.Mutex
+	var all, failures []engine.Notification
+	collect := func(into *[]engine.Notification) engine.Notifier {
+		return engine.NotifierFunc(func(ctx context.Context, n engine.Notification) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*into = append(*into, n)
+			return nil
+		})
+	}
+	taskManager.AddNotifier(collect(&all), engine.NotifyTasks("notify-*"), engine.NotifyOutputs("changed"), engine.NotifyActionOutputs("step"))
+	remove := taskManager.AddNotifier(collect(&failures), engine.NotifyFailuresOnly())
+
+	action := &TestAction{}
+	task := &engine.Task{
+		ID:      "notify-task",
+		Actions: []engine.ActionWrapper{&engine.Action[*TestAction]{ID: "step", Wrapped: action}},
+	}
+	other := &engine.Task{ID: "other-task", Actions: SingleAction}
+	require.NoError(suite.T(), taskManager.AddTask(task))
+	require.NoError(suite.T(), taskManager.AddTask(other))
+
+	for _, fail := range []bool{false, true} {
+		action.ShouldFail = fail
+		require.NoError(suite.T(), taskManager.RunTask("notify-task"))
+		require.NoError(suite.T(), taskManager.RunTask("other-task"))
+		require.NoError(suite.T(), taskManager.WaitForAllTasksToComplete(time.Second))
+	}
+	remove()
+	require.NoError(suite.T(), taskManager.RunTask("notify-task"))
+	require.NoError(suite.T(), taskManager.WaitForAllTasksToComplete(time.Second))
+	require.NoError(suite.T(), taskManager.WaitForNotifications(time.Second))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(suite.T(), all, 3, "only notify-* tasks are reported")
+	assert.Equal(suite.T(), "notify-task", all[0].TaskID)
+	assert.Equal(suite.T(), string(engine.TaskStatusSuccess), all[0].Status)
+	assert.NotEmpty(suite.T(), all[0].RunID)
+	assert.Contains(suite.T(), all[0].Outputs, "changed")
+	assert.NotContains(suite.T(), all[0].Outputs, "completedTasks")
+
+	require.Len(suite.T(), failures, 1)
+	assert.Equal(suite.T(), string(engine.TaskStatusFailed), failures[0].Status)
+	assert.Contains(suite.T(), failures[0].Error, "simulated failure")
+}
+
+func (suite *TaskManagerTestSuite) TestShutdownWhileNotifying() {
+	for i := 0; i < 20; i++ {
+		taskManager := engine.NewTaskManager(noOpLogger)
+		var delivered atomic.Int32
+		taskManager.AddNotifier(engine.NotifierFunc(func(ctx context.Context, n engine.Notification) error {
+			delivered.Add(1)
+			return nil
+		}))
+		require.NoError(suite.T(), taskManager.AddTask(&engine.Task{ID: "quick", Actions: SingleAction}))
+		require.NoError(suite.T(), taskManager.RunTask("quick"))
+
+		_, err := taskManager.Shutdown(context.Background())
+		require.NoError(suite.T(), err)
+		afterShutdown := delivered.Load()
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(suite.T(), afterShutdown, delivered.Load(), "no notification is delivered after Shutdown returns")
+	}
+}
+
+func TestNotificationFromOutput(t *testing.T) {
+	n := engine.NotificationFromOutput(map[string]interface{}{
+		"taskID":    "deploy",
+		"runID":     "run-1",
+		"status":    "degraded",
+		"totalTime": 3 * time.Second,
+		"error":     "boom",
+		"changed":   true,
+	}, "changed", "missing")
+
+	assert.Equal(t, engine.Notification{
+		TaskID:   "deploy",
+		RunID:    "run-1",
+		Status:   "degraded",
+		Duration: 3 * time.Second,
+		Error:    "boom",
+		Outputs:  map[string]interface{}{"changed": true},
+	}, n)
+}
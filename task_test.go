@@ -3,6 +3,7 @@ package task_engine_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"sync"
 	"testing"
@@ -397,3 +398,449 @@ func (suite *TaskTestSuite) TestTask_SimpleResultAggregation() {
 		suite.T().Fatal("unexpected result type")
 	}
 }
+
+func (suite *TaskTestSuite) TestRun_ErrorPolicyContinue() {
+	logger := mocks.NewDiscardLogger()
+	action2Executed := false
+	pruneErr := errors.New("prune failed")
+
+	prune := newMockAction(logger, "prune-images", pruneErr, nil).(*engine.Action[*mockAction])
+	prune.ErrorPolicy = engine.ErrorPolicyContinue
+
+	task := &engine.Task{
+		ID:     "continue-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			prune,
+			newMockAction(logger, "action2", nil, &action2Executed),
+		},
+	}
+
+	gc := engine.NewGlobalContext()
+	err := task.RunWithContext(context.Background(), gc)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), action2Executed, "Action 2 should run after a tolerated failure")
+	assert.Equal(suite.T(), engine.TaskStatusSuccess, task.GetStatus())
+	assert.NoError(suite.T(), task.GetError())
+	failures := task.GetActionErrors()
+	if assert.Len(suite.T(), failures, 1) {
+		assert.Equal(suite.T(), "prune-images", failures[0].ActionID)
+		assert.ErrorIs(suite.T(), failures[0], pruneErr)
+	}
+
+	actionErrors, err := engine.TaskOutputFieldAs[[]string](gc, "continue-task", "actionErrors")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"action prune-images: prune failed"}, actionErrors)
+}
+
+func (suite *TaskTestSuite) TestRun_ErrorPolicyContinueDegraded() {
+	logger := mocks.NewDiscardLogger()
+	cleanup := newMockAction(logger, "remove-temp", errors.New("busy"), nil).(*engine.Action[*mockAction])
+	cleanup.ErrorPolicy = engine.ErrorPolicyContinueDegraded
+
+	task := &engine.Task{
+		ID:     "degraded-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			newMockAction(logger, "action1", nil, nil),
+			cleanup,
+			newMockAction(logger, "action3", nil, nil),
+		},
+	}
+
+	gc := engine.NewGlobalContext()
+	assert.NoError(suite.T(), task.RunWithContext(context.Background(), gc))
+	assert.Equal(suite.T(), engine.TaskStatusDegraded, task.GetStatus())
+	assert.Equal(suite.T(), 2, task.GetCompletedTasks())
+
+	status, err := engine.TaskOutputFieldAs[string](gc, "degraded-task", "status")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "degraded", status)
+	success, err := engine.TaskOutputFieldAs[bool](gc, "degraded-task", "success")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), success)
+}
+
+func (suite *TaskTestSuite) TestRun_FailPolicyRecordsFailedStatus() {
+	logger := mocks.NewDiscardLogger()
+	tolerated := newMockAction(logger, "tolerated", errors.New("first"), nil).(*engine.Action[*mockAction])
+	tolerated.ErrorPolicy = engine.ErrorPolicyContinueDegraded
+
+	task := &engine.Task{
+		ID:     "failed-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			tolerated,
+			newMockAction(logger, "fatal", errors.New("second"), nil),
+		},
+	}
+
+	err := task.Run(context.Background())
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), engine.TaskStatusFailed, task.GetStatus())
+	assert.Len(suite.T(), task.GetActionErrors(), 2)
+	assert.Equal(suite.T(), "fatal", task.GetFailedActionID())
+
+	result, ok := task.GetResult().(map[string]interface{})
+	if assert.True(suite.T(), ok) {
+		assert.Equal(suite.T(), "failed", result["status"])
+		assert.Len(suite.T(), result["actionErrors"], 2)
+	}
+}
+
+func (suite *TaskTestSuite) TestRun_RerunAfterFailureStartsClean() {
+	logger := mocks.NewDiscardLogger()
+	action := newMockAction(logger, "deploy", errors.New("boom"), nil).(*engine.Action[*mockAction])
+	var events []engine.TaskEventType
+	task := &engine.Task{
+		ID:            "rerun-task",
+		Logger:        logger,
+		Actions:       []engine.ActionWrapper{action},
+		EventListener: func(event engine.TaskEvent) { events = append(events, event.Type) },
+	}
+
+	gc := engine.NewGlobalContext()
+	assert.Error(suite.T(), task.RunWithContext(context.Background(), gc))
+	assert.Equal(suite.T(), engine.TaskStatusFailed, task.GetStatus())
+
+	action.Wrapped.ReturnError = nil
+	events = nil
+	assert.NoError(suite.T(), task.RunWithContext(context.Background(), gc))
+	assert.Equal(suite.T(), engine.TaskStatusSuccess, task.GetStatus())
+	assert.NoError(suite.T(), task.GetError())
+	assert.Empty(suite.T(), task.GetActionErrors())
+	assert.Contains(suite.T(), events, engine.EventTaskCompleted)
+
+	success, err := engine.TaskOutputFieldAs[bool](gc, "rerun-task", "success")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), success)
+	status, err := engine.TaskOutputFieldAs[string](gc, "rerun-task", "status")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "success", status)
+}
+
+func (suite *TaskTestSuite) TestPause_RequiresARunningTask() {
+	logger := mocks.NewDiscardLogger()
+	executed := false
+	task := &engine.Task{
+		ID:      "idle-task",
+		Logger:  logger,
+		Actions: []engine.ActionWrapper{newMockAction(logger, "action1", nil, &executed)},
+	}
+
+	assert.ErrorContains(suite.T(), task.Pause(), "not running")
+	assert.False(suite.T(), task.IsPaused())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(suite.T(), task.Run(ctx), "a rejected pause must not block the next run")
+	assert.True(suite.T(), executed)
+}
+
+func (suite *TaskTestSuite) TestRun_PrerequisiteAbortsDespiteContinuePolicy() {
+	logger := mocks.NewDiscardLogger()
+	action2Executed := false
+	prereq := newMockAction(logger, "prereq", engine.ErrPrerequisiteNotMet, nil).(*engine.Action[*mockAction])
+	prereq.ErrorPolicy = engine.ErrorPolicyContinue
+
+	task := &engine.Task{
+		ID:     "prereq-policy-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			prereq,
+			newMockAction(logger, "action2", nil, &action2Executed),
+		},
+	}
+
+	err := task.Run(context.Background())
+	assert.ErrorIs(suite.T(), err, engine.ErrPrerequisiteNotMet)
+	assert.False(suite.T(), action2Executed)
+	assert.Equal(suite.T(), engine.TaskStatusFailed, task.GetStatus())
+}
+
+// ctxProbeAction records whether its context was already done when it ran
+type ctxProbeAction struct {
+	engine.BaseAction
+	Ran         bool
+	CtxErr      error
+	HasDeadline bool
+	ReturnError error
+}
+
+func (a *ctxProbeAction) Execute(ctx context.Context) error {
+	a.Ran = true
+	a.CtxErr = ctx.Err()
+	_, a.HasDeadline = ctx.Deadline()
+	return a.ReturnError
+}
+
+func (suite *TaskTestSuite) TestFinally_RunsOnSuccessAndFailure() {
+	logger := mocks.NewDiscardLogger()
+
+	for _, mainErr := range []error{nil, errors.New("main failed")} {
+		probe := &ctxProbeAction{BaseAction: engine.BaseAction{Logger: logger}}
+		task := &engine.Task{
+			ID:      "finally-task",
+			Logger:  logger,
+			Actions: []engine.ActionWrapper{newMockAction(logger, "main", mainErr, nil)},
+			Finally: []engine.ActionWrapper{&engine.Action[*ctxProbeAction]{ID: "cleanup", Wrapped: probe}},
+		}
+
+		err := task.Run(context.Background())
+		if mainErr != nil {
+			assert.ErrorIs(suite.T(), err, mainErr)
+		} else {
+			assert.NoError(suite.T(), err)
+		}
+		assert.True(suite.T(), probe.Ran, "Finally action should run")
+		assert.Empty(suite.T(), task.GetCleanupErrors())
+	}
+}
+
+func (suite *TaskTestSuite) TestFinally_FreshContextAfterCancellation() {
+	logger := mocks.NewDiscardLogger()
+	probe := &ctxProbeAction{BaseAction: engine.BaseAction{Logger: logger}}
+
+	task := &engine.Task{
+		ID:     "finally-cancel-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*CancelAwareAction]{ID: "blocking", Wrapped: &CancelAwareAction{Delay: time.Second}},
+		},
+		Finally:        []engine.ActionWrapper{&engine.Action[*ctxProbeAction]{ID: "stop-containers", Wrapped: probe}},
+		FinallyTimeout: 5 * time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	err := task.Run(ctx)
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+	assert.True(suite.T(), probe.Ran)
+	assert.NoError(suite.T(), probe.CtxErr, "Finally should run on a live context")
+	assert.True(suite.T(), probe.HasDeadline, "Finally context should carry its own timeout")
+}
+
+func (suite *TaskTestSuite) TestFinally_ErrorsReportedSeparately() {
+	logger := mocks.NewDiscardLogger()
+	mainErr := errors.New("main failed")
+	cleanupErr := errors.New("lock release failed")
+	second := &ctxProbeAction{BaseAction: engine.BaseAction{Logger: logger}}
+
+	task := &engine.Task{
+		ID:      "finally-errors-task",
+		Logger:  logger,
+		Actions: []engine.ActionWrapper{newMockAction(logger, "main", mainErr, nil)},
+		Finally: []engine.ActionWrapper{
+			&engine.Action[*ctxProbeAction]{ID: "release-lock", Wrapped: &ctxProbeAction{BaseAction: engine.BaseAction{Logger: logger}, ReturnError: cleanupErr}},
+			&engine.Action[*ctxProbeAction]{ID: "remove-scratch", Wrapped: second},
+		},
+	}
+
+	gc := engine.NewGlobalContext()
+	err := task.RunWithContext(context.Background(), gc)
+	assert.ErrorIs(suite.T(), err, mainErr)
+	assert.NotErrorIs(suite.T(), err, cleanupErr)
+	assert.ErrorIs(suite.T(), task.GetError(), mainErr)
+	assert.True(suite.T(), second.Ran, "Later finally actions run after a cleanup failure")
+
+	cleanupErrors := task.GetCleanupErrors()
+	if assert.Len(suite.T(), cleanupErrors, 1) {
+		assert.Equal(suite.T(), "release-lock", cleanupErrors[0].ActionID)
+		assert.ErrorIs(suite.T(), cleanupErrors[0], cleanupErr)
+	}
+	reported, err := engine.TaskOutputFieldAs[[]string](gc, "finally-errors-task", "cleanupErrors")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), reported, 1)
+}
+
+func (suite *TaskTestSuite) TestFinally_CleanupFailureDegradesSuccessfulTask() {
+	logger := mocks.NewDiscardLogger()
+	task := &engine.Task{
+		ID:      "finally-degraded-task",
+		Logger:  logger,
+		Actions: []engine.ActionWrapper{newMockAction(logger, "main", nil, nil)},
+		Finally: []engine.ActionWrapper{newMockAction(logger, "cleanup", errors.New("busy"), nil)},
+	}
+
+	assert.NoError(suite.T(), task.Run(context.Background()))
+	assert.Equal(suite.T(), engine.TaskStatusDegraded, task.GetStatus())
+}
+
+// changeReportingAction reports a fixed "changed" flag in its output
+type changeReportingAction struct {
+	engine.BaseAction
+	Changed bool
+}
+
+func (a *changeReportingAction) Execute(ctx context.Context) error {
+	return nil
+}
+
+func (a *changeReportingAction) GetOutput() interface{} {
+	return map[string]interface{}{"success": true, "changed": a.Changed}
+}
+
+func (suite *TaskTestSuite) TestSummary_ReportsChangedActions() {
+	logger := mocks.NewDiscardLogger()
+	var events []engine.TaskEvent
+	task := &engine.Task{
+		ID:     "changed-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*changeReportingAction]{ID: "unchanged", Wrapped: &changeReportingAction{}},
+			&engine.Action[*changeReportingAction]{ID: "changed", Wrapped: &changeReportingAction{Changed: true}},
+			newMockAction(logger, "silent", nil, nil),
+		},
+		EventListener: func(e engine.TaskEvent) { events = append(events, e) },
+	}
+
+	suite.Require().NoError(task.Run(context.Background()))
+	result := task.GetResult().(map[string]interface{})
+	suite.Equal(true, result["changed"])
+	suite.Equal([]string{"changed"}, result["changedActions"])
+
+	var completed []map[string]interface{}
+	for _, e := range events {
+		if e.Type == engine.EventActionCompleted {
+			completed = append(completed, e.Data)
+		}
+	}
+	suite.Equal([]map[string]interface{}{
+		{"changed": false},
+		{"changed": true},
+		nil,
+	}, completed)
+}
+
+// steppedAction reports progress and then blocks until released
+type steppedAction struct {
+	engine.BaseAction
+	Current, Total int64
+	Started        chan struct{}
+	Release        chan struct{}
+}
+
+func (a *steppedAction) Execute(ctx context.Context) error {
+	engine.ReportProgress(ctx, a.Current, a.Total, "items")
+	if a.Started != nil {
+		a.Started <- struct{}{}
+	}
+	if a.Release != nil {
+		<-a.Release
+	}
+	return nil
+}
+
+func (suite *TaskTestSuite) TestGetProgress_TracksCurrentActionAndETA() {
+	logger := mocks.NewDiscardLogger()
+	stepped := &steppedAction{Current: 1, Total: 4}
+
+	task := &engine.Task{
+		ID:     "progress-task",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			newMockAction(logger, "first", nil, nil),
+			&engine.Action[*steppedAction]{ID: "second", Name: "Second Step", Wrapped: stepped},
+			newMockAction(logger, "third", nil, nil),
+		},
+	}
+
+	idle := task.GetProgress()
+	assert.False(suite.T(), idle.Running)
+	assert.Equal(suite.T(), 3, idle.TotalActions)
+
+	// First run has no history, so there is no ETA
+	stepped.Started = make(chan struct{})
+	stepped.Release = make(chan struct{})
+	var progressEvents []engine.TaskEvent
+	task.EventListener = func(e engine.TaskEvent) {
+		if e.Type == engine.EventActionProgress {
+			progressEvents = append(progressEvents, e)
+		}
+	}
+	done := make(chan error)
+	go func() { done <- task.Run(context.Background()) }()
+
+	<-stepped.Started
+	progress := task.GetProgress()
+	assert.True(suite.T(), progress.Running)
+	assert.Equal(suite.T(), "second", progress.CurrentActionID)
+	assert.Equal(suite.T(), "Second Step", progress.CurrentActionName)
+	assert.Equal(suite.T(), 2, progress.CurrentIndex)
+	assert.Equal(suite.T(), 1, progress.CompletedActions)
+	assert.False(suite.T(), progress.ETAKnown, "third has never run")
+	if assert.NotNil(suite.T(), progress.Action) {
+		assert.Equal(suite.T(), engine.ActionProgress{Current: 1, Total: 4, Unit: "items"}, *progress.Action)
+	}
+	assert.Contains(suite.T(), progress.String(), "step 2 of 3: Second Step")
+	close(stepped.Release)
+	assert.NoError(suite.T(), <-done)
+
+	if assert.Len(suite.T(), progressEvents, 1) {
+		assert.Equal(suite.T(), "second", progressEvents[0].ActionID)
+		assert.Equal(suite.T(), int64(1), progressEvents[0].Data["current"])
+	}
+	assert.False(suite.T(), task.GetProgress().Running)
+
+	// Second run uses the recorded durations
+	stepped.Started = make(chan struct{})
+	stepped.Release = make(chan struct{})
+	go func() { done <- task.Run(context.Background()) }()
+	<-stepped.Started
+	progress = task.GetProgress()
+	assert.True(suite.T(), progress.ETAKnown)
+	assert.GreaterOrEqual(suite.T(), progress.ETA, time.Duration(0))
+	close(stepped.Release)
+	assert.NoError(suite.T(), <-done)
+}
+
+func (suite *TaskTestSuite) TestDeclaredOutputs_UsableByOtherTasks() {
+	logger := mocks.NewDiscardLogger()
+	gc := engine.NewGlobalContext()
+	build := &engine.Task{
+		ID:     "build",
+		Logger: logger,
+		Actions: []engine.ActionWrapper{
+			&engine.Action[*outputAction]{ID: "compile", Wrapped: &outputAction{Output: map[string]interface{}{"tag": "app:1.3"}}},
+		},
+		Outputs: map[string]engine.ActionParameter{
+			"imageTag": engine.ActionOutputField("compile", "tag"),
+			"channel":  engine.StaticParameter{Value: "stable"},
+		},
+	}
+	suite.Require().NoError(build.RunWithContext(context.Background(), gc))
+
+	output := build.GetResult().(map[string]interface{})
+	suite.Equal("app:1.3", output["imageTag"])
+	suite.Equal("stable", output["channel"])
+	suite.Equal("build", output["taskID"])
+
+	tag, err := engine.TaskOutputField("build", "imageTag").Resolve(context.Background(), gc)
+	suite.Require().NoError(err)
+	suite.Equal("app:1.3", tag)
+}
+
+func (suite *TaskTestSuite) TestDeclaredOutputs_FailuresFailTheRun() {
+	for name, param := range map[string]engine.ActionParameter{
+		"imageTag": engine.ActionOutputField("compile", "missing"),
+		"status":   engine.StaticParameter{Value: "overridden"},
+	} {
+		task := &engine.Task{
+			ID:     "build",
+			Logger: mocks.NewDiscardLogger(),
+			Actions: []engine.ActionWrapper{
+				&engine.Action[*outputAction]{ID: "compile", Wrapped: &outputAction{Output: map[string]interface{}{"tag": "app:1.3"}}},
+			},
+			Outputs: map[string]engine.ActionParameter{name: param},
+		}
+		err := task.Run(context.Background())
+		suite.ErrorContains(err, fmt.Sprintf("failed to resolve output %q", name))
+		suite.Equal(engine.TaskStatusFailed, task.GetStatus())
+		suite.Equal(string(engine.TaskStatusFailed), task.GetResult().(map[string]interface{})["status"])
+	}
+}
@@ -0,0 +1,360 @@
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ndizazzo/task-engine/command"
+	"github.com/stretchr/testify/mock"
+)
+
+// ErrUnmatchedCommand is returned by ReplayCommandRunner for commands that
+// have no matching recorded interaction
+var ErrUnmatchedCommand = errors.New("no recorded interaction matches command")
+
+// CommandInteraction is one recorded command and its outcome
+type CommandInteraction struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+	// Output is the combined output, or stdout for RunCommandWithOptions calls
+	Output   string `json:"output"`
+	Stderr   string `json:"stderr,omitempty"`
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// String renders the interaction's command line for error messages
+func (i CommandInteraction) String() string {
+	s := strings.TrimSpace(i.Command + " " + strings.Join(i.Args, " "))
+	if i.Dir != "" {
+		s += " (in " + i.Dir + ")"
+	}
+	return s
+}
+
+func (i CommandInteraction) matches(name string, args []string, dir string) bool {
+	return i.Command == name && slices.Equal(i.Args, args) && i.Dir == dir
+}
+
+// commandFixture is the on-disk fixture format
+type commandFixture struct {
+	Interactions []CommandInteraction `json:"interactions"`
+}
+
+// LoadCommandFixture reads the interactions saved by a RecordingCommandRunner
+func LoadCommandFixture(path string) ([]CommandInteraction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read command fixture %s: %w", path, err)
+	}
+	var fixture commandFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse command fixture %s: %w", path, err)
+	}
+	return fixture.Interactions, nil
+}
+
+// RecordingCommandRunner runs commands through a real runner and records every
+// call so it can be saved as a fixture for ReplayCommandRunner
+type RecordingCommandRunner struct {
+	runner command.CommandRunner
+	path   string
+
+	mu           sync.Mutex
+	interactions []CommandInteraction
+}
+
+// NewRecordingCommandRunner records the commands run through runner (a
+// command.DefaultCommandRunner when nil); Save writes them to path
+func NewRecordingCommandRunner(path string, runner command.CommandRunner) *RecordingCommandRunner {
+	if runner == nil {
+		runner = command.NewDefaultCommandRunner()
+	}
+	return &RecordingCommandRunner{runner: runner, path: path}
+}
+
+// Interactions returns a copy of the recorded interactions
+func (r *RecordingCommandRunner) Interactions() []CommandInteraction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.interactions)
+}
+
+// Save writes the recorded interactions to the fixture file
+func (r *RecordingCommandRunner) Save() error {
+	data, err := json.MarshalIndent(commandFixture{Interactions: r.Interactions()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write command fixture %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// RunCommand runs and records a command
+func (r *RecordingCommandRunner) RunCommand(cmd string, args ...string) (string, error) {
+	output, err := r.runner.RunCommand(cmd, args...)
+	r.record(cmd, args, "", output, "", err, exitCode(err))
+	return output, err
+}
+
+// RunCommandWithContext runs and records a command
+func (r *RecordingCommandRunner) RunCommandWithContext(ctx context.Context, cmd string, args ...string) (string, error) {
+	output, err := r.runner.RunCommandWithContext(ctx, cmd, args...)
+	r.record(cmd, args, "", output, "", err, exitCode(err))
+	return output, err
+}
+
+// RunCommandInDir runs and records a command
+func (r *RecordingCommandRunner) RunCommandInDir(workingDir string, cmd string, args ...string) (string, error) {
+	output, err := r.runner.RunCommandInDir(workingDir, cmd, args...)
+	r.record(cmd, args, workingDir, output, "", err, exitCode(err))
+	return output, err
+}
+
+// RunCommandInDirWithContext runs and records a command
+func (r *RecordingCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, cmd string, args ...string) (string, error) {
+	output, err := r.runner.RunCommandInDirWithContext(ctx, workingDir, cmd, args...)
+	r.record(cmd, args, workingDir, output, "", err, exitCode(err))
+	return output, err
+}
+
+// RunCommandStream streams and records a command
+func (r *RecordingCommandRunner) RunCommandStream(ctx context.Context, opts command.StreamOptions, cmd string, args ...string) (string, error) {
+	output, err := command.RunStreaming(ctx, r.runner, opts, cmd, args...)
+	r.record(cmd, args, opts.WorkingDir, output, "", err, exitCode(err))
+	return output, err
+}
+
+// RunCommandWithOptions runs and records a command; Env and Stdin are not recorded
+func (r *RecordingCommandRunner) RunCommandWithOptions(ctx context.Context, cmd command.Cmd) (command.CommandResult, error) {
+	result, err := command.RunWithOptions(ctx, r.runner, cmd)
+	r.record(cmd.Name, cmd.Args, cmd.Dir, result.Stdout, result.Stderr, err, result.ExitCode)
+	return result, err
+}
+
+func (r *RecordingCommandRunner) record(cmd string, args []string, dir, output, stderr string, err error, code int) {
+	interaction := CommandInteraction{
+		Command:  cmd,
+		Args:     slices.Clone(args),
+		Dir:      dir,
+		Output:   output,
+		Stderr:   stderr,
+		ExitCode: code,
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+}
+
+// exitCode derives a process exit code from a legacy runner error
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	default:
+		return -1
+	}
+}
+
+// ReplayMode controls how ReplayCommandRunner matches calls to interactions
+type ReplayMode int
+
+const (
+	// ReplayStrict requires commands to arrive in the recorded order
+	ReplayStrict ReplayMode = iota
+	// ReplayLenient serves the first unused interaction that matches, in any order
+	ReplayLenient
+)
+
+// ReplayCommandRunner serves recorded interactions instead of running
+// commands. Calls are matched on command, arguments and working directory;
+// each interaction is used at most once.
+type ReplayCommandRunner struct {
+	mode ReplayMode
+
+	mu           sync.Mutex
+	interactions []CommandInteraction
+	used         []bool
+	next         int
+	unmatched    []string
+}
+
+// NewReplayCommandRunner replays the given interactions
+func NewReplayCommandRunner(interactions []CommandInteraction, mode ReplayMode) *ReplayCommandRunner {
+	return &ReplayCommandRunner{
+		mode:         mode,
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// LoadReplayCommandRunner replays the fixture file at path
+func LoadReplayCommandRunner(path string, mode ReplayMode) (*ReplayCommandRunner, error) {
+	interactions, err := LoadCommandFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayCommandRunner(interactions, mode), nil
+}
+
+// RunCommand replays a recorded command
+func (r *ReplayCommandRunner) RunCommand(cmd string, args ...string) (string, error) {
+	return r.replayCombined(cmd, args, "")
+}
+
+// RunCommandWithContext replays a recorded command
+func (r *ReplayCommandRunner) RunCommandWithContext(ctx context.Context, cmd string, args ...string) (string, error) {
+	return r.replayCombined(cmd, args, "")
+}
+
+// RunCommandInDir replays a recorded command
+func (r *ReplayCommandRunner) RunCommandInDir(workingDir string, cmd string, args ...string) (string, error) {
+	return r.replayCombined(cmd, args, workingDir)
+}
+
+// RunCommandInDirWithContext replays a recorded command
+func (r *ReplayCommandRunner) RunCommandInDirWithContext(ctx context.Context, workingDir string, cmd string, args ...string) (string, error) {
+	return r.replayCombined(cmd, args, workingDir)
+}
+
+// RunCommandStream replays a recorded command, delivering its output to the
+// handler and writers in opts
+func (r *ReplayCommandRunner) RunCommandStream(ctx context.Context, opts command.StreamOptions, cmd string, args ...string) (string, error) {
+	interaction, err := r.match(cmd, args, opts.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+	deliver(opts.OnLine, opts.Stdout, command.Stdout, interaction.Output)
+	deliver(opts.OnLine, opts.Stderr, command.Stderr, interaction.Stderr)
+	return interaction.Output + interaction.Stderr, interaction.err()
+}
+
+// RunCommandWithOptions replays a recorded command with its separate streams and exit code
+func (r *ReplayCommandRunner) RunCommandWithOptions(ctx context.Context, cmd command.Cmd) (command.CommandResult, error) {
+	interaction, err := r.match(cmd.Name, cmd.Args, cmd.Dir)
+	if err != nil {
+		return command.CommandResult{ExitCode: -1}, err
+	}
+	deliver(cmd.OnLine, nil, command.Stdout, interaction.Output)
+	deliver(cmd.OnLine, nil, command.Stderr, interaction.Stderr)
+	return command.CommandResult{
+		Stdout:   interaction.Output,
+		Stderr:   interaction.Stderr,
+		ExitCode: interaction.ExitCode,
+	}, interaction.err()
+}
+
+// Unused returns the interactions that were never replayed
+func (r *ReplayCommandRunner) Unused() []CommandInteraction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []CommandInteraction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Unmatched returns the command lines that had no matching interaction
+func (r *ReplayCommandRunner) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.unmatched)
+}
+
+// Verify returns an error listing every unmatched call and unused interaction
+func (r *ReplayCommandRunner) Verify() error {
+	var errs []error
+	for _, call := range r.Unmatched() {
+		errs = append(errs, fmt.Errorf("unmatched command: %s", call))
+	}
+	for _, interaction := range r.Unused() {
+		errs = append(errs, fmt.Errorf("unused interaction: %s", interaction))
+	}
+	return errors.Join(errs...)
+}
+
+// AssertExpectations fails t when Verify reports a problem
+func (r *ReplayCommandRunner) AssertExpectations(t mock.TestingT) bool {
+	if err := r.Verify(); err != nil {
+		t.Errorf("command replay mismatch:\n%s", err)
+		return false
+	}
+	return true
+}
+
+func (r *ReplayCommandRunner) replayCombined(cmd string, args []string, dir string) (string, error) {
+	interaction, err := r.match(cmd, args, dir)
+	if err != nil {
+		return "", err
+	}
+	return interaction.Output + interaction.Stderr, interaction.err()
+}
+
+// match finds and consumes the interaction for a call
+func (r *ReplayCommandRunner) match(cmd string, args []string, dir string) (CommandInteraction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call := CommandInteraction{Command: cmd, Args: args, Dir: dir}.String()
+	switch r.mode {
+	case ReplayStrict:
+		if r.next >= len(r.interactions) {
+			r.unmatched = append(r.unmatched, call)
+			return CommandInteraction{}, fmt.Errorf("%w: %s (all %d interactions already replayed)", ErrUnmatchedCommand, call, len(r.interactions))
+		}
+		expected := r.interactions[r.next]
+		if !expected.matches(cmd, args, dir) {
+			r.unmatched = append(r.unmatched, call)
+			return CommandInteraction{}, fmt.Errorf("%w: %s (expected interaction %d: %s)", ErrUnmatchedCommand, call, r.next+1, expected)
+		}
+		r.used[r.next] = true
+		r.next++
+		return expected, nil
+	default:
+		for i, interaction := range r.interactions {
+			if !r.used[i] && interaction.matches(cmd, args, dir) {
+				r.used[i] = true
+				return interaction, nil
+			}
+		}
+		r.unmatched = append(r.unmatched, call)
+		return CommandInteraction{}, fmt.Errorf("%w: %s", ErrUnmatchedCommand, call)
+	}
+}
+
+func (i CommandInteraction) err() error {
+	if i.Error == "" {
+		return nil
+	}
+	return errors.New(i.Error)
+}
+
+// deliver replays recorded output to a line handler and raw writer
+func deliver(handler command.LineHandler, w io.Writer, stream command.OutputStream, output string) {
+	if w != nil && output != "" {
+		_, _ = io.WriteString(w, output)
+	}
+	if handler != nil {
+		replayLines(handler, stream, output)
+	}
+}
@@ -0,0 +1,124 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/ndizazzo/task-engine/command"
+	"github.com/stretchr/testify/suite"
+)
+
+// CommandFixtureTestSuite tests the recording and replaying command runners
+type CommandFixtureTestSuite struct {
+	suite.Suite
+	fixture string
+}
+
+func TestCommandFixtureTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandFixtureTestSuite))
+}
+
+// SetupTest records a small fixture with the real command runner
+func (suite *CommandFixtureTestSuite) SetupTest() {
+	suite.fixture = filepath.Join(suite.T().TempDir(), "commands.json")
+	dir := suite.T().TempDir()
+
+	recorder := NewRecordingCommandRunner(suite.fixture, nil)
+	output, err := recorder.RunCommand("echo", "hello")
+	suite.Require().NoError(err)
+	suite.Equal("hello", output)
+	_, err = recorder.RunCommandInDirWithContext(context.Background(), dir, "pwd")
+	suite.Require().NoError(err)
+	_, err = recorder.RunCommandWithOptions(context.Background(), command.Cmd{
+		Name: "sh",
+		Args: []string{"-c", "echo out; echo err 1>&2; exit 3"},
+	})
+	suite.Require().Error(err)
+	suite.Require().NoError(recorder.Save())
+
+	interactions := recorder.Interactions()
+	suite.Require().Len(interactions, 3)
+	suite.Equal(dir, interactions[1].Dir)
+	suite.Equal(dir, interactions[1].Output)
+	suite.Equal(3, interactions[2].ExitCode)
+	suite.Equal("err\n", interactions[2].Stderr)
+	suite.Equal("exit status 3", interactions[2].Error)
+}
+
+func (suite *CommandFixtureTestSuite) TestStrictReplay() {
+	replay, err := LoadReplayCommandRunner(suite.fixture, ReplayStrict)
+	suite.Require().NoError(err)
+	interactions, err := LoadCommandFixture(suite.fixture)
+	suite.Require().NoError(err)
+
+	output, err := replay.RunCommandWithContext(context.Background(), "echo", "hello")
+	suite.NoError(err)
+	suite.Equal("hello", output)
+
+	// Out of order calls are rejected and reported
+	_, err = replay.RunCommand("sh", "-c", "echo out; echo err 1>&2; exit 3")
+	suite.ErrorIs(err, ErrUnmatchedCommand)
+	suite.ErrorContains(err, "expected interaction 2: pwd (in "+interactions[1].Dir+")")
+
+	output, err = replay.RunCommandInDir(interactions[1].Dir, "pwd")
+	suite.NoError(err)
+	suite.Equal(interactions[1].Dir, output)
+
+	var lines []string
+	result, err := replay.RunCommandWithOptions(context.Background(), command.Cmd{
+		Name:   "sh",
+		Args:   []string{"-c", "echo out; echo err 1>&2; exit 3"},
+		OnLine: func(stream command.OutputStream, line string) { lines = append(lines, string(stream)+":"+line) },
+	})
+	suite.EqualError(err, "exit status 3")
+	suite.Equal(command.CommandResult{Stdout: "out\n", Stderr: "err\n", ExitCode: 3}, result)
+	suite.Equal([]string{"stdout:out", "stderr:err"}, lines)
+
+	suite.Empty(replay.Unused())
+	suite.Equal([]string{"sh -c echo out; echo err 1>&2; exit 3"}, replay.Unmatched())
+	suite.ErrorContains(replay.Verify(), "unmatched command: sh -c")
+}
+
+func (suite *CommandFixtureTestSuite) TestLenientReplay() {
+	replay, err := LoadReplayCommandRunner(suite.fixture, ReplayLenient)
+	suite.Require().NoError(err)
+
+	var streamed []string
+	output, err := replay.RunCommandStream(context.Background(), command.StreamOptions{
+		OnLine: func(stream command.OutputStream, line string) { streamed = append(streamed, line) },
+	}, "echo", "hello")
+	suite.NoError(err)
+	suite.Equal("hello", output)
+	suite.Equal([]string{"hello"}, streamed)
+
+	// Each interaction is served once
+	_, err = replay.RunCommand("echo", "hello")
+	suite.ErrorIs(err, ErrUnmatchedCommand)
+
+	unused := replay.Unused()
+	suite.Require().Len(unused, 2)
+	suite.Equal("pwd", unused[0].Command)
+
+	recorder := &recordingT{}
+	suite.False(replay.AssertExpectations(recorder))
+	suite.Contains(recorder.errors[0], "unmatched command: echo hello")
+	suite.Contains(recorder.errors[0], "unused interaction: pwd")
+}
+
+func (suite *CommandFixtureTestSuite) TestLoadMissingFixture() {
+	_, err := LoadReplayCommandRunner(filepath.Join(suite.T().TempDir(), "missing.json"), ReplayStrict)
+	suite.ErrorContains(err, "failed to read command fixture")
+}
+
+// recordingT captures assertion failures
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Logf(format string, args ...interface{}) {}
+func (t *recordingT) FailNow()                                {}
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"strings"
 
 	engine "github.com/ndizazzo/task-engine"
+	"github.com/ndizazzo/task-engine/command"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -66,6 +68,82 @@ func (m *MockCommandRunner) RunCommandInDirWithContext(ctx context.Context, work
 	return ret.String(0), ret.Error(1)
 }
 
+// RunCommandWithOptions mocks the RunCommandWithOptions method. When an
+// expectation for "RunCommandWithOptions" is registered it is matched on ctx
+// and cmd and must return a command.CommandResult; otherwise the call is
+// served by the legacy mocked methods through command.RunBuffered, so tests
+// written against RunCommandWithContext keep working.
+func (m *MockCommandRunner) RunCommandWithOptions(ctx context.Context, cmd command.Cmd) (command.CommandResult, error) {
+	if !hasExpectation(&m.Mock, "RunCommandWithOptions") {
+		return command.RunBuffered(ctx, m, cmd)
+	}
+	return m.calledWithOptions(ctx, cmd)
+}
+
+func (m *MockCommandRunner) calledWithOptions(ctx context.Context, cmd command.Cmd) (command.CommandResult, error) {
+	ret := m.MethodCalled("RunCommandWithOptions", ctx, cmd)
+	result, _ := ret.Get(0).(command.CommandResult)
+	if cmd.OnLine != nil {
+		replayLines(cmd.OnLine, command.Stdout, result.Stdout)
+		replayLines(cmd.OnLine, command.Stderr, result.Stderr)
+	}
+	return result, ret.Error(1)
+}
+
+// hasExpectation reports whether any expectation was registered for method
+func hasExpectation(m *mock.Mock, method string) bool {
+	for _, call := range m.ExpectedCalls {
+		if call.Method == method {
+			return true
+		}
+	}
+	return false
+}
+
+// replayLines delivers mocked output to a line handler
+func replayLines(handler command.LineHandler, stream command.OutputStream, output string) {
+	if output == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		handler(stream, line)
+	}
+}
+
+// MockStreamingCommandRunner is a MockCommandRunner that also implements
+// command.StreamingCommandRunner. The mocked output is replayed line by line
+// to opts.OnLine as stdout.
+type MockStreamingCommandRunner struct {
+	MockCommandRunner
+}
+
+// RunCommandStream mocks the RunCommandStream method; expectations are matched
+// on ctx, command and args (the options are not part of the match)
+func (m *MockStreamingCommandRunner) RunCommandStream(ctx context.Context, opts command.StreamOptions, cmd string, args ...string) (string, error) {
+	arguments := make([]interface{}, len(args)+2)
+	arguments[0] = ctx
+	arguments[1] = cmd
+	for i, arg := range args {
+		arguments[i+2] = arg
+	}
+
+	ret := m.Called(arguments...)
+	output := ret.String(0)
+	if opts.OnLine != nil {
+		replayLines(opts.OnLine, command.Stdout, output)
+	}
+	return output, ret.Error(1)
+}
+
+// RunCommandWithOptions mocks RunCommandWithOptions like MockCommandRunner,
+// falling back to RunCommandStream expectations
+func (m *MockStreamingCommandRunner) RunCommandWithOptions(ctx context.Context, cmd command.Cmd) (command.CommandResult, error) {
+	if !hasExpectation(&m.Mock, "RunCommandWithOptions") {
+		return command.RunBuffered(ctx, m, cmd)
+	}
+	return m.calledWithOptions(ctx, cmd)
+}
+
 // MockActionParameter is a mock implementation of ActionParameter for testing
 type MockActionParameter struct {
 	ResolveFunc func(ctx context.Context, gc *engine.GlobalContext) (interface{}, error)
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -105,6 +106,42 @@ func TestEnhancedTaskManagerMock(t *testing.T) {
 		mockTM.AssertExpectations(t)
 	})
 
+	t.Run("Shutdown clears running tasks", func(t *testing.T) {
+		mockTM := NewEnhancedTaskManagerMock()
+		summary := task_engine.ShutdownSummary{Drained: []string{"task1"}}
+
+		mockTM.On("RunTask", "task1").Return(nil)
+		mockTM.On("Shutdown", mock.Anything).Return(summary, nil)
+		mockTM.On("GetRunningTasks").Return(nil)
+
+		require.NoError(t, mockTM.RunTask("task1"))
+		result, err := mockTM.Shutdown(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, summary, result)
+		assert.Empty(t, mockTM.GetRunningTasks())
+
+		mockTM.AssertExpectations(t)
+	})
+
+	t.Run("GetTasksInState follows pause state", func(t *testing.T) {
+		mockTM := NewEnhancedTaskManagerMock()
+		for _, id := range []string{"task1", "task2", "task3"} {
+			mockTM.On("AddTask", mock.Anything).Return(nil).Once()
+			require.NoError(t, mockTM.AddTask(&task_engine.Task{ID: id}))
+		}
+		mockTM.On("RunTask", mock.Anything).Return(nil)
+		mockTM.On("PauseTask", "task2").Return(nil)
+		mockTM.On("GetTasksInState", mock.Anything).Return(nil)
+
+		require.NoError(t, mockTM.RunTask("task1"))
+		require.NoError(t, mockTM.RunTask("task2"))
+		require.NoError(t, mockTM.PauseTask("task2"))
+
+		assert.Equal(t, []string{"task1"}, mockTM.GetTasksInState(task_engine.TaskStateRunning))
+		assert.Equal(t, []string{"task2"}, mockTM.GetTasksInState(task_engine.TaskStatePaused))
+		assert.Equal(t, []string{"task2", "task3"}, mockTM.GetTasksInState(task_engine.TaskStatePaused, task_engine.TaskStateIdle))
+	})
+
 	t.Run("GetRunningTasks with Call Tracking", func(t *testing.T) {
 		mockTM := NewEnhancedTaskManagerMock()
 
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+	"slices"
 	"sync"
 	"time"
 
@@ -8,8 +10,17 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-// Ensure EnhancedTaskManagerMock implements TaskManagerInterface
-var _ task_engine.TaskManagerInterface = (*EnhancedTaskManagerMock)(nil)
+// Ensure EnhancedTaskManagerMock implements TaskManagerInterface and the
+// optional task manager interfaces
+var (
+	_ task_engine.TaskManagerInterface = (*EnhancedTaskManagerMock)(nil)
+	_ task_engine.TaskInputRunner      = (*EnhancedTaskManagerMock)(nil)
+	_ task_engine.TaskStopper          = (*EnhancedTaskManagerMock)(nil)
+	_ task_engine.TaskShutdowner       = (*EnhancedTaskManagerMock)(nil)
+	_ task_engine.TaskPauser           = (*EnhancedTaskManagerMock)(nil)
+	_ task_engine.TaskStateReporter    = (*EnhancedTaskManagerMock)(nil)
+	_ task_engine.TaskProgressReporter = (*EnhancedTaskManagerMock)(nil)
+)
 
 // EnhancedTaskManagerMock provides comprehensive mocking capabilities
 type EnhancedTaskManagerMock struct {
@@ -19,6 +30,7 @@ type EnhancedTaskManagerMock struct {
 	// State tracking
 	tasks        map[string]*task_engine.Task
 	runningTasks map[string]bool
+	pausedTasks  map[string]bool
 	taskResults  map[string]interface{}
 	taskErrors   map[string]error
 	taskTiming   map[string]time.Duration
@@ -37,6 +49,7 @@ func NewEnhancedTaskManagerMock() *EnhancedTaskManagerMock {
 	return &EnhancedTaskManagerMock{
 		tasks:          make(map[string]*task_engine.Task),
 		runningTasks:   make(map[string]bool),
+		pausedTasks:    make(map[string]bool),
 		taskResults:    make(map[string]interface{}),
 		taskErrors:     make(map[string]error),
 		taskTiming:     make(map[string]time.Duration),
@@ -72,6 +85,19 @@ func (m *EnhancedTaskManagerMock) RunTask(taskID string) error {
 	return args.Error(0)
 }
 
+// RunTaskWithInputs mocks RunTaskWithInputs with the same state tracking as RunTask
+func (m *EnhancedTaskManagerMock) RunTaskWithInputs(taskID string, inputs map[string]interface{}) error {
+	args := m.Called(taskID, inputs)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runningTasks[taskID] = true
+	m.runTaskCalls = append(m.runTaskCalls, taskID)
+
+	return args.Error(0)
+}
+
 // StopTask mocks StopTask with state tracking
 func (m *EnhancedTaskManagerMock) StopTask(taskID string) error {
 	args := m.Called(taskID)
@@ -80,6 +106,21 @@ func (m *EnhancedTaskManagerMock) StopTask(taskID string) error {
 	defer m.mu.Unlock()
 
 	delete(m.runningTasks, taskID)
+	delete(m.pausedTasks, taskID)
+	m.stopTaskCalls = append(m.stopTaskCalls, taskID)
+
+	return args.Error(0)
+}
+
+// StopTaskAndWait mocks StopTaskAndWait with the same state tracking as StopTask
+func (m *EnhancedTaskManagerMock) StopTaskAndWait(taskID string, timeout time.Duration) error {
+	args := m.Called(taskID, timeout)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.runningTasks, taskID)
+	delete(m.pausedTasks, taskID)
 	m.stopTaskCalls = append(m.stopTaskCalls, taskID)
 
 	return args.Error(0)
@@ -94,6 +135,77 @@ func (m *EnhancedTaskManagerMock) StopAllTasks() {
 
 	m.stopAllCalls++
 	m.runningTasks = make(map[string]bool)
+	m.pausedTasks = make(map[string]bool)
+}
+
+// Shutdown mocks Shutdown; like StopAllTasks it clears the tracked tasks
+func (m *EnhancedTaskManagerMock) Shutdown(ctx context.Context) (task_engine.ShutdownSummary, error) {
+	args := m.Called(ctx)
+
+	m.mu.Lock()
+	m.runningTasks = make(map[string]bool)
+	m.pausedTasks = make(map[string]bool)
+	m.mu.Unlock()
+
+	summary, _ := args.Get(0).(task_engine.ShutdownSummary)
+	return summary, args.Error(1)
+}
+
+// PauseTask mocks PauseTask with state tracking
+func (m *EnhancedTaskManagerMock) PauseTask(taskID string) error {
+	args := m.Called(taskID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if args.Error(0) == nil {
+		m.pausedTasks[taskID] = true
+	}
+	return args.Error(0)
+}
+
+// ResumeTask mocks ResumeTask with state tracking
+func (m *EnhancedTaskManagerMock) ResumeTask(taskID string) error {
+	args := m.Called(taskID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if args.Error(0) == nil {
+		delete(m.pausedTasks, taskID)
+	}
+	return args.Error(0)
+}
+
+// GetTaskState mocks GetTaskState; without an explicit return value the state
+// is derived from the tracked running and paused tasks
+func (m *EnhancedTaskManagerMock) GetTaskState(taskID string) (task_engine.TaskState, error) {
+	args := m.Called(taskID)
+
+	if state, ok := args.Get(0).(task_engine.TaskState); ok && state != "" {
+		return state, args.Error(1)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	switch {
+	case m.pausedTasks[taskID]:
+		return task_engine.TaskStatePaused, args.Error(1)
+	case m.runningTasks[taskID]:
+		return task_engine.TaskStateRunning, args.Error(1)
+	default:
+		return task_engine.TaskStateIdle, args.Error(1)
+	}
+}
+
+// GetTaskProgress mocks GetTaskProgress
+func (m *EnhancedTaskManagerMock) GetTaskProgress(taskID string) (task_engine.TaskProgress, error) {
+	args := m.Called(taskID)
+	if progress, ok := args.Get(0).(task_engine.TaskProgress); ok {
+		return progress, args.Error(1)
+	}
+	return task_engine.TaskProgress{TaskID: taskID}, args.Error(1)
 }
 
 // GetRunningTasks returns the current running tasks
@@ -118,6 +230,35 @@ func (m *EnhancedTaskManagerMock) GetRunningTasks() []string {
 	return running
 }
 
+// GetTasksInState mocks GetTasksInState; without an explicit return value the
+// tasks are selected by the tracked running and paused state
+func (m *EnhancedTaskManagerMock) GetTasksInState(states ...task_engine.TaskState) []string {
+	args := m.Called(states)
+
+	if taskIDs, ok := args.Get(0).([]string); ok {
+		return taskIDs
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	taskIDs := []string{}
+	for taskID := range m.tasks {
+		state := task_engine.TaskStateIdle
+		switch {
+		case m.pausedTasks[taskID]:
+			state = task_engine.TaskStatePaused
+		case m.runningTasks[taskID]:
+			state = task_engine.TaskStateRunning
+		}
+		if slices.Contains(states, state) {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	slices.Sort(taskIDs)
+	return taskIDs
+}
+
 // IsTaskRunning checks if a specific task is running
 func (m *EnhancedTaskManagerMock) IsTaskRunning(taskID string) bool {
 	args := m.Called(taskID)
@@ -198,6 +339,7 @@ func (m *EnhancedTaskManagerMock) ResetState() {
 
 	m.tasks = make(map[string]*task_engine.Task)
 	m.runningTasks = make(map[string]bool)
+	m.pausedTasks = make(map[string]bool)
 	m.taskResults = make(map[string]interface{})
 	m.taskErrors = make(map[string]error)
 	m.taskTiming = make(map[string]time.Duration)
@@ -339,6 +481,7 @@ func (m *EnhancedTaskManagerMock) ClearState() {
 
 	m.tasks = make(map[string]*task_engine.Task)
 	m.runningTasks = make(map[string]bool)
+	m.pausedTasks = make(map[string]bool)
 	m.taskResults = make(map[string]interface{})
 	m.taskErrors = make(map[string]error)
 	m.taskTiming = make(map[string]time.Duration)
@@ -185,6 +185,20 @@ func (tm *TestableTaskManager) RunTask(taskID string) error {
 	return tm.TaskManager.RunTask(taskID)
 }
 
+// Override RunTaskWithInputs to include hooks and call tracking
+func (tm *TestableTaskManager) RunTaskWithInputs(taskID string, inputs map[string]interface{}) error {
+	tm.mu.Lock()
+	tm.taskStartedCalls = append(tm.taskStartedCalls, taskID)
+	hook := tm.onTaskStarted
+	tm.mu.Unlock()
+
+	if hook != nil {
+		hook(taskID)
+	}
+
+	return tm.TaskManager.RunTaskWithInputs(taskID, inputs)
+}
+
 // Override StopTask to include hooks and call tracking
 func (tm *TestableTaskManager) StopTask(taskID string) error {
 	// Track the call and execute hook (protected by our lock)
@@ -202,6 +216,20 @@ func (tm *TestableTaskManager) StopTask(taskID string) error {
 	return tm.TaskManager.StopTask(taskID)
 }
 
+// Override StopTaskAndWait to include hooks and call tracking
+func (tm *TestableTaskManager) StopTaskAndWait(taskID string, timeout time.Duration) error {
+	tm.mu.Lock()
+	tm.taskStoppedCalls = append(tm.taskStoppedCalls, taskID)
+	hook := tm.onTaskStopped
+	tm.mu.Unlock()
+
+	if hook != nil {
+		hook(taskID)
+	}
+
+	return tm.TaskManager.StopTaskAndWait(taskID, timeout)
+}
+
 // SimulateTaskCompletion allows tests to simulate task completion
 func (tm *TestableTaskManager) SimulateTaskCompletion(taskID string, err error) {
 	// Get hook and execute it (protected by our lock)
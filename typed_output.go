@@ -0,0 +1,145 @@
+package task_engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ActionWithTypedOutput is implemented by actions that publish their results
+// as a concrete struct in addition to the GetOutput map. Tasks store the value
+// in the GlobalContext, where TypedActionOutput references read it back
+// without string keys or type assertions.
+type ActionWithTypedOutput[O any] interface {
+	ActionInterface
+	TypedOutput() O
+}
+
+// TypedOutputProvider is implemented by action wrappers that can report the
+// typed output of the action they wrap. Action[T] implements it for every
+// wrapped action with a TypedOutput method.
+type TypedOutputProvider interface {
+	TypedOutputValue() (interface{}, bool)
+}
+
+// TypedOutputValue returns the result of the wrapped action's TypedOutput
+// method, or false when it has none
+func (a *Action[T]) TypedOutputValue() (interface{}, bool) {
+	v := reflect.ValueOf(any(a.Wrapped))
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, false
+	}
+	method := v.MethodByName("TypedOutput")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil, false
+	}
+	return method.Call(nil)[0].Interface(), true
+}
+
+// StoreTypedActionOutput stores the typed output of an action
+func (gc *GlobalContext) StoreTypedActionOutput(actionID string, output interface{}) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.TypedActionOutputs == nil {
+		// GlobalContexts built as literals predate this map
+		gc.TypedActionOutputs = make(map[string]interface{})
+	}
+	gc.TypedActionOutputs[actionID] = output
+}
+
+// GetTypedActionOutput returns the stored typed output of an action
+func (gc *GlobalContext) GetTypedActionOutput(actionID string) (interface{}, bool) {
+	gc.mu.RLock()
+	defer gc.mu.RUnlock()
+	output, ok := gc.TypedActionOutputs[actionID]
+	return output, ok
+}
+
+// TypedActionOutput references the typed output O of an action. Unlike
+// ActionOutputParameter, Resolve returns O itself.
+type TypedActionOutput[O any] struct {
+	ActionID string // ID of the action to reference
+}
+
+// OutputOf returns a reference to the typed output of action. The type
+// argument is checked against the wrapped action at compile time:
+//
+//	ps := engine.OutputOf[docker.DockerPsOutput](psAction)
+func OutputOf[O any, T ActionWithTypedOutput[O]](action *Action[T]) TypedActionOutput[O] {
+	return TypedActionOutput[O]{ActionID: action.ID}
+}
+
+// Resolve returns the referenced action's typed output. An action whose
+// GetOutput already returns O is accepted as well. A nil globalContext falls
+// back to the one carried by ctx.
+func (r TypedActionOutput[O]) Resolve(ctx context.Context, globalContext *GlobalContext) (O, error) {
+	var zero O
+	if r.ActionID == "" {
+		return zero, fmt.Errorf("TypedActionOutput: ActionID cannot be empty")
+	}
+	if globalContext == nil {
+		globalContext, _ = ctx.Value(GlobalContextKey).(*GlobalContext)
+	}
+	if globalContext == nil {
+		return zero, fmt.Errorf("TypedActionOutput: no global context to resolve action '%s'", r.ActionID)
+	}
+	output, exists := globalContext.GetTypedActionOutput(r.ActionID)
+	if !exists {
+		output, exists = globalContext.GetActionOutput(r.ActionID)
+	}
+	if !exists {
+		return zero, fmt.Errorf("TypedActionOutput: action '%s' not found in context", r.ActionID)
+	}
+	typed, ok := output.(O)
+	if !ok {
+		return zero, fmt.Errorf("TypedActionOutput: action '%s' output is %T, not %T", r.ActionID, output, zero)
+	}
+	return typed, nil
+}
+
+// Param adapts the reference to an ActionParameter for actions that take one
+func (r TypedActionOutput[O]) Param() ActionParameter {
+	return typedParameter[O]{resolve: r.Resolve}
+}
+
+// TypedOutputField selects a field of an action's typed output through an
+// accessor function, so the field is checked by the compiler instead of being
+// named by a string key.
+type TypedOutputField[O, F any] struct {
+	Output TypedActionOutput[O]
+	Field  func(O) F
+}
+
+// OutputField returns a reference to one field of a typed output:
+//
+//	engine.OutputField(ps, func(o docker.DockerPsOutput) []docker.Container { return o.Containers })
+func OutputField[O, F any](output TypedActionOutput[O], field func(O) F) TypedOutputField[O, F] {
+	return TypedOutputField[O, F]{Output: output, Field: field}
+}
+
+// Resolve resolves the typed output and returns the selected field
+func (f TypedOutputField[O, F]) Resolve(ctx context.Context, globalContext *GlobalContext) (F, error) {
+	var zero F
+	if f.Field == nil {
+		return zero, fmt.Errorf("TypedOutputField: Field cannot be nil")
+	}
+	output, err := f.Output.Resolve(ctx, globalContext)
+	if err != nil {
+		return zero, err
+	}
+	return f.Field(output), nil
+}
+
+// Param adapts the reference to an ActionParameter for actions that take one
+func (f TypedOutputField[O, F]) Param() ActionParameter {
+	return typedParameter[F]{resolve: f.Resolve}
+}
+
+// typedParameter exposes a typed reference through the ActionParameter interface
+type typedParameter[V any] struct {
+	resolve func(ctx context.Context, globalContext *GlobalContext) (V, error)
+}
+
+func (p typedParameter[V]) Resolve(ctx context.Context, globalContext *GlobalContext) (interface{}, error) {
+	return p.resolve(ctx, globalContext)
+}
@@ -0,0 +1,69 @@
+package task_engine_test
+
+import (
+	"context"
+	"testing"
+
+	task_engine "github.com/ndizazzo/task-engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type releaseInfo struct {
+	Version string
+	Hosts   []string
+}
+
+// releaseAction publishes a releaseInfo as its typed output
+type releaseAction struct {
+	task_engine.BaseAction
+	info releaseInfo
+}
+
+func (a *releaseAction) Execute(ctx context.Context) error {
+	a.info = releaseInfo{Version: "1.4.2", Hosts: []string{"web-1", "web-2"}}
+	return nil
+}
+
+func (a *releaseAction) GetOutput() interface{} {
+	return map[string]interface{}{"version": a.info.Version, "changed": false}
+}
+
+func (a *releaseAction) TypedOutput() releaseInfo {
+	return a.info
+}
+
+func TestTypedActionOutput(t *testing.T) {
+	release := &task_engine.Action[*releaseAction]{ID: "release", Wrapped: &releaseAction{}}
+	version := task_engine.OutputOf[releaseInfo](release)
+	hosts := task_engine.OutputField(version, func(r releaseInfo) []string { return r.Hosts })
+
+	reader := &inputReadingAction{Params: map[string]task_engine.ActionParameter{
+		"hosts": hosts.Param(),
+	}}
+	task := &task_engine.Task{
+		ID: "publish",
+		Actions: []task_engine.ActionWrapper{
+			release,
+			&task_engine.Action[*inputReadingAction]{ID: "read", Wrapped: reader},
+		},
+		Logger: NewDiscardLogger(),
+	}
+	gc := task_engine.NewGlobalContext()
+	require.NoError(t, task.RunWithContext(context.Background(), gc))
+
+	info, err := version.Resolve(context.Background(), gc)
+	require.NoError(t, err)
+	assert.Equal(t, releaseInfo{Version: "1.4.2", Hosts: []string{"web-1", "web-2"}}, info)
+	assert.Equal(t, []string{"web-1", "web-2"}, reader.Resolved["hosts"])
+
+	// The map output is stored unchanged alongside the typed one
+	v, err := task_engine.ActionOutputFieldAs[string](gc, "release", "version")
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.2", v)
+
+	_, err = task_engine.TypedActionOutput[string]{ActionID: "release"}.Resolve(context.Background(), gc)
+	assert.ErrorContains(t, err, "not string")
+	_, err = task_engine.TypedActionOutput[releaseInfo]{ActionID: "missing"}.Resolve(context.Background(), gc)
+	assert.ErrorContains(t, err, "not found in context")
+}